@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"task_manager/Delivery/grpc/pb"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	usecases "task_manager/Usecases"
+	"task_manager/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// access is who may call a method
+type access int
+
+const (
+	authenticated access = iota // any active account; the default
+	public                      // no credentials needed
+	adminOnly                   // accounts with the admin role
+)
+
+// methodAccess lists the methods that differ from the default, matching the
+// HTTP routes they mirror
+var methodAccess = map[string]access{
+	pb.UserService_Login_FullMethodName: public,
+
+	pb.TaskService_CreateTask_FullMethodName:  adminOnly,
+	pb.TaskService_UpdateTask_FullMethodName:  adminOnly,
+	pb.TaskService_DeleteTask_FullMethodName:  adminOnly,
+	pb.UserService_GetUser_FullMethodName:     adminOnly,
+	pb.UserService_PromoteUser_FullMethodName: adminOnly,
+}
+
+// identityKey is the context key for the authenticated caller
+type identityKey struct{}
+
+// callerFrom returns the account the call was authenticated as
+func callerFrom(ctx context.Context) entities.User {
+	user, _ := ctx.Value(identityKey{}).(entities.User)
+	return user
+}
+
+// authenticator checks the access token of each call like the HTTP
+// AuthMiddleware. The account is looked up on every call so that disabled
+// users and role changes take effect without waiting for the token to expire.
+type authenticator struct {
+	tokenService interfaces.TokenService
+	userUsecase  usecases.UserUsecase
+}
+
+func (a *authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate returns ctx carrying the caller's account and scoped to its
+// organization, or the error to answer with when the caller may not use the
+// method
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	level := methodAccess[method]
+	if level == public {
+		return ctx, nil
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		return nil, errors.AuthenticationRequiredError{}
+	}
+	email, _, organizationID, err := a.tokenService.ValidateToken(token)
+	if err != nil {
+		return nil, errors.InvalidTokenError{}
+	}
+	user, err := a.userUsecase.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, errors.InvalidTokenError{}
+	}
+	if organizationID != "" && organizationID != user.OrganizationID {
+		return nil, errors.InvalidTokenError{}
+	}
+	if !user.IsActive() {
+		return nil, errors.AccountDisabledError{}
+	}
+	if level == adminOnly && !user.IsAdmin() {
+		return nil, errors.ForbiddenError{Message: "admin access required"}
+	}
+
+	ctx = utils.ContextWithOrganization(ctx, user.OrganizationID)
+	return context.WithValue(ctx, identityKey{}, user), nil
+}
+
+// bearerToken reads the token from the call's authorization metadata
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return "", false
+	}
+	return strings.TrimPrefix(values[0], "Bearer "), true
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
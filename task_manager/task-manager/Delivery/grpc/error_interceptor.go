@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"log/slog"
+	"net/http"
+	"task_manager/Domain/errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain names the service in ErrorInfo details
+const errorDomain = "task_manager"
+
+// errorReporter turns the errors returned by handlers and interceptors into
+// gRPC statuses, the counterpart of the HTTP ErrorMiddleware:
+//
+//   - errors.CodedError values get the gRPC code closest to their HTTP status,
+//     and an ErrorInfo detail whose reason is the problem code
+//   - errors.RetryableError values also carry a RetryInfo detail
+//   - anything else is Internal, with the details kept in the logs
+type errorReporter struct {
+	logger *slog.Logger
+}
+
+func (r *errorReporter) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, r.toStatus(info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+func (r *errorReporter) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return r.toStatus(info.FullMethod, err)
+	}
+	return nil
+}
+
+func (r *errorReporter) toStatus(method string, err error) error {
+	// Already a status, e.g. from a failed Send or a cancelled call
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var coded errors.CodedError
+	if !stderrors.As(err, &coded) {
+		r.logger.Error("gRPC call failed", "method", method, "error", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: coded.Code(), Domain: errorDomain}}
+	var retryable errors.RetryableError
+	if stderrors.As(err, &retryable) {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryable.RetryAfterDuration())})
+	}
+
+	st := status.New(codeFor(coded.Status()), coded.Error())
+	if withDetails, detailErr := st.WithDetails(details...); detailErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// codeFor maps an HTTP status to the matching gRPC code
+func codeFor(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
+	}
+}
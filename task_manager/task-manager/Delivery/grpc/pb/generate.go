@@ -0,0 +1,5 @@
+// Package pb holds the protocol buffer messages and gRPC services of the
+// task_manager gRPC API, generated from task_manager.proto.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative task_manager.proto
@@ -0,0 +1,51 @@
+// Package grpc serves the task and user use cases over gRPC, next to the Gin
+// HTTP API. Callers authenticate with the same access tokens, sent as
+// "authorization: Bearer <token>" metadata, and the same routes are limited
+// to admins. The service definitions live in the pb package.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"task_manager/Delivery/grpc/pb"
+	"task_manager/Domain/interfaces"
+	usecases "task_manager/Usecases"
+
+	"google.golang.org/grpc"
+)
+
+// NewServer creates a gRPC server exposing TaskService and UserService. Errors
+// returned by the services are converted to statuses by the outermost
+// interceptor, so authentication failures are reported the same way.
+func NewServer(taskUsecase usecases.TaskUsecase, userUsecase usecases.UserUsecase, tokenService interfaces.TokenService, logger *slog.Logger) *grpc.Server {
+	auth := &authenticator{tokenService: tokenService, userUsecase: userUsecase}
+	errs := &errorReporter{logger: logger}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(errs.unary, auth.unary),
+		grpc.ChainStreamInterceptor(errs.stream, auth.stream),
+	)
+	pb.RegisterTaskServiceServer(server, &taskServer{service: taskUsecase})
+	pb.RegisterUserServiceServer(server, &userServer{service: userUsecase})
+	return server
+}
+
+// Shutdown returns a closer that stops the server gracefully, letting in-flight
+// calls finish. Calls still running when ctx is done are cancelled.
+func Shutdown(server *grpc.Server) func(context.Context) error {
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"task_manager/Delivery/grpc/pb"
+	"task_manager/Domain/entities"
+	domainErrors "task_manager/Domain/errors"
+	"task_manager/Infrastructure/services"
+	usecases "task_manager/Usecases"
+	"task_manager/config"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	adminEmail   = "admin@example.com"
+	userEmail    = "user@example.com"
+	testPassword = "correct horse"
+	testTaskID   = "507f1f77bcf86cd799439011"
+)
+
+// testServer is the gRPC server with real use cases over mocked repositories,
+// reached through an in-memory connection
+type testServer struct {
+	users *mocks.MockUserRepository
+	tasks *mocks.MockTaskRepository
+	conn  *grpc.ClientConn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ts := &testServer{
+		users: mocks.NewMockUserRepository(ctrl),
+		tasks: mocks.NewMockTaskRepository(ctrl),
+	}
+
+	tokenService, err := services.NewJWTService(nil, &config.JWTConfig{Algorithm: config.JWTAlgorithmHS256, TokenTTL: time.Hour}, "grpc-test-secret", logger)
+	require.NoError(t, err)
+	throttle := services.NewMemoryLoginThrottle(100, time.Second, time.Minute, time.Minute)
+	userUsecase := usecases.NewUserUsecase(ts.users, ts.tasks, mocks.NewMockAPIKeyRepository(ctrl), tokenService, mocks.NewMockMailer(ctrl), throttle, services.NewPrometheusMetrics(), usecases.DefaultAuthSettings(), logger)
+
+	server := NewServer(usecases.NewTaskUsecase(ts.tasks), userUsecase, tokenService, logger)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	ts.conn, err = grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { ts.conn.Close() })
+
+	// A cheap hash keeps logins fast
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	admin := entities.User{ID: "user1", Name: "Admin", Email: adminEmail, Password: string(hash), Role: "admin"}
+	user := entities.User{ID: "user2", Name: "User", Email: userEmail, Password: string(hash), Role: "user"}
+	ts.users.EXPECT().GetUserByEmail(gomock.Any(), adminEmail).Return(admin, nil).AnyTimes()
+	ts.users.EXPECT().GetUserByEmail(gomock.Any(), userEmail).Return(user, nil).AnyTimes()
+	return ts
+}
+
+// login returns a context that authenticates calls as email
+func (ts *testServer) login(t *testing.T, email string) context.Context {
+	resp, err := pb.NewUserServiceClient(ts.conn).Login(context.Background(), &pb.LoginRequest{Email: email, Password: testPassword})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.GetToken())
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+resp.GetToken())
+}
+
+// errorReason returns the problem code carried in the status' ErrorInfo
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestTaskService(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := ts.login(t, adminEmail)
+	client := pb.NewTaskServiceClient(ts.conn)
+
+	due := time.Date(2027, time.March, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []entities.Task{
+		{ID: testTaskID, Title: "Write the gRPC API", DueDate: due, Status: "Pending", CreatedBy: adminEmail},
+		{ID: "507f1f77bcf86cd799439012", Title: "Document it", Status: "Completed", CreatedBy: adminEmail},
+	}
+	ts.tasks.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, added entities.Task) (entities.Task, error) {
+		added.ID = testTaskID
+		return added, nil
+	})
+	ts.tasks.EXPECT().GetTasks(gomock.Any()).Return(tasks, nil)
+	ts.tasks.EXPECT().GetTaskByID(gomock.Any(), testTaskID).Return(tasks[0], nil)
+	ts.tasks.EXPECT().UpdateTask(gomock.Any(), testTaskID, gomock.Any()).DoAndReturn(func(_ context.Context, id string, updated entities.Task) (entities.Task, error) {
+		updated.ID = id
+		return updated, nil
+	})
+	ts.tasks.EXPECT().DeleteTask(gomock.Any(), testTaskID).Return(nil)
+
+	created, err := client.CreateTask(ctx, &pb.CreateTaskRequest{Title: "Write the gRPC API", DueDate: timestamppb.New(due)})
+	require.NoError(t, err)
+	assert.Equal(t, testTaskID, created.GetId())
+	assert.Equal(t, adminEmail, created.GetCreatedBy())
+
+	stream, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+	require.NoError(t, err)
+	var streamed []*pb.Task
+	for {
+		task, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		streamed = append(streamed, task)
+	}
+	require.Len(t, streamed, 2)
+	assert.True(t, due.Equal(streamed[0].GetDueDate().AsTime()))
+	assert.Nil(t, streamed[1].GetDueDate(), "unset due dates are left out")
+
+	got, err := client.GetTask(ctx, &pb.GetTaskRequest{Id: testTaskID})
+	require.NoError(t, err)
+	assert.Equal(t, "Write the gRPC API", got.GetTitle())
+
+	updated, err := client.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: testTaskID, Title: "Ship the gRPC API", Status: "Completed"})
+	require.NoError(t, err)
+	assert.Equal(t, "Completed", updated.GetStatus())
+
+	_, err = client.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: testTaskID})
+	assert.NoError(t, err)
+}
+
+func TestTaskService_Errors(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := ts.login(t, adminEmail)
+	client := pb.NewTaskServiceClient(ts.conn)
+
+	ts.tasks.EXPECT().GetTaskByID(gomock.Any(), testTaskID).Return(entities.Task{}, domainErrors.TaskNotFoundError{})
+	_, err := client.GetTask(ctx, &pb.GetTaskRequest{Id: testTaskID})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "task_not_found", errorReason(err))
+
+	_, err = client.GetTask(ctx, &pb.GetTaskRequest{Id: "not-an-id"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateTask(ctx, &pb.CreateTaskRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "validation_failed", errorReason(err))
+
+	// Unexpected failures keep their details out of the answer
+	ts.tasks.EXPECT().GetTasks(gomock.Any()).Return(nil, errors.New("connection reset"))
+	stream, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "connection reset")
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	ts := newTestServer(t)
+	tasks := pb.NewTaskServiceClient(ts.conn)
+	users := pb.NewUserServiceClient(ts.conn)
+
+	// No token
+	_, err := tasks.GetTask(context.Background(), &pb.GetTaskRequest{Id: testTaskID})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "authentication_required", errorReason(err))
+
+	stream, err := tasks.ListTasks(context.Background(), &pb.ListTasksRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "streams are authenticated too")
+
+	// Bad token
+	badCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-token")
+	_, err = users.GetProfile(badCtx, &pb.GetProfileRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "invalid_token", errorReason(err))
+
+	// Wrong password
+	ts.users.EXPECT().RecordFailedLogin(gomock.Any(), userEmail).Return(1, nil)
+	_, err = users.Login(context.Background(), &pb.LoginRequest{Email: userEmail, Password: "wrong"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Regular users can read but not manage
+	userCtx := ts.login(t, userEmail)
+	profile, err := users.GetProfile(userCtx, &pb.GetProfileRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, userEmail, profile.GetEmail())
+	assert.Equal(t, "user", profile.GetRole())
+
+	_, err = tasks.DeleteTask(userCtx, &pb.DeleteTaskRequest{Id: testTaskID})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = users.PromoteUser(userCtx, &pb.PromoteUserRequest{Email: userEmail})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"task_manager/Delivery/grpc/pb"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// taskServer implements TaskService on top of TaskUsecase
+type taskServer struct {
+	pb.UnimplementedTaskServiceServer
+	service usecases.TaskUsecase
+}
+
+// ListTasks streams every task, one message each
+func (s *taskServer) ListTasks(_ *pb.ListTasksRequest, stream grpc.ServerStreamingServer[pb.Task]) error {
+	tasks, err := s.service.GetTasks(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err := stream.Send(toTask(task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *taskServer) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.Task, error) {
+	id, err := taskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.service.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toTask(task), nil
+}
+
+func (s *taskServer) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.Task, error) {
+	if req.GetTitle() == "" {
+		return nil, &errors.ValidationError{Field: "title", Message: "title is required"}
+	}
+
+	task := entities.NewTask(req.GetTitle(), req.GetDescription(), fromTimestamp(req.GetDueDate()))
+	task.SetStatus(req.GetStatus())
+	task.CreatedBy = callerFrom(ctx).Email
+
+	newTask, err := s.service.AddTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return toTask(newTask), nil
+}
+
+func (s *taskServer) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.Task, error) {
+	id, err := taskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	updatedTask := entities.NewTask(req.GetTitle(), req.GetDescription(), fromTimestamp(req.GetDueDate()))
+	updatedTask.SetStatus(req.GetStatus())
+
+	task, err := s.service.UpdateTask(ctx, id, updatedTask)
+	if err != nil {
+		return nil, err
+	}
+	return toTask(task), nil
+}
+
+func (s *taskServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	id, err := taskID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteTask(ctx, id); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteTaskResponse{}, nil
+}
+
+// taskID checks that id is a task ID
+func taskID(id string) (string, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return "", errors.InvalidTaskIDError{}
+	}
+	return objectID.Hex(), nil
+}
+
+func toTask(task entities.Task) *pb.Task {
+	return &pb.Task{
+		Id:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     toTimestamp(task.DueDate),
+		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+	}
+}
+
+// toTimestamp leaves unset times out of the message
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"task_manager/Delivery/grpc/pb"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/peer"
+)
+
+// userServer implements UserService on top of UserUsecase
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	service usecases.UserUsecase
+}
+
+// Login checks a password. Accounts with two-factor authentication get an MFA
+// token instead, to finish the login over HTTP at /login/mfa.
+func (s *userServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	result, err := s.service.Login(ctx, req.GetEmail(), req.GetPassword(), clientIP(ctx))
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	if result.MFARequired {
+		return &pb.LoginResponse{MfaRequired: true, MfaToken: result.MFAToken}, nil
+	}
+	return &pb.LoginResponse{Token: result.Token}, nil
+}
+
+func (s *userServer) GetProfile(ctx context.Context, _ *pb.GetProfileRequest) (*pb.User, error) {
+	return toUser(callerFrom(ctx)), nil
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, errors.InvalidUserIDError{}
+	}
+
+	user, err := s.service.GetUserByID(ctx, id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	return toUser(user), nil
+}
+
+func (s *userServer) PromoteUser(ctx context.Context, req *pb.PromoteUserRequest) (*pb.PromoteUserResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, &errors.ValidationError{Field: "email", Message: "email is required"}
+	}
+
+	if err := s.service.PromoteToAdmin(ctx, req.GetEmail()); err != nil {
+		return nil, err
+	}
+	return &pb.PromoteUserResponse{}, nil
+}
+
+func toUser(user entities.User) *pb.User {
+	return &pb.User{
+		Id:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		Disabled:      user.Disabled,
+		EmailVerified: user.EmailVerified,
+		MfaEnabled:    user.MFAEnabled,
+	}
+}
+
+// clientIP is the caller's address, used to throttle failed logins
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// loginError reports a failed login as the HTTP API does: account states such
+// as a lockout keep their own answer, any other client error means the caller
+// failed to authenticate
+func loginError(err error) error {
+	var coded errors.CodedError
+	if stderrors.As(err, &coded) && coded.Status() == http.StatusBadRequest {
+		return authenticationFailed{coded}
+	}
+	return err
+}
+
+// authenticationFailed answers a wrapped error with Unauthenticated
+type authenticationFailed struct {
+	errors.CodedError
+}
+
+func (e authenticationFailed) Status() int   { return http.StatusUnauthorized }
+func (e authenticationFailed) Unwrap() error { return e.CodedError }
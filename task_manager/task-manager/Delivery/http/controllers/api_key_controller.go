@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/errors"
+	"task_manager/Usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyController handles the authenticated user's API keys
+type APIKeyController struct {
+	Service usecases.APIKeyUsecase
+}
+
+// NewAPIKeyController creates and returns a new APIKeyController instance
+func NewAPIKeyController(service usecases.APIKeyUsecase) *APIKeyController {
+	return &APIKeyController{
+		Service: service,
+	}
+}
+
+// CreateAPIKey handles POST /me/api-keys
+func (kc *APIKeyController) CreateAPIKey(c *gin.Context) {
+	var input request.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	newKey := usecases.NewAPIKey{
+		Name:   input.Name,
+		Scopes: input.Scopes,
+		TTL:    time.Duration(input.ExpiresInDays) * 24 * time.Hour,
+	}
+
+	key, secret, err := kc.Service.CreateAPIKey(c.Request.Context(), c.GetString("userID"), newKey)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToCreatedAPIKeyResponse(key, secret)
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListAPIKeys handles GET /me/api-keys
+func (kc *APIKeyController) ListAPIKeys(c *gin.Context) {
+	keys, err := kc.Service.ListAPIKeys(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToAPIKeyListResponse(keys)
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey handles DELETE /me/api-keys/:id
+func (kc *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.Error(errors.InvalidAPIKeyIDError{})
+		return
+	}
+
+	if err := kc.Service.RevokeAPIKey(c.Request.Context(), c.GetString("userID"), id.Hex()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("API key revoked")
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock APIKeyUsecase
+type MockAPIKeyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyUsecase) CreateAPIKey(ctx context.Context, userID string, request usecases.NewAPIKey) (entities.APIKey, string, error) {
+	args := m.Called(userID, request)
+	return args.Get(0).(entities.APIKey), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyUsecase) ListAPIKeys(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUsecase) RevokeAPIKey(ctx context.Context, userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyUsecase) Authenticate(ctx context.Context, secret string) (entities.User, entities.APIKey, error) {
+	args := m.Called(secret)
+	return args.Get(0).(entities.User), args.Get(1).(entities.APIKey), args.Error(2)
+}
+
+// setupAPIKeyTestRouter mounts the /me/api-keys routes as the given user, without auth middleware
+func setupAPIKeyTestRouter(controller *APIKeyController, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	keys := r.Group("/me/api-keys")
+	keys.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	keys.GET("", controller.ListAPIKeys)
+	keys.POST("", controller.CreateAPIKey)
+	keys.DELETE("/:id", controller.RevokeAPIKey)
+	return r
+}
+
+func TestAPIKeyController_CreateAPIKey(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockAPIKeyUsecase)
+	controller := NewAPIKeyController(mockUsecase)
+	router := setupAPIKeyTestRouter(controller, "user123")
+
+	// Mock expectations
+	created := entities.APIKey{ID: "key123", Name: "CI deploy", Prefix: "tm_0123abcd", Scopes: []string{"tasks:read"},
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(30 * 24 * time.Hour)}
+	expectedRequest := usecases.NewAPIKey{Name: "CI deploy", Scopes: []string{"tasks:read"}, TTL: 30 * 24 * time.Hour}
+	mockUsecase.On("CreateAPIKey", "user123", expectedRequest).Return(created, "tm_0123abcdsecret", nil)
+
+	// Test data
+	requestData := map[string]interface{}{
+		"name":            "CI deploy",
+		"scopes":          []string{"tasks:read"},
+		"expires_in_days": 30,
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("POST", "/me/api-keys", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response response.CreatedAPIKeyResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "key123", response.ID)
+	assert.Equal(t, "tm_0123abcdsecret", response.Key)
+	assert.Nil(t, response.LastUsedAt)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestAPIKeyController_CreateAPIKey_InvalidScope(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockAPIKeyUsecase)
+	controller := NewAPIKeyController(mockUsecase)
+	router := setupAPIKeyTestRouter(controller, "user123")
+
+	// Mock expectations
+	mockUsecase.On("CreateAPIKey", "user123", mock.AnythingOfType("usecases.NewAPIKey")).Return(entities.APIKey{}, "", errors.InvalidScopeError{Scope: "root"})
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"name": "CI deploy", "scopes": []string{"root"}})
+	req, _ := http.NewRequest("POST", "/me/api-keys", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestAPIKeyController_CreateAPIKey_DatabaseError(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockAPIKeyUsecase)
+	controller := NewAPIKeyController(mockUsecase)
+	router := setupAPIKeyTestRouter(controller, "user123")
+
+	// Mock expectations
+	mockUsecase.On("CreateAPIKey", "user123", mock.AnythingOfType("usecases.NewAPIKey")).Return(entities.APIKey{}, "", assert.AnError)
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"name": "CI deploy", "scopes": []string{"tasks:read"}})
+	req, _ := http.NewRequest("POST", "/me/api-keys", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions: a failing database is the server's fault, not the client's
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var problem response.ProblemResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "internal_error", problem.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestAPIKeyController_ListAPIKeys(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockAPIKeyUsecase)
+	controller := NewAPIKeyController(mockUsecase)
+	router := setupAPIKeyTestRouter(controller, "user123")
+
+	// Mock expectations
+	keys := []entities.APIKey{
+		{ID: "key1", Name: "CI deploy", Prefix: "tm_0123abcd", KeyHash: "hash", Scopes: []string{"tasks:read"}, LastUsedAt: time.Now()},
+		{ID: "key2", Name: "Backup script", Prefix: "tm_4567ef01", KeyHash: "hash", Scopes: []string{"tasks:read"}},
+	}
+	mockUsecase.On("ListAPIKeys", "user123").Return(keys, nil)
+
+	req, _ := http.NewRequest("GET", "/me/api-keys", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	var response response.APIKeyListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.APIKeys, 2)
+	assert.NotNil(t, response.APIKeys[0].LastUsedAt)
+	assert.Nil(t, response.APIKeys[1].LastUsedAt)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestAPIKeyController_RevokeAPIKey(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockAPIKeyUsecase)
+	controller := NewAPIKeyController(mockUsecase)
+	router := setupAPIKeyTestRouter(controller, "user123")
+
+	// Mock expectations
+	mockUsecase.On("RevokeAPIKey", "user123", "507f1f77bcf86cd799439011").Return(nil)
+	mockUsecase.On("RevokeAPIKey", "user123", "507f1f77bcf86cd799439012").Return(errors.APIKeyNotFoundError{})
+
+	req, _ := http.NewRequest("DELETE", "/me/api-keys/507f1f77bcf86cd799439011", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/me/api-keys/507f1f77bcf86cd799439012", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/me/api-keys/not-an-id", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUsecase.AssertExpectations(t)
+}
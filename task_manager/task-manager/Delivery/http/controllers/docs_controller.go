@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"task_manager/Delivery/http/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsController serves the OpenAPI document and the documentation page
+type DocsController struct {
+	Spec       []byte
+	stylesheet []byte
+	script     []byte
+}
+
+// NewDocsController creates and returns a new DocsController instance. The
+// document is built once, from operations.
+func NewDocsController(operations []openapi.Operation) (*DocsController, error) {
+	spec, err := json.Marshal(openapi.Document(operations))
+	if err != nil {
+		return nil, err
+	}
+	stylesheet, err := fs.ReadFile(openapi.UIAssets, "swagger-ui.css")
+	if err != nil {
+		return nil, err
+	}
+	script, err := fs.ReadFile(openapi.UIAssets, "swagger-ui-bundle.js")
+	if err != nil {
+		return nil, err
+	}
+	return &DocsController{
+		Spec:       spec,
+		stylesheet: stylesheet,
+		script:     script,
+	}, nil
+}
+
+// OpenAPI handles GET /openapi.json
+func (dc *DocsController) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", dc.Spec)
+}
+
+// UI handles GET /docs
+func (dc *DocsController) UI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.UI)
+}
+
+// UIStylesheet handles GET /docs/swagger-ui.css
+func (dc *DocsController) UIStylesheet(c *gin.Context) {
+	c.Data(http.StatusOK, "text/css; charset=utf-8", dc.stylesheet)
+}
+
+// UIScript handles GET /docs/swagger-ui-bundle.js
+func (dc *DocsController) UIScript(c *gin.Context) {
+	c.Data(http.StatusOK, "text/javascript; charset=utf-8", dc.script)
+}
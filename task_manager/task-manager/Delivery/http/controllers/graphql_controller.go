@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxGraphQLBody caps the size of a GraphQL request, which is read whole
+// before it is parsed
+const maxGraphQLBody = 1 << 20
+
+// GraphQLController serves the GraphQL endpoint
+type GraphQLController struct {
+	Schema *graphql.Schema
+}
+
+// NewGraphQLController creates and returns a new GraphQLController instance
+func NewGraphQLController(schema *graphql.Schema) *GraphQLController {
+	return &GraphQLController{
+		Schema: schema,
+	}
+}
+
+// Query handles POST /graphql. Requests that cannot run, e.g. with a syntax
+// error or over a limit, are answered with 400; errors in individual fields
+// are reported next to the data with 200.
+func (gc *GraphQLController) Query(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGraphQLBody)
+
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	viewer := graphql.Viewer{
+		ID:    c.GetString("userID"),
+		Email: c.GetString("userEmail"),
+		Role:  c.GetString("userRole"),
+	}
+	if key, ok := c.Get("apiKey"); ok {
+		apiKey := key.(entities.APIKey)
+		viewer.APIKey = &apiKey
+	}
+
+	result := gc.Schema.Execute(graphql.WithViewer(c.Request.Context(), viewer), req)
+
+	// Unexpected failures are only described in the logs
+	for _, err := range result.InternalErrors() {
+		c.Error(err)
+	}
+
+	status := http.StatusOK
+	if !result.Executed() {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, result)
+}
+
+// SchemaSDL handles GET /graphql/schema
+func (gc *GraphQLController) SchemaSDL(c *gin.Context) {
+	c.String(http.StatusOK, gc.Schema.SDL())
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Domain/entities"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGraphQLTestRouter(controller *GraphQLController, caller func(c *gin.Context)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.POST("/graphql", caller, controller.Query)
+	r.GET("/graphql/schema", controller.SchemaSDL)
+	return r
+}
+
+func asUser(role string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Set("userID", "user1")
+		c.Set("userEmail", "user@example.com")
+		c.Set("userRole", role)
+	}
+}
+
+func postGraphQL(router *gin.Engine, body string) (*httptest.ResponseRecorder, map[string]any) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var decoded map[string]any
+	json.Unmarshal(w.Body.Bytes(), &decoded)
+	return w, decoded
+}
+
+func TestGraphQLController_Query_Success(t *testing.T) {
+	mockUsecase := new(MockTaskUsecase)
+	mockUsecase.On("GetTasks").Return([]entities.Task{{ID: "task1", Title: "Test Task", Status: "Pending"}}, nil)
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(mockUsecase, nil, graphql.Limits{MaxDepth: 8, MaxComplexity: 100}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	w, body := postGraphQL(router, `{"query": "query($s: String) { tasks(filter: {status: $s}) { total items { id title } } }", "variables": {"s": "Pending"}}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"tasks": {"total": 1, "items": [{"id": "task1", "title": "Test Task"}]}}}`, w.Body.String())
+	assert.NotContains(t, body, "errors")
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGraphQLController_Query_FieldErrors(t *testing.T) {
+	mockUsecase := new(MockTaskUsecase)
+	mockUsecase.On("GetTaskByID", "507f1f77bcf86cd799439011").Return(nil, errors.New("connection reset"))
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(mockUsecase, nil, graphql.Limits{MaxDepth: 8, MaxComplexity: 100}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	// Field errors are reported next to the data, without internal details
+	w, body := postGraphQL(router, `{"query": "{ task(id: \"507f1f77bcf86cd799439011\") { title } }"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]any{"task": nil}, body["data"])
+	require.Len(t, body["errors"], 1)
+	assert.Equal(t, "Internal server error", body["errors"].([]any)[0].(map[string]any)["message"])
+	assert.NotContains(t, w.Body.String(), "connection reset")
+}
+
+func TestGraphQLController_Query_Rejected(t *testing.T) {
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(new(MockTaskUsecase), nil, graphql.Limits{MaxDepth: 3, MaxComplexity: 100}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	tests := []struct {
+		name string
+		body string
+		code string
+	}{
+		{"syntax error", `{"query": "{ tasks { "}`, "GRAPHQL_PARSE_FAILED"},
+		{"unknown field", `{"query": "{ nope }"}`, "GRAPHQL_VALIDATION_FAILED"},
+		{"too deep", `{"query": "{ tasks { items { creator { name } } } }"}`, "QUERY_TOO_DEEP"},
+		{"too complex", `{"query": "{ tasks(limit: 100) { items { id title } } }"}`, "QUERY_TOO_COMPLEX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := postGraphQL(router, tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.NotContains(t, body, "data")
+			require.Len(t, body["errors"], 1)
+			assert.Equal(t, tt.code, body["errors"].([]any)[0].(map[string]any)["extensions"].(map[string]any)["code"])
+		})
+	}
+}
+
+func TestGraphQLController_Query_InvalidBody(t *testing.T) {
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(new(MockTaskUsecase), nil, graphql.Limits{}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	w, _ := postGraphQL(router, `{"variables": {}}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+func TestGraphQLController_Query_TooLarge(t *testing.T) {
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(new(MockTaskUsecase), nil, graphql.Limits{}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	w, body := postGraphQL(router, `{"query": "`+strings.Repeat("{ a ", maxGraphQLBody)+`"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, "request_too_large", body["code"])
+}
+
+func TestGraphQLController_SchemaSDL(t *testing.T) {
+	controller := NewGraphQLController(graphql.NewTaskManagerSchema(nil, nil, graphql.Limits{}))
+	router := setupGraphQLTestRouter(controller, asUser("user"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/graphql/schema", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "type Query {")
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"task_manager/Delivery/http/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each dependency check so a hung dependency still
+// gets the instance reported as not ready
+const readinessTimeout = 2 * time.Second
+
+// HealthCheck probes a dependency the service needs to serve requests
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthController reports whether the service is alive and ready for traffic
+type HealthController struct {
+	Checks []HealthCheck
+}
+
+// NewHealthController creates and returns a new HealthController instance
+func NewHealthController(checks ...HealthCheck) *HealthController {
+	return &HealthController{
+		Checks: checks,
+	}
+}
+
+// Liveness handles GET /healthz. It only shows the process is serving requests.
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, response.ToHealthResponse(nil))
+}
+
+// Readiness handles GET /readyz by running every dependency check
+func (hc *HealthController) Readiness(c *gin.Context) {
+	status := http.StatusOK
+	checks := make(map[string]string, len(hc.Checks))
+
+	for _, check := range hc.Checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		err := check.Check(ctx)
+		cancel()
+
+		if err != nil {
+			c.Error(err)
+			status = http.StatusServiceUnavailable
+			checks[check.Name] = "unavailable"
+			continue
+		}
+		checks[check.Name] = "ok"
+	}
+
+	// Return response DTO
+	c.JSON(status, response.ToHealthResponse(checks))
+}
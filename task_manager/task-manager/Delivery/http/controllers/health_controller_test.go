@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/response"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHealthTestRouter(controller *HealthController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/healthz", controller.Liveness)
+	r.GET("/readyz", controller.Readiness)
+	return r
+}
+
+func TestHealthController_Liveness(t *testing.T) {
+	// Liveness does not depend on the checks
+	controller := NewHealthController(HealthCheck{Name: "mongodb", Check: func(ctx context.Context) error {
+		return errors.New("down")
+	}})
+	router := setupHealthTestRouter(controller)
+
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthController_Readiness(t *testing.T) {
+	mongoErr := error(nil)
+	controller := NewHealthController(HealthCheck{Name: "mongodb", Check: func(ctx context.Context) error {
+		return mongoErr
+	}})
+	router := setupHealthTestRouter(controller)
+
+	// Ready
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body response.HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "ok", body.Status)
+	assert.Equal(t, "ok", body.Checks["mongodb"])
+
+	// Not ready while MongoDB is unreachable
+	mongoErr = errors.New("server selection timeout")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "unavailable", body.Status)
+	assert.Equal(t, "unavailable", body.Checks["mongodb"])
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController handles the admin view of background jobs
+type JobController struct {
+	Service usecases.JobUsecase
+}
+
+// NewJobController creates and returns a new JobController instance
+func NewJobController(service usecases.JobUsecase) *JobController {
+	return &JobController{
+		Service: service,
+	}
+}
+
+// ListJobs handles GET /jobs
+func (jc *JobController) ListJobs(c *gin.Context) {
+	var input request.ListJobsInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	filter := entities.JobFilter{
+		Status: input.Status,
+		Type:   input.Type,
+		Page:   input.Page,
+		Limit:  input.Limit,
+	}.WithDefaults()
+
+	jobs, total, err := jc.Service.ListJobs(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToJobListResponse(jobs, filter, total)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Infrastructure/services"
+	usecases "task_manager/Usecases"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobTestRouter(t *testing.T, jobs ...entities.JobRecord) *gin.Engine {
+	queue := services.NewMemoryJobQueue()
+	for _, job := range jobs {
+		_, _, err := queue.Enqueue(context.Background(), job)
+		require.NoError(t, err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/jobs", NewJobController(usecases.NewJobUsecase(queue)).ListJobs)
+	return r
+}
+
+func TestJobController_ListJobs(t *testing.T) {
+	created := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	router := setupJobTestRouter(t,
+		entities.JobRecord{ID: "job1", Type: "send_email", Payload: []byte(`{"to":"user@example.com"}`), Status: entities.JobSucceeded,
+			Attempts: 1, MaxAttempts: 5, CreatedAt: created, FinishedAt: created.Add(time.Second)},
+		entities.JobRecord{ID: "job2", Type: "send_email", Status: entities.JobQueued,
+			Attempts: 2, MaxAttempts: 5, LastError: "connection refused", CreatedAt: created.Add(time.Minute)},
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/jobs?limit=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body response.JobListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.EqualValues(t, 2, body.Total)
+	assert.Equal(t, 1, body.Limit)
+	require.Len(t, body.Jobs, 1)
+	assert.Equal(t, "job2", body.Jobs[0].ID)
+	assert.Equal(t, "connection refused", body.Jobs[0].LastError)
+	assert.Nil(t, body.Jobs[0].FinishedAt)
+
+	// Filtered by state; payloads are never shown
+	req, _ = http.NewRequest(http.MethodGet, "/jobs?status=succeeded", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Jobs, 1)
+	assert.Equal(t, "job1", body.Jobs[0].ID)
+	assert.NotNil(t, body.Jobs[0].FinishedAt)
+	assert.NotContains(t, w.Body.String(), "user@example.com")
+}
+
+func TestJobController_ListJobs_InvalidStatus(t *testing.T) {
+	router := setupJobTestRouter(t)
+
+	req, _ := http.NewRequest(http.MethodGet, "/jobs?status=paused", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+func TestJobController_ListJobs_OtherOrganization(t *testing.T) {
+	router := setupJobTestRouter(t, entities.JobRecord{ID: "job1", Type: "send_email", Status: entities.JobQueued})
+
+	req, _ := http.NewRequest(http.MethodGet, "/jobs", nil)
+	req = req.WithContext(utils.ContextWithOrganization(req.Context(), "org1"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "job1")
+
+	req = req.WithContext(utils.ContextWithOrganization(req.Context(), entities.DefaultOrganizationID))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "job1")
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSController publishes the public keys that verify access tokens
+type JWKSController struct {
+	TokenService interfaces.TokenService
+}
+
+// NewJWKSController creates and returns a new JWKSController instance
+func NewJWKSController(tokenService interfaces.TokenService) *JWKSController {
+	return &JWKSController{
+		TokenService: tokenService,
+	}
+}
+
+// JWKS handles GET /.well-known/jwks.json
+func (jc *JWKSController) JWKS(c *gin.Context) {
+	keys, err := jc.TokenService.JWKS()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Verifiers may cache the set for five minutes; the key ring publishes each
+	// new key longer than that before it signs anything
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, response.ToJWKSResponse(keys))
+}
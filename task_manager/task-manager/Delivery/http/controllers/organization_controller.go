@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Usecases"
+	"task_manager/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationController handles starting organizations and viewing one's own
+type OrganizationController struct {
+	Service usecases.OrganizationUsecase
+}
+
+// NewOrganizationController creates and returns a new OrganizationController instance
+func NewOrganizationController(service usecases.OrganizationUsecase) *OrganizationController {
+	return &OrganizationController{
+		Service: service,
+	}
+}
+
+// CreateOrganization handles POST /organizations
+func (oc *OrganizationController) CreateOrganization(c *gin.Context) {
+	var input request.CreateOrganizationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	admin := entities.NewUser(input.Name, input.Email, input.Password)
+
+	organization, createdAdmin, err := oc.Service.CreateOrganization(c.Request.Context(), input.Organization, admin)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.CreateOrganizationResponse{
+		Organization: response.ToOrganizationResponse(organization),
+		Admin:        response.ToUserResponse(createdAdmin),
+	})
+}
+
+// GetOrganization handles GET /me/organization
+func (oc *OrganizationController) GetOrganization(c *gin.Context) {
+	organizationID, _ := utils.OrganizationFromContext(c.Request.Context())
+
+	organization, err := oc.Service.GetOrganization(c.Request.Context(), organizationID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToOrganizationResponse(organization)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock OrganizationUsecase
+type MockOrganizationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationUsecase) CreateOrganization(ctx context.Context, name string, admin entities.User) (entities.Organization, entities.User, error) {
+	args := m.Called(name, admin)
+	return args.Get(0).(entities.Organization), args.Get(1).(entities.User), args.Error(2)
+}
+
+func (m *MockOrganizationUsecase) GetOrganization(ctx context.Context, id string) (entities.Organization, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Organization), args.Error(1)
+}
+
+// setupOrganizationTestRouter mounts the organization routes, with requests
+// scoped to organizationID as the auth middleware would
+func setupOrganizationTestRouter(controller *OrganizationController, organizationID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.POST("/organizations", controller.CreateOrganization)
+	r.GET("/me/organization", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(utils.ContextWithOrganization(c.Request.Context(), organizationID))
+		c.Next()
+	}, controller.GetOrganization)
+	return r
+}
+
+func TestOrganizationController_CreateOrganization(t *testing.T) {
+	mockUsecase := new(MockOrganizationUsecase)
+	router := setupOrganizationTestRouter(NewOrganizationController(mockUsecase), "")
+
+	created := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	admin := entities.NewUser("Ada", "ada@example.com", "password123")
+	mockUsecase.On("CreateOrganization", "Acme", admin).Return(
+		entities.Organization{ID: "org1", Name: "Acme", CreatedAt: created},
+		entities.User{ID: "user1", Name: "Ada", Email: "ada@example.com", Role: "admin", OrganizationID: "org1"},
+		nil,
+	)
+
+	body, _ := json.Marshal(map[string]string{"organization": "Acme", "name": "Ada", "email": "ada@example.com", "password": "password123"})
+	req, _ := http.NewRequest(http.MethodPost, "/organizations", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp response.CreateOrganizationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "org1", resp.Organization.ID)
+	assert.Equal(t, "org1", resp.Admin.OrganizationID)
+	assert.Equal(t, "admin", resp.Admin.Role)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestOrganizationController_CreateOrganization_EmailTaken(t *testing.T) {
+	mockUsecase := new(MockOrganizationUsecase)
+	router := setupOrganizationTestRouter(NewOrganizationController(mockUsecase), "")
+
+	mockUsecase.On("CreateOrganization", "Acme", mock.Anything).Return(entities.Organization{}, entities.User{}, errors.EmailAlreadyExistsError{})
+
+	body, _ := json.Marshal(map[string]string{"organization": "Acme", "name": "Ada", "email": "ada@example.com", "password": "password123"})
+	req, _ := http.NewRequest(http.MethodPost, "/organizations", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestOrganizationController_GetOrganization(t *testing.T) {
+	mockUsecase := new(MockOrganizationUsecase)
+	router := setupOrganizationTestRouter(NewOrganizationController(mockUsecase), "org1")
+
+	// The organization comes from the request's scope, never from the client
+	mockUsecase.On("GetOrganization", "org1").Return(entities.Organization{ID: "org1", Name: "Acme"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/me/organization", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.OrganizationResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Acme", resp.Name)
+	assert.Nil(t, resp.CreatedAt)
+	mockUsecase.AssertExpectations(t)
+}
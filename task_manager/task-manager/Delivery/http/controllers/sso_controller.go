@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/errors"
+	"task_manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The in-flight authorization request is kept in a short-lived cookie scoped to
+// the SSO routes, so it comes back only with the provider's redirect
+const (
+	ssoCookieName   = "oidc_login"
+	ssoCookiePath   = "/auth/oidc"
+	ssoCookieMaxAge = 10 * 60
+)
+
+// SSOController handles single sign-on through an external identity provider
+type SSOController struct {
+	Service      usecases.SSOUsecase
+	SecureCookie bool // set when the application is served over HTTPS
+}
+
+// NewSSOController creates and returns a new SSOController instance
+func NewSSOController(service usecases.SSOUsecase, secureCookie bool) *SSOController {
+	return &SSOController{
+		Service:      service,
+		SecureCookie: secureCookie,
+	}
+}
+
+// Login handles GET /auth/oidc/login by redirecting to the identity provider
+func (sc *SSOController) Login(c *gin.Context) {
+	login, err := sc.Service.BeginLogin()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	value := strings.Join([]string{login.State, login.Nonce, login.CodeVerifier}, ".")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoCookieName, value, ssoCookieMaxAge, ssoCookiePath, "", sc.SecureCookie, true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// Callback handles GET /auth/oidc/callback, where the identity provider returns the user
+func (sc *SSOController) Callback(c *gin.Context) {
+	cookie, _ := c.Cookie(ssoCookieName)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoCookieName, "", -1, ssoCookiePath, "", sc.SecureCookie, true)
+
+	if errorCode := c.Query("error"); errorCode != "" {
+		c.Error(errors.SSOLoginFailedError{Reason: errorCode})
+		return
+	}
+
+	// The state must match the one issued to this browser, which stops a login
+	// started elsewhere from being completed here
+	parts := strings.Split(cookie, ".")
+	state := c.Query("state")
+	if len(parts) != 3 || state == "" || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		c.Error(errors.InvalidSSORequestError{})
+		return
+	}
+
+	result, err := sc.Service.CompleteLogin(c.Request.Context(), c.Query("code"), usecases.SSOLoginRequest{
+		State:        parts[0],
+		Nonce:        parts[1],
+		CodeVerifier: parts[2],
+	})
+	if err != nil {
+		c.Error(loginError(err))
+		return
+	}
+
+	// Accounts with two-factor authentication continue at /login/mfa
+	if result.MFARequired {
+		c.JSON(http.StatusOK, response.ToMFAChallengeResponse(result.MFAToken))
+		return
+	}
+
+	// Return response DTO
+	response := response.ToLoginResponse(result.Token)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock SSOUsecase
+type MockSSOUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSSOUsecase) BeginLogin() (usecases.SSOLoginRequest, error) {
+	args := m.Called()
+	return args.Get(0).(usecases.SSOLoginRequest), args.Error(1)
+}
+
+func (m *MockSSOUsecase) CompleteLogin(ctx context.Context, code string, request usecases.SSOLoginRequest) (usecases.LoginResult, error) {
+	args := m.Called(code, request)
+	return args.Get(0).(usecases.LoginResult), args.Error(1)
+}
+
+func setupSSOTestRouter(controller *SSOController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/auth/oidc/login", controller.Login)
+	r.GET("/auth/oidc/callback", controller.Callback)
+	return r
+}
+
+func TestSSOController_Login(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockSSOUsecase)
+	controller := NewSSOController(mockUsecase, true)
+	router := setupSSOTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("BeginLogin").Return(usecases.SSOLoginRequest{
+		AuthURL: "https://idp.example.com/authorize?state=state1", State: "state1", Nonce: "nonce1", CodeVerifier: "verifier1",
+	}, nil)
+
+	// Execute
+	req, _ := http.NewRequest("GET", "/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=state1", w.Header().Get("Location"))
+
+	cookies := w.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, "state1.nonce1.verifier1", cookies[0].Value)
+	assert.Equal(t, "/auth/oidc", cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestSSOController_Callback(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockSSOUsecase)
+	controller := NewSSOController(mockUsecase, false)
+	router := setupSSOTestRouter(controller)
+
+	// Mock expectations
+	expectedRequest := usecases.SSOLoginRequest{State: "state1", Nonce: "nonce1", CodeVerifier: "verifier1"}
+	mockUsecase.On("CompleteLogin", "code1", expectedRequest).Return(usecases.LoginResult{Token: "jwt-token"}, nil)
+
+	// Execute
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code1&state=state1", nil)
+	req.AddCookie(&http.Cookie{Name: "oidc_login", Value: "state1.nonce1.verifier1"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body response.LoginResponse
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", body.Token)
+
+	// The request cookie is cleared
+	cookies := w.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Less(t, cookies[0].MaxAge, 0)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestSSOController_Callback_StateMismatch(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockSSOUsecase)
+	controller := NewSSOController(mockUsecase, false)
+	router := setupSSOTestRouter(controller)
+
+	for _, cookie := range []string{"", "other.nonce1.verifier1"} {
+		// Execute
+		req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code1&state=state1", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "oidc_login", Value: cookie})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	mockUsecase.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything)
+}
+
+func TestSSOController_Callback_AccountNotAllowed(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockSSOUsecase)
+	controller := NewSSOController(mockUsecase, false)
+	router := setupSSOTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("CompleteLogin", "code1", mock.Anything).Return(usecases.LoginResult{}, errors.SSOAccountNotAllowedError{})
+
+	// Execute
+	req, _ := http.NewRequest("GET", "/auth/oidc/callback?code=code1&state=state1", nil)
+	req.AddCookie(&http.Cookie{Name: "oidc_login", Value: "state1.nonce1.verifier1"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
@@ -1,35 +1,60 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
-	"task_manager/Domain/entities"
+	"strings"
 	"task_manager/Delivery/http/request"
 	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
 	"task_manager/Usecases"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// TaskController handles task-related HTTP requests
+// TaskController handles task-related HTTP requests. Each API version has its
+// own controller, which differ only in how tasks are represented.
 type TaskController struct {
 	Service usecases.TaskUsecase
+
+	toTask     func(entities.Task) any
+	toTaskList func([]entities.Task) any
 }
 
-// NewTaskController creates and returns a new TaskController instance
+// NewTaskController creates and returns a new TaskController instance for API v1
 func NewTaskController(service usecases.TaskUsecase) *TaskController {
 	return &TaskController{
-		Service: service,
+		Service:    service,
+		toTask:     func(task entities.Task) any { return response.ToTaskResponse(task) },
+		toTaskList: func(tasks []entities.Task) any { return response.ToTaskListResponse(tasks) },
+	}
+}
+
+// NewTaskControllerV2 creates and returns a new TaskController instance for API v2
+func NewTaskControllerV2(service usecases.TaskUsecase) *TaskController {
+	return &TaskController{
+		Service:    service,
+		toTask:     func(task entities.Task) any { return response.ToTaskResponseV2(task) },
+		toTaskList: func(tasks []entities.Task) any { return response.ToTaskListResponseV2(tasks) },
 	}
 }
 
 // GetTasks handles GET /tasks
 func (tc *TaskController) GetTasks(c *gin.Context) {
-	tasks := tc.Service.GetTasks()
-	
-	// Return response DTO
-	response := response.ToTaskListResponse(tasks)
-	c.JSON(http.StatusOK, response)
+	tasks, err := tc.Service.GetTasks(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO. The list has no Last-Modified, as deleting a task
+	// changes it without leaving a newer timestamp behind.
+	writeConditional(c, tc.toTaskList(tasks), time.Time{})
 }
 
 // GetTaskByID handles GET /tasks/:id
@@ -37,42 +62,41 @@ func (tc *TaskController) GetTaskByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.Error(errors.InvalidTaskIDError{})
 		return
 	}
-	
-	task, err := tc.Service.GetTaskByID(id.Hex())
+
+	task, err := tc.Service.GetTaskByID(c.Request.Context(), id.Hex())
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
-	
+
 	// Return response DTO
-	response := response.ToTaskResponse(task)
-	c.JSON(http.StatusOK, response)
+	writeConditional(c, tc.toTask(task), task.UpdatedAt)
 }
 
 // AddTask handles POST /tasks
 func (tc *TaskController) AddTask(c *gin.Context) {
 	var input request.CreateTaskInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Convert CreateTaskInput to domain Task
 	task := entities.NewTask(input.Title, input.Description, input.DueDate)
 	task.SetStatus(input.Status)
+	task.CreatedBy = c.GetString("userEmail")
 
-	newTask, err := tc.Service.AddTask(task)
+	newTask, err := tc.Service.AddTask(c.Request.Context(), task)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	// Return response DTO
-	response := response.ToTaskResponse(newTask)
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, tc.toTask(newTask))
 }
 
 // UpdateTask handles PUT /tasks/:id
@@ -80,13 +104,13 @@ func (tc *TaskController) UpdateTask(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.Error(errors.InvalidTaskIDError{})
 		return
 	}
 
 	var input request.UpdateTaskInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -94,15 +118,14 @@ func (tc *TaskController) UpdateTask(c *gin.Context) {
 	updatedTask := entities.NewTask(input.Title, input.Description, input.DueDate)
 	updatedTask.SetStatus(input.Status)
 
-	task, err := tc.Service.UpdateTask(id.Hex(), updatedTask)
+	task, err := tc.Service.UpdateTask(c.Request.Context(), id.Hex(), updatedTask)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	// Return response DTO
-	response := response.ToTaskResponse(task)
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, tc.toTask(task))
 }
 
 // DeleteTask handles DELETE /tasks/:id
@@ -110,17 +133,65 @@ func (tc *TaskController) DeleteTask(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.Error(errors.InvalidTaskIDError{})
 		return
 	}
-	
-	err = tc.Service.DeleteTask(id.Hex())
+
+	err = tc.Service.DeleteTask(c.Request.Context(), id.Hex())
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
-	
+
 	// Return response DTO
 	response := response.ToMessageResponse("Task deleted successfully")
 	c.JSON(http.StatusOK, response)
-} 
\ No newline at end of file
+}
+
+// writeConditional answers a GET with body and its validators: an ETag
+// derived from the body and, when known, Last-Modified. A client that already
+// has this representation gets 304 Not Modified without the body.
+func writeConditional(c *gin.Context, body any, lastModified time.Time) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	hash := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	// Caches must check back with us before reusing the response
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// notModified evaluates If-None-Match and, only without it, If-Modified-Since
+// as RFC 9110 describes for GET
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimSpace(candidate)
+			// Weak comparison: W/"x" matches "x"
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
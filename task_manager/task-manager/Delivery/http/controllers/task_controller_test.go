@@ -2,14 +2,16 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"testing"
-	"time"
+	"task_manager/Delivery/http/middleware"
 	"task_manager/Delivery/http/response"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
+	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -21,12 +23,12 @@ type MockTaskUsecase struct {
 	mock.Mock
 }
 
-func (m *MockTaskUsecase) GetTasks() []entities.Task {
+func (m *MockTaskUsecase) GetTasks(ctx context.Context) ([]entities.Task, error) {
 	args := m.Called()
-	return args.Get(0).([]entities.Task)
+	return args.Get(0).([]entities.Task), args.Error(1)
 }
 
-func (m *MockTaskUsecase) GetTaskByID(id string) (entities.Task, error) {
+func (m *MockTaskUsecase) GetTaskByID(ctx context.Context, id string) (entities.Task, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return entities.Task{}, args.Error(1)
@@ -34,7 +36,7 @@ func (m *MockTaskUsecase) GetTaskByID(id string) (entities.Task, error) {
 	return args.Get(0).(entities.Task), args.Error(1)
 }
 
-func (m *MockTaskUsecase) AddTask(task entities.Task) (entities.Task, error) {
+func (m *MockTaskUsecase) AddTask(ctx context.Context, task entities.Task) (entities.Task, error) {
 	args := m.Called(task)
 	if args.Get(0) == nil {
 		return entities.Task{}, args.Error(1)
@@ -42,7 +44,7 @@ func (m *MockTaskUsecase) AddTask(task entities.Task) (entities.Task, error) {
 	return args.Get(0).(entities.Task), args.Error(1)
 }
 
-func (m *MockTaskUsecase) UpdateTask(id string, updatedTask entities.Task) (entities.Task, error) {
+func (m *MockTaskUsecase) UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error) {
 	args := m.Called(id, updatedTask)
 	if args.Get(0) == nil {
 		return entities.Task{}, args.Error(1)
@@ -50,7 +52,7 @@ func (m *MockTaskUsecase) UpdateTask(id string, updatedTask entities.Task) (enti
 	return args.Get(0).(entities.Task), args.Error(1)
 }
 
-func (m *MockTaskUsecase) DeleteTask(id string) error {
+func (m *MockTaskUsecase) DeleteTask(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
@@ -58,6 +60,7 @@ func (m *MockTaskUsecase) DeleteTask(id string) error {
 func setupTaskTestRouter(controller *TaskController) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
 	r.POST("/tasks", controller.AddTask)
 	r.GET("/tasks", controller.GetTasks)
 	r.GET("/tasks/:id", controller.GetTaskByID)
@@ -135,7 +138,8 @@ func TestTaskController_AddTask_ValidationError(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "Key: 'CreateTaskInput.Title' Error:Field validation for 'Title' failed on the 'required' tag")
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "title", "message": "is required"}}, response["errors"])
 }
 
 func TestTaskController_AddTask_InvalidStatus(t *testing.T) {
@@ -195,7 +199,7 @@ func TestTaskController_GetTasks_Success(t *testing.T) {
 	expectedTasks := []entities.Task{task1, task2}
 
 	// Mock expectations
-	mockUsecase.On("GetTasks").Return(expectedTasks)
+	mockUsecase.On("GetTasks").Return(expectedTasks, nil)
 
 	req, _ := http.NewRequest("GET", "/tasks", nil)
 	w := httptest.NewRecorder()
@@ -221,6 +225,63 @@ func TestTaskController_GetTasks_Success(t *testing.T) {
 	mockUsecase.AssertExpectations(t)
 }
 
+func TestTaskController_GetTasks_Error(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockTaskUsecase)
+	controller := NewTaskController(mockUsecase)
+	router := setupTaskTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("GetTasks").Return([]entities.Task(nil), assert.AnError)
+
+	req, _ := http.NewRequest("GET", "/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, response.ProblemContentType, w.Header().Get("Content-Type"))
+
+	// The cause is logged, not shown to the client
+	var problem response.ProblemResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "internal_error", problem.Code)
+	assert.Equal(t, "/tasks", problem.Instance)
+	assert.NotContains(t, w.Body.String(), assert.AnError.Error())
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestTaskControllerV2_GetTasks(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockTaskUsecase)
+	controller := NewTaskControllerV2(mockUsecase)
+	router := setupTaskTestRouter(controller)
+
+	overdue := entities.NewTask("Overdue", "", time.Now().Add(-time.Hour))
+	overdue.ID = "task1"
+	mockUsecase.On("GetTasks").Return([]entities.Task{overdue}, nil).Once()
+	mockUsecase.On("GetTasks").Return([]entities.Task(nil), nil).Once()
+
+	req, _ := http.NewRequest("GET", "/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list response.TaskListResponseV2
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Data, 1)
+	assert.True(t, list.Data[0].Overdue)
+
+	// An empty list is an array, not null
+	req, _ = http.NewRequest("GET", "/tasks", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[]}`, w.Body.String())
+	mockUsecase.AssertExpectations(t)
+}
+
 func TestTaskController_GetTaskByID_Success(t *testing.T) {
 	// Setup
 	mockUsecase := new(MockTaskUsecase)
@@ -271,7 +332,99 @@ func TestTaskController_GetTaskByID_NotFound(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "task not found")
+	assert.Equal(t, "task_not_found", response["code"])
+	assert.Equal(t, "task not found", response["detail"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestTaskController_GetTaskByID_Conditional(t *testing.T) {
+	mockUsecase := new(MockTaskUsecase)
+	controller := NewTaskController(mockUsecase)
+	router := setupTaskTestRouter(controller)
+
+	task := entities.NewTask("Test Task", "Test Description", time.Now())
+	task.ID = "507f1f77bcf86cd799439011"
+	task.UpdatedAt = time.Date(2026, 3, 1, 9, 30, 15, 500, time.UTC)
+	mockUsecase.On("GetTaskByID", task.ID).Return(task, nil)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/tasks/"+task.ID, nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "Sun, 01 Mar 2026 09:30:15 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"matching etag", "If-None-Match", etag, http.StatusNotModified},
+		{"weak etag in a list", "If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"any etag", "If-None-Match", "*", http.StatusNotModified},
+		{"changed etag", "If-None-Match", `"other"`, http.StatusOK},
+		{"not modified since", "If-Modified-Since", "Sun, 01 Mar 2026 09:30:15 GMT", http.StatusNotModified},
+		{"modified since", "If-Modified-Since", "Sun, 01 Mar 2026 09:30:14 GMT", http.StatusOK},
+		{"malformed date", "If-Modified-Since", "yesterday", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.header, tt.value)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tt.status == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.Contains(t, w.Body.String(), "Test Task")
+			}
+		})
+	}
+}
+
+func TestTaskController_GetTasks_Conditional(t *testing.T) {
+	mockUsecase := new(MockTaskUsecase)
+	router := setupTaskTestRouter(NewTaskController(mockUsecase))
+
+	tasks := []entities.Task{{ID: "task1", Title: "Test Task", Status: "Pending", UpdatedAt: time.Now()}}
+	mockUsecase.On("GetTasks").Return(tasks, nil).Once()
+
+	req, _ := http.NewRequest("GET", "/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+	etag := w.Header().Get("ETag")
+
+	// If-Modified-Since is ignored, as the list has no Last-Modified
+	mockUsecase.On("GetTasks").Return(tasks, nil).Once()
+	req, _ = http.NewRequest("GET", "/tasks", nil)
+	req.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Once a task changes, so does the ETag
+	tasks[0].Status = "Completed"
+	mockUsecase.On("GetTasks").Return(tasks, nil).Once()
+	req, _ = http.NewRequest("GET", "/tasks", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -356,13 +509,13 @@ func TestTaskResponse_ToMap(t *testing.T) {
 	// Convert to map using JSON marshaling
 	jsonData, err := json.Marshal(taskResp)
 	assert.NoError(t, err)
-	
+
 	var taskMap map[string]interface{}
 	err = json.Unmarshal(jsonData, &taskMap)
 	assert.NoError(t, err)
-	
+
 	assert.Equal(t, "task123", taskMap["id"])
 	assert.Equal(t, "Test Task", taskMap["title"])
 	assert.Equal(t, "Test Description", taskMap["description"])
 	assert.Equal(t, "Pending", taskMap["status"])
-} 
\ No newline at end of file
+}
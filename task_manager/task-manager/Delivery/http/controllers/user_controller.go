@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	stderrors "errors"
 	"net/http"
-	"task_manager/Domain/entities"
 	"task_manager/Delivery/http/request"
 	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
 	"task_manager/Usecases"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // UserController handles user-related HTTP requests
@@ -26,16 +29,16 @@ func NewUserController(service usecases.UserUsecase) *UserController {
 func (uc *UserController) Register(c *gin.Context) {
 	var input request.RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Convert RegisterInput to domain User
 	user := entities.NewUser(input.Name, input.Email, input.Password)
 
-	createdUser, err := uc.Service.Register(user)
+	createdUser, err := uc.Service.Register(c.Request.Context(), user)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -48,13 +51,38 @@ func (uc *UserController) Register(c *gin.Context) {
 func (uc *UserController) Login(c *gin.Context) {
 	var input request.LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	token, err := uc.Service.Login(input.Email, input.Password)
+	result, err := uc.Service.Login(c.Request.Context(), input.Email, input.Password, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.Error(loginError(err))
+		return
+	}
+
+	// Accounts with two-factor authentication continue at /login/mfa
+	if result.MFARequired {
+		c.JSON(http.StatusOK, response.ToMFAChallengeResponse(result.MFAToken))
+		return
+	}
+
+	// Return response DTO
+	response := response.ToLoginResponse(result.Token)
+	c.JSON(http.StatusOK, response)
+}
+
+// LoginMFA handles POST /login/mfa, the second step of a two-factor login
+func (uc *UserController) LoginMFA(c *gin.Context) {
+	var input request.MFALoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	token, err := uc.Service.VerifyMFALogin(c.Request.Context(), input.MFAToken, input.Code, c.ClientIP())
+	if err != nil {
+		c.Error(loginError(err))
 		return
 	}
 
@@ -67,17 +95,390 @@ func (uc *UserController) Login(c *gin.Context) {
 func (uc *UserController) Promote(c *gin.Context) {
 	var input request.PromoteInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
-	err := uc.Service.PromoteToAdmin(input.Email)
+	err := uc.Service.PromoteToAdmin(c.Request.Context(), input.Email)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	// Return response DTO
 	response := response.ToMessageResponse("User promoted to admin")
 	c.JSON(http.StatusOK, response)
-} 
\ No newline at end of file
+}
+
+// GetProfile returns the authenticated user's profile
+func (uc *UserController) GetProfile(c *gin.Context) {
+	user, err := uc.Service.GetUserByEmail(c.Request.Context(), c.GetString("userEmail"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToUserResponse(user)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateProfile changes the authenticated user's name and/or email
+func (uc *UserController) UpdateProfile(c *gin.Context) {
+	var input request.UpdateProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	update := usecases.ProfileUpdate{
+		Name:            input.Name,
+		Email:           input.Email,
+		CurrentPassword: input.CurrentPassword,
+	}
+
+	user, token, err := uc.Service.UpdateProfile(c.Request.Context(), c.GetString("userEmail"), update)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToProfileUpdateResponse(user, token)
+	c.JSON(http.StatusOK, response)
+}
+
+// ChangePassword replaces the authenticated user's password
+func (uc *UserController) ChangePassword(c *gin.Context) {
+	var input request.ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	err := uc.Service.ChangePassword(c.Request.Context(), c.GetString("userEmail"), input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("Password updated successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteAccount deletes the authenticated user's account
+func (uc *UserController) DeleteAccount(c *gin.Context) {
+	var input request.DeleteAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	err := uc.Service.DeleteAccount(c.Request.Context(), c.GetString("userEmail"), input.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("Account deleted successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// EnrollMFA handles POST /me/mfa/enroll
+func (uc *UserController) EnrollMFA(c *gin.Context) {
+	enrollment, err := uc.Service.EnrollMFA(c.Request.Context(), c.GetString("userEmail"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMFAEnrollmentResponse(enrollment.Secret, enrollment.URI)
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmMFA handles POST /me/mfa/confirm
+func (uc *UserController) ConfirmMFA(c *gin.Context) {
+	var input request.MFACodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	codes, err := uc.Service.ConfirmMFA(c.Request.Context(), c.GetString("userEmail"), input.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToRecoveryCodesResponse(codes)
+	c.JSON(http.StatusOK, response)
+}
+
+// DisableMFA handles DELETE /me/mfa
+func (uc *UserController) DisableMFA(c *gin.Context) {
+	var input request.DeleteAccountInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := uc.Service.DisableMFA(c.Request.Context(), c.GetString("userEmail"), input.Password); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("Two-factor authentication disabled")
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyEmail handles POST /verify-email, and GET for links opened from the email
+func (uc *UserController) VerifyEmail(c *gin.Context) {
+	var input request.VerifyEmailInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := uc.Service.VerifyEmail(c.Request.Context(), input.Token); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("Email verified successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// ResendVerification handles POST /verify-email/resend
+func (uc *UserController) ResendVerification(c *gin.Context) {
+	var input request.EmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := uc.Service.ResendVerification(c.Request.Context(), input.Email); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Same answer whether or not the account exists
+	response := response.ToMessageResponse("If the account exists and is unverified, a verification email has been sent")
+	c.JSON(http.StatusOK, response)
+}
+
+// ForgotPassword handles POST /forgot-password
+func (uc *UserController) ForgotPassword(c *gin.Context) {
+	var input request.EmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := uc.Service.RequestPasswordReset(c.Request.Context(), input.Email); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Same answer whether or not the account exists
+	response := response.ToMessageResponse("If the account exists, a password reset email has been sent")
+	c.JSON(http.StatusOK, response)
+}
+
+// ResetPassword handles POST /reset-password
+func (uc *UserController) ResetPassword(c *gin.Context) {
+	var input request.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	if err := uc.Service.ResetPassword(c.Request.Context(), input.Token, input.Password); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("Password reset successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// ListUsers handles GET /users
+func (uc *UserController) ListUsers(c *gin.Context) {
+	var input request.ListUsersInput
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	filter := entities.UserFilter{
+		Query: input.Query,
+		Role:  input.Role,
+		Page:  input.Page,
+		Limit: input.Limit,
+	}.WithDefaults()
+
+	users, total, err := uc.Service.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToUserListResponse(users, filter, total)
+	c.JSON(http.StatusOK, response)
+}
+
+// InviteUser handles POST /users, adding an account to the admin's organization
+func (uc *UserController) InviteUser(c *gin.Context) {
+	var input request.InviteUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	invitation := usecases.Invitation{
+		Name:  input.Name,
+		Email: input.Email,
+		Role:  input.Role,
+	}
+
+	user, err := uc.Service.InviteUser(c.Request.Context(), invitation)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToUserResponse(user)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetUserByID handles GET /users/:id
+func (uc *UserController) GetUserByID(c *gin.Context) {
+	id, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToUserResponse(user)
+	c.JSON(http.StatusOK, response)
+}
+
+// Demote handles POST /users/:id/demote
+func (uc *UserController) Demote(c *gin.Context) {
+	id, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := uc.Service.DemoteToUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("User demoted to user")
+	c.JSON(http.StatusOK, response)
+}
+
+// Disable handles POST /users/:id/disable
+func (uc *UserController) Disable(c *gin.Context) {
+	uc.setDisabled(c, true, "User disabled")
+}
+
+// Enable handles POST /users/:id/enable
+func (uc *UserController) Enable(c *gin.Context) {
+	uc.setDisabled(c, false, "User enabled")
+}
+
+func (uc *UserController) setDisabled(c *gin.Context, disabled bool, message string) {
+	id, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := uc.Service.SetUserDisabled(c.Request.Context(), id, disabled); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse(message)
+	c.JSON(http.StatusOK, response)
+}
+
+// Unlock handles POST /users/:id/unlock
+func (uc *UserController) Unlock(c *gin.Context) {
+	id, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := uc.Service.UnlockUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("User unlocked")
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteUser handles DELETE /users/:id
+func (uc *UserController) DeleteUser(c *gin.Context) {
+	id, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := uc.Service.DeleteUser(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Return response DTO
+	response := response.ToMessageResponse("User deleted successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// userIDParam reads and validates the :id path parameter, reporting an error when it is malformed
+func userIDParam(c *gin.Context) (string, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.Error(errors.InvalidUserIDError{})
+		return "", false
+	}
+	return id.Hex(), true
+}
+
+// loginError reports an error that ended a login step. Account states such as a
+// lockout keep their own answer; any other client error means the caller failed
+// to authenticate, so it is answered with 401 under its own code.
+func loginError(err error) error {
+	var coded errors.CodedError
+	if stderrors.As(err, &coded) && coded.Status() == http.StatusBadRequest {
+		return authenticationFailed{coded}
+	}
+	return err
+}
+
+// authenticationFailed answers a wrapped error with 401
+type authenticationFailed struct {
+	errors.CodedError
+}
+
+func (e authenticationFailed) Status() int   { return http.StatusUnauthorized }
+func (e authenticationFailed) Unwrap() error { return e.CodedError }
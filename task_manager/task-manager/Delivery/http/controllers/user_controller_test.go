@@ -2,13 +2,17 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"testing"
+	"task_manager/Delivery/http/middleware"
 	"task_manager/Delivery/http/response"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -20,7 +24,7 @@ type MockUserUsecase struct {
 	mock.Mock
 }
 
-func (m *MockUserUsecase) Register(user entities.User) (entities.User, error) {
+func (m *MockUserUsecase) Register(ctx context.Context, user entities.User) (entities.User, error) {
 	args := m.Called(user)
 	if args.Get(0) == nil {
 		return entities.User{}, args.Error(1)
@@ -28,12 +32,22 @@ func (m *MockUserUsecase) Register(user entities.User) (entities.User, error) {
 	return args.Get(0).(entities.User), args.Error(1)
 }
 
-func (m *MockUserUsecase) Login(email, password string) (string, error) {
-	args := m.Called(email, password)
+func (m *MockUserUsecase) Login(ctx context.Context, email, password, clientIP string) (usecases.LoginResult, error) {
+	args := m.Called(email, password, clientIP)
+	return args.Get(0).(usecases.LoginResult), args.Error(1)
+}
+
+func (m *MockUserUsecase) VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (string, error) {
+	args := m.Called(mfaToken, code, clientIP)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockUserUsecase) GetUserByEmail(email string) (entities.User, error) {
+func (m *MockUserUsecase) LoginWithIdentity(ctx context.Context, identity entities.ExternalIdentity) (usecases.LoginResult, error) {
+	args := m.Called(identity)
+	return args.Get(0).(usecases.LoginResult), args.Error(1)
+}
+
+func (m *MockUserUsecase) GetUserByEmail(ctx context.Context, email string) (entities.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return entities.User{}, args.Error(1)
@@ -41,16 +55,148 @@ func (m *MockUserUsecase) GetUserByEmail(email string) (entities.User, error) {
 	return args.Get(0).(entities.User), args.Error(1)
 }
 
-func (m *MockUserUsecase) PromoteToAdmin(email string) error {
+func (m *MockUserUsecase) PromoteToAdmin(ctx context.Context, email string) error {
 	args := m.Called(email)
 	return args.Error(0)
 }
 
+func (m *MockUserUsecase) UpdateProfile(ctx context.Context, email string, update usecases.ProfileUpdate) (entities.User, string, error) {
+	args := m.Called(email, update)
+	return args.Get(0).(entities.User), args.String(1), args.Error(2)
+}
+
+func (m *MockUserUsecase) ChangePassword(ctx context.Context, email, currentPassword, newPassword string) error {
+	args := m.Called(email, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) DeleteAccount(ctx context.Context, email, password string) error {
+	args := m.Called(email, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ListUsers(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserUsecase) InviteUser(ctx context.Context, invite usecases.Invitation) (entities.User, error) {
+	args := m.Called(invite)
+	return args.Get(0).(entities.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) GetUserByID(ctx context.Context, id string) (entities.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) DemoteToUser(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) SetUserDisabled(ctx context.Context, id string, disabled bool) error {
+	args := m.Called(id, disabled)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) UnlockUser(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) DeleteUser(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) EnrollMFA(ctx context.Context, email string) (usecases.MFAEnrollment, error) {
+	args := m.Called(email)
+	return args.Get(0).(usecases.MFAEnrollment), args.Error(1)
+}
+
+func (m *MockUserUsecase) ConfirmMFA(ctx context.Context, email, code string) ([]string, error) {
+	args := m.Called(email, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserUsecase) DisableMFA(ctx context.Context, email, password string) error {
+	args := m.Called(email, password)
+	return args.Error(0)
+}
+
 func setupTestRouter(controller *UserController) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
 	r.POST("/register", controller.Register)
 	r.POST("/login", controller.Login)
+	r.POST("/login/mfa", controller.LoginMFA)
+	r.GET("/verify-email", controller.VerifyEmail)
+	r.POST("/verify-email", controller.VerifyEmail)
+	r.POST("/forgot-password", controller.ForgotPassword)
+	r.POST("/reset-password", controller.ResetPassword)
+	return r
+}
+
+// setupAdminTestRouter mounts the admin /users routes without auth middleware
+func setupAdminTestRouter(controller *UserController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.GET("/users", controller.ListUsers)
+	r.POST("/users", controller.InviteUser)
+	r.GET("/users/:id", controller.GetUserByID)
+	r.POST("/users/:id/demote", controller.Demote)
+	r.POST("/users/:id/disable", controller.Disable)
+	r.DELETE("/users/:id", controller.DeleteUser)
+	return r
+}
+
+// setupProfileTestRouter mounts the /me routes with a fake authenticated user
+func setupProfileTestRouter(controller *UserController, email string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	me := r.Group("/me")
+	me.Use(func(c *gin.Context) {
+		c.Set("userEmail", email)
+		c.Set("userRole", "user")
+		c.Next()
+	})
+	me.GET("", controller.GetProfile)
+	me.PATCH("", controller.UpdateProfile)
+	me.POST("/password", controller.ChangePassword)
+	me.DELETE("", controller.DeleteAccount)
+	me.POST("/mfa/enroll", controller.EnrollMFA)
+	me.POST("/mfa/confirm", controller.ConfirmMFA)
+	me.DELETE("/mfa", controller.DisableMFA)
 	return r
 }
 
@@ -122,7 +268,8 @@ func TestUserController_Register_ValidationError(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "Field validation for 'Email' failed on the 'email' tag")
+	assert.Equal(t, "validation_failed", response["code"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "email", "message": "must be a valid email address"}}, response["errors"])
 }
 
 func TestUserController_Register_EmailAlreadyExists(t *testing.T) {
@@ -149,12 +296,13 @@ func TestUserController_Register_EmailAlreadyExists(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	// Assertions
-	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusConflict, w.Code)
 
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "email already exists")
+	assert.Equal(t, "email_taken", response["code"])
+	assert.Equal(t, "email already exists", response["detail"])
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -178,7 +326,7 @@ func TestUserController_Register_InvalidJSON(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid character 'i' looking for beginning of value")
+	assert.Equal(t, "malformed_request", response["code"])
 }
 
 func TestUserController_Login_Success(t *testing.T) {
@@ -188,7 +336,7 @@ func TestUserController_Login_Success(t *testing.T) {
 	router := setupTestRouter(controller)
 
 	// Mock expectations
-	mockUsecase.On("Login", "test@example.com", "password123").Return("jwt-token-123", nil)
+	mockUsecase.On("Login", "test@example.com", "password123", mock.Anything).Return(usecases.LoginResult{Token: "jwt-token-123"}, nil)
 
 	// Test data
 	requestData := map[string]interface{}{
@@ -222,7 +370,7 @@ func TestUserController_Login_InvalidCredentials(t *testing.T) {
 	router := setupTestRouter(controller)
 
 	// Mock expectations
-	mockUsecase.On("Login", "test@example.com", "wrongpassword").Return("", errors.InvalidCredentialsError{})
+	mockUsecase.On("Login", "test@example.com", "wrongpassword", mock.Anything).Return(usecases.LoginResult{}, errors.InvalidCredentialsError{})
 
 	// Test data
 	requestData := map[string]interface{}{
@@ -243,7 +391,7 @@ func TestUserController_Login_InvalidCredentials(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid credentials")
+	assert.Equal(t, "invalid_credentials", response["code"])
 
 	mockUsecase.AssertExpectations(t)
 }
@@ -267,7 +415,7 @@ func TestUserController_Login_InvalidJSON(t *testing.T) {
 	var response map[string]interface{}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "invalid character 'i' looking for beginning of value")
+	assert.Equal(t, "malformed_request", response["code"])
 }
 
 func TestUserResponse_ToMap(t *testing.T) {
@@ -286,13 +434,535 @@ func TestUserResponse_ToMap(t *testing.T) {
 	// Convert to map using JSON marshaling
 	jsonData, err := json.Marshal(userResp)
 	assert.NoError(t, err)
-	
+
 	var userMap map[string]interface{}
 	err = json.Unmarshal(jsonData, &userMap)
 	assert.NoError(t, err)
-	
+
 	assert.Equal(t, "user123", userMap["id"])
 	assert.Equal(t, "Test User", userMap["name"])
 	assert.Equal(t, "test@example.com", userMap["email"])
 	assert.Equal(t, "admin", userMap["role"])
-} 
\ No newline at end of file
+}
+
+func TestUserController_GetProfile_Success(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	user := entities.User{ID: "user123", Name: "Test User", Email: "test@example.com", Password: "hash", Role: "user"}
+	mockUsecase.On("GetUserByEmail", "test@example.com").Return(user, nil)
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", response["id"])
+	assert.Equal(t, "test@example.com", response["email"])
+	assert.NotContains(t, response, "password")
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_UpdateProfile_EmailChange(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	updated := entities.User{ID: "user123", Name: "Test User", Email: "new@example.com", Role: "user"}
+	mockUsecase.On("UpdateProfile", "test@example.com", mock.AnythingOfType("usecases.ProfileUpdate")).Return(updated, "new-token", nil)
+
+	// Test data
+	requestData := map[string]interface{}{
+		"email":            "new@example.com",
+		"current_password": "password123",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("PATCH", "/me", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", response["email"])
+	assert.Equal(t, "new-token", response["token"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_ChangePassword_IncorrectPassword(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	mockUsecase.On("ChangePassword", "test@example.com", "wrongpassword", "newpassword456").Return(errors.IncorrectPasswordError{})
+
+	// Test data
+	requestData := map[string]interface{}{
+		"current_password": "wrongpassword",
+		"new_password":     "newpassword456",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("POST", "/me/password", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "incorrect_password", response["code"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_DeleteAccount_Success(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	mockUsecase.On("DeleteAccount", "test@example.com", "password123").Return(nil)
+
+	// Test data
+	requestData := map[string]interface{}{
+		"password": "password123",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("DELETE", "/me", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_Login_DisabledAccount(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("Login", "test@example.com", "password123", mock.Anything).Return(usecases.LoginResult{}, errors.AccountDisabledError{})
+
+	// Test data
+	requestData := map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password123",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_InviteUser(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupAdminTestRouter(controller)
+
+	// Mock expectations
+	invited := entities.User{ID: "user3", Name: "Carol", Email: "carol@example.com", Role: "user", OrganizationID: "org1"}
+	mockUsecase.On("InviteUser", usecases.Invitation{Name: "Carol", Email: "carol@example.com"}).Return(invited, nil)
+
+	jsonData, _ := json.Marshal(map[string]string{"name": "Carol", "email": "carol@example.com"})
+	req, _ := http.NewRequest("POST", "/users", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created response.UserResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "user3", created.ID)
+	assert.Equal(t, "user", created.Role)
+	mockUsecase.AssertExpectations(t)
+
+	// Only the two roles can be given
+	jsonData, _ = json.Marshal(map[string]string{"name": "Dan", "email": "dan@example.com", "role": "owner"})
+	req, _ = http.NewRequest("POST", "/users", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertNumberOfCalls(t, "InviteUser", 1)
+}
+
+func TestUserController_ListUsers_Success(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupAdminTestRouter(controller)
+
+	// Mock expectations
+	users := []entities.User{
+		{ID: "user1", Name: "Alice", Email: "alice@example.com", Role: "admin"},
+		{ID: "user2", Name: "Bob", Email: "bob@example.com", Role: "user", Disabled: true},
+	}
+	expectedFilter := entities.UserFilter{Query: "example", Page: 2, Limit: 2}
+	mockUsecase.On("ListUsers", expectedFilter).Return(users, int64(6), nil)
+
+	req, _ := http.NewRequest("GET", "/users?q=example&page=2&limit=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(6), response["total"])
+	assert.Equal(t, float64(2), response["page"])
+	assert.Len(t, response["users"], 2)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_Demote_LastAdmin(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupAdminTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("DemoteToUser", "507f1f77bcf86cd799439011").Return(errors.LastAdminError{})
+
+	req, _ := http.NewRequest("POST", "/users/507f1f77bcf86cd799439011/demote", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "last_admin", response["code"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_Disable_InvalidID(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupAdminTestRouter(controller)
+
+	req, _ := http.NewRequest("POST", "/users/not-an-id/disable", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertNotCalled(t, "SetUserDisabled")
+}
+
+func TestUserController_DeleteUser_NotFound(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupAdminTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("DeleteUser", "507f1f77bcf86cd799439012").Return(errors.UserNotFoundError{})
+
+	req, _ := http.NewRequest("DELETE", "/users/507f1f77bcf86cd799439012", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_VerifyEmail_FromLink(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("VerifyEmail", "verify-token").Return(nil)
+
+	req, _ := http.NewRequest("GET", "/verify-email?token=verify-token", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_VerifyEmail_InvalidToken(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("VerifyEmail", "used-token").Return(errors.InvalidActionTokenError{})
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"token": "used-token"})
+	req, _ := http.NewRequest("POST", "/verify-email", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "invalid_action_token", response["code"])
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_ForgotPassword_Success(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("RequestPasswordReset", "test@example.com").Return(nil)
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"email": "test@example.com"})
+	req, _ := http.NewRequest("POST", "/forgot-password", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_ResetPassword_Success(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("ResetPassword", "reset-token", "newpassword456").Return(nil)
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"token": "reset-token", "password": "newpassword456"})
+	req, _ := http.NewRequest("POST", "/reset-password", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_Login_AccountLocked(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	lockErr := errors.AccountLockedError{RetryAfter: 90*time.Second + 200*time.Millisecond}
+	mockUsecase.On("Login", "test@example.com", "password123", mock.Anything).Return(usecases.LoginResult{}, lockErr)
+
+	// Test data
+	requestData := map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password123",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_Login_MFARequired(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("Login", "test@example.com", "password123", mock.Anything).Return(usecases.LoginResult{MFARequired: true, MFAToken: "mfa-token"}, nil)
+
+	// Test data
+	requestData := map[string]interface{}{
+		"email":    "test@example.com",
+		"password": "password123",
+	}
+
+	jsonData, _ := json.Marshal(requestData)
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.False(t, response["success"].(bool))
+	assert.True(t, response["mfa_required"].(bool))
+	assert.Equal(t, "mfa-token", response["mfa_token"])
+	assert.NotContains(t, response, "token")
+
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_LoginMFA(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupTestRouter(controller)
+
+	// Mock expectations
+	mockUsecase.On("VerifyMFALogin", "mfa-token", "123456", mock.Anything).Return("jwt-token-123", nil)
+	mockUsecase.On("VerifyMFALogin", "mfa-token", "000000", mock.Anything).Return("", errors.InvalidMFACodeError{})
+
+	// Valid code
+	jsonData, _ := json.Marshal(map[string]string{"mfa_token": "mfa-token", "code": "123456"})
+	req, _ := http.NewRequest("POST", "/login/mfa", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var loginResponse response.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &loginResponse)
+	assert.True(t, loginResponse.Success)
+	assert.Equal(t, "jwt-token-123", loginResponse.Token)
+
+	// Wrong code
+	jsonData, _ = json.Marshal(map[string]string{"mfa_token": "mfa-token", "code": "000000"})
+	req, _ = http.NewRequest("POST", "/login/mfa", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_MFAEnrollment(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	enrollment := usecases.MFAEnrollment{Secret: "ABCDEF", URI: "otpauth://totp/Task%20Manager:test@example.com?secret=ABCDEF"}
+	mockUsecase.On("EnrollMFA", "test@example.com").Return(enrollment, nil)
+	mockUsecase.On("ConfirmMFA", "test@example.com", "123456").Return([]string{"aaaaa-bbbbb", "ccccc-ddddd"}, nil)
+	mockUsecase.On("ConfirmMFA", "test@example.com", "000000").Return(nil, errors.InvalidMFACodeError{})
+
+	// Enroll
+	req, _ := http.NewRequest("POST", "/me/mfa/enroll", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var enrollResponse response.MFAEnrollmentResponse
+	json.Unmarshal(w.Body.Bytes(), &enrollResponse)
+	assert.Equal(t, "ABCDEF", enrollResponse.Secret)
+	assert.Equal(t, enrollment.URI, enrollResponse.OTPAuthURI)
+
+	// Confirm
+	jsonData, _ := json.Marshal(map[string]string{"code": "123456"})
+	req, _ = http.NewRequest("POST", "/me/mfa/confirm", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var codesResponse response.RecoveryCodesResponse
+	json.Unmarshal(w.Body.Bytes(), &codesResponse)
+	assert.Len(t, codesResponse.RecoveryCodes, 2)
+
+	// Wrong code
+	jsonData, _ = json.Marshal(map[string]string{"code": "000000"})
+	req, _ = http.NewRequest("POST", "/me/mfa/confirm", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestUserController_DisableMFA(t *testing.T) {
+	// Setup
+	mockUsecase := new(MockUserUsecase)
+	controller := NewUserController(mockUsecase)
+	router := setupProfileTestRouter(controller, "test@example.com")
+
+	// Mock expectations
+	mockUsecase.On("DisableMFA", "test@example.com", "wrongpassword").Return(errors.IncorrectPasswordError{})
+
+	jsonData, _ := json.Marshal(map[string]string{"password": "wrongpassword"})
+	req, _ := http.NewRequest("DELETE", "/me/mfa", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockUsecase.AssertExpectations(t)
+}
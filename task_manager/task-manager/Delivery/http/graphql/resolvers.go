@@ -0,0 +1,454 @@
+package graphql
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+	"task_manager/utils"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Viewer is the caller of a request, as authenticated by middleware.AuthMiddleware
+type Viewer struct {
+	ID     string
+	Email  string
+	Role   string
+	APIKey *entities.APIKey // set when the caller used an API key
+}
+
+// IsAdmin checks if the caller has the admin role
+func (v Viewer) IsAdmin() bool {
+	return v.Role == "admin"
+}
+
+// authorize applies the same rules as the HTTP routes: the admin role where
+// required, and the scope for API keys
+func (v Viewer) authorize(scope string, adminOnly bool) error {
+	if adminOnly && !v.IsAdmin() {
+		return errors.ForbiddenError{Message: "admin access required"}
+	}
+	if v.APIKey != nil && !v.APIKey.HasScope(scope) {
+		return errors.ForbiddenError{Message: "API key lacks the " + scope + " scope"}
+	}
+	return nil
+}
+
+// requestState is what resolvers share during one request
+type requestState struct {
+	viewer Viewer
+
+	mu    sync.Mutex
+	users map[string]*entities.User // task creators by email; nil if not found
+}
+
+type requestStateKey struct{}
+
+// WithViewer returns a context for executing a request on behalf of viewer
+func WithViewer(ctx context.Context, viewer Viewer) context.Context {
+	return context.WithValue(ctx, requestStateKey{}, &requestState{viewer: viewer, users: map[string]*entities.User{}})
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		return state
+	}
+	return &requestState{users: map[string]*entities.User{}}
+}
+
+// resolver holds the use cases behind the root fields of queries and mutations
+type resolver struct {
+	tasks usecases.TaskUsecase
+	users usecases.UserUsecase
+}
+
+type taskFilterInput struct {
+	Status    *string
+	Search    *string
+	CreatedBy *string
+	Overdue   *bool
+}
+
+type userFilterInput struct {
+	Search *string
+	Role   *string
+}
+
+type createTaskInput struct {
+	Title       string
+	Description *string
+	DueDate     *gql.Time
+	Status      *string
+}
+
+// updateTaskInput tells an omitted due date from an explicit null, which
+// clears it
+type updateTaskInput struct {
+	Title       *string
+	Description *string
+	DueDate     gql.NullTime
+	Status      *string
+}
+
+func (r *resolver) Tasks(ctx context.Context, args struct {
+	Filter *taskFilterInput
+	Page   int32
+	Limit  int32
+}) (_ *taskPage, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeTasksRead, false); err != nil {
+		return nil, err
+	}
+
+	filter := entities.TaskFilter{Page: int(args.Page), Limit: int(args.Limit)}
+	if input := args.Filter; input != nil {
+		filter.Status = stringValue(input.Status)
+		filter.Query = stringValue(input.Search)
+		filter.CreatedBy = stringValue(input.CreatedBy)
+		filter.Overdue = input.Overdue != nil && *input.Overdue
+	}
+	filter = filter.WithDefaults()
+
+	tasks, err := r.tasks.GetTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, total := filter.Apply(tasks)
+	return &taskPage{pageInfo: pageInfo{page: filter.Page, limit: filter.Limit, total: total}, items: items, root: r}, nil
+}
+
+func (r *resolver) Task(ctx context.Context, args struct{ ID gql.ID }) (_ *taskResolver, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeTasksRead, false); err != nil {
+		return nil, err
+	}
+	id, err := taskID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := r.tasks.GetTaskByID(ctx, id)
+	if stderrors.As(err, new(errors.TaskNotFoundError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &taskResolver{task: task, root: r}, nil
+}
+
+func (r *resolver) Me(ctx context.Context) (_ *userResolver, err error) {
+	defer reportError(&err)
+	viewer := stateFrom(ctx).viewer
+	if err := viewer.authorize(entities.ScopeAccount, false); err != nil {
+		return nil, err
+	}
+
+	user, err := r.users.GetUserByEmail(ctx, viewer.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) Users(ctx context.Context, args struct {
+	Filter *userFilterInput
+	Page   int32
+	Limit  int32
+}) (_ *userPage, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeUsersAdmin, true); err != nil {
+		return nil, err
+	}
+
+	filter := entities.UserFilter{Page: int(args.Page), Limit: int(args.Limit)}
+	if input := args.Filter; input != nil {
+		filter.Query = stringValue(input.Search)
+		filter.Role = stringValue(input.Role)
+	}
+	filter = filter.WithDefaults()
+
+	users, total, err := r.users.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &userPage{pageInfo: pageInfo{page: filter.Page, limit: filter.Limit, total: total}, items: users}, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID gql.ID }) (_ *userResolver, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeUsersAdmin, true); err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, errors.InvalidUserIDError{}
+	}
+
+	user, err := r.users.GetUserByID(ctx, id.Hex())
+	if stderrors.As(err, new(errors.UserNotFoundError)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (_ *taskResolver, err error) {
+	defer reportError(&err)
+	viewer := stateFrom(ctx).viewer
+	if err := viewer.authorize(entities.ScopeTasksWrite, true); err != nil {
+		return nil, err
+	}
+
+	input := args.Input
+	if input.Title == "" {
+		return nil, &errors.ValidationError{Field: "title", Message: "title is required"}
+	}
+	var dueDate time.Time
+	if input.DueDate != nil {
+		dueDate = input.DueDate.Time
+	}
+	task := entities.NewTask(input.Title, stringValue(input.Description), dueDate)
+	if status := stringValue(input.Status); status != "" {
+		if err := utils.ValidateTaskStatus(status); err != nil {
+			return nil, err
+		}
+		task.SetStatus(status)
+	}
+	task.CreatedBy = viewer.Email
+
+	created, err := r.tasks.AddTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return &taskResolver{task: created, root: r}, nil
+}
+
+func (r *resolver) UpdateTask(ctx context.Context, args struct {
+	ID    gql.ID
+	Input updateTaskInput
+}) (_ *taskResolver, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeTasksWrite, true); err != nil {
+		return nil, err
+	}
+	id, err := taskID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	input := args.Input
+	if input.Status != nil {
+		if err := utils.ValidateTaskStatus(*input.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	task, err := r.tasks.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the fields given change
+	if input.Title != nil {
+		task.Title = *input.Title
+	}
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+	if input.DueDate.Set {
+		task.DueDate = time.Time{}
+		if input.DueDate.Value != nil {
+			task.DueDate = input.DueDate.Value.Time
+		}
+	}
+	if input.Status != nil {
+		task.SetStatus(*input.Status)
+	}
+
+	updated, err := r.tasks.UpdateTask(ctx, id, task)
+	if err != nil {
+		return nil, err
+	}
+	return &taskResolver{task: updated, root: r}, nil
+}
+
+func (r *resolver) DeleteTask(ctx context.Context, args struct{ ID gql.ID }) (_ bool, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeTasksWrite, true); err != nil {
+		return false, err
+	}
+	id, err := taskID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.tasks.DeleteTask(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *resolver) PromoteUser(ctx context.Context, args struct{ Email string }) (_ bool, err error) {
+	defer reportError(&err)
+	if err := stateFrom(ctx).viewer.authorize(entities.ScopeUsersAdmin, true); err != nil {
+		return false, err
+	}
+
+	if err := r.users.PromoteToAdmin(ctx, args.Email); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// pageInfo resolves the fields every page has
+type pageInfo struct {
+	page, limit int
+	total       int64
+}
+
+func (p pageInfo) Page() int32  { return int32(p.page) }
+func (p pageInfo) Limit() int32 { return int32(p.limit) }
+func (p pageInfo) Total() int32 { return int32(p.total) }
+
+type taskPage struct {
+	pageInfo
+	items []entities.Task
+	root  *resolver
+}
+
+func (p *taskPage) Items() []*taskResolver {
+	items := make([]*taskResolver, len(p.items))
+	for i, task := range p.items {
+		items[i] = &taskResolver{task: task, root: p.root}
+	}
+	return items
+}
+
+type userPage struct {
+	pageInfo
+	items []entities.User
+}
+
+func (p *userPage) Items() []*userResolver {
+	items := make([]*userResolver, len(p.items))
+	for i, user := range p.items {
+		items[i] = &userResolver{user: user}
+	}
+	return items
+}
+
+type taskResolver struct {
+	task entities.Task
+	root *resolver // looks up the creator
+}
+
+func (t *taskResolver) ID() gql.ID          { return gql.ID(t.task.ID) }
+func (t *taskResolver) Title() string       { return t.task.Title }
+func (t *taskResolver) Description() string { return t.task.Description }
+func (t *taskResolver) Status() string      { return t.task.Status }
+func (t *taskResolver) Overdue() bool       { return t.task.IsOverdue() }
+func (t *taskResolver) CreatedBy() string   { return t.task.CreatedBy }
+
+func (t *taskResolver) DueDate() *gql.Time {
+	if t.task.DueDate.IsZero() {
+		return nil
+	}
+	return &gql.Time{Time: t.task.DueDate}
+}
+
+// Creator looks up the task's creator once per request, however many of
+// their tasks are listed
+func (t *taskResolver) Creator(ctx context.Context) (_ *userResolver, err error) {
+	defer reportError(&err)
+	email := t.task.CreatedBy
+	if email == "" {
+		return nil, nil
+	}
+
+	state := stateFrom(ctx)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if user, ok := state.users[email]; ok {
+		if user == nil {
+			return nil, nil
+		}
+		return &userResolver{user: *user}, nil
+	}
+
+	user, err := t.root.users.GetUserByEmail(ctx, email)
+	if stderrors.As(err, new(errors.UserNotFoundError)) {
+		state.users[email] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state.users[email] = &user
+	return &userResolver{user: user}, nil
+}
+
+type userResolver struct {
+	user entities.User
+}
+
+func (u *userResolver) ID() gql.ID    { return gql.ID(u.user.ID) }
+func (u *userResolver) Name() string  { return u.user.Name }
+func (u *userResolver) Email() string { return u.user.Email }
+
+// visible reports whether the caller may see the account details, which are
+// for admins and the user
+func (u *userResolver) visible(ctx context.Context) bool {
+	viewer := stateFrom(ctx).viewer
+	return viewer.IsAdmin() || viewer.Email == u.user.Email
+}
+
+func (u *userResolver) Role(ctx context.Context) *string {
+	if !u.visible(ctx) {
+		return nil
+	}
+	return &u.user.Role
+}
+
+func (u *userResolver) Disabled(ctx context.Context) *bool {
+	if !u.visible(ctx) {
+		return nil
+	}
+	return &u.user.Disabled
+}
+
+func (u *userResolver) EmailVerified(ctx context.Context) *bool {
+	if !u.visible(ctx) {
+		return nil
+	}
+	return &u.user.EmailVerified
+}
+
+func (u *userResolver) MFAEnabled(ctx context.Context) *bool {
+	if !u.visible(ctx) {
+		return nil
+	}
+	return &u.user.MFAEnabled
+}
+
+// taskID checks an ID argument is a task ID
+func taskID(id gql.ID) (string, error) {
+	objectID, err := primitive.ObjectIDFromHex(string(id))
+	if err != nil {
+		return "", errors.InvalidTaskIDError{}
+	}
+	return objectID.Hex(), nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,232 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"task_manager/Domain/entities"
+	domainErrors "task_manager/Domain/errors"
+	"task_manager/Infrastructure/services"
+	usecases "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTaskID = "507f1f77bcf86cd799439011"
+
+var (
+	admin = Viewer{ID: "user1", Email: "admin@example.com", Role: "admin"}
+	user  = Viewer{ID: "user2", Email: "user@example.com", Role: "user"}
+)
+
+// testResolvers is the task manager schema with real use cases over mocked
+// repositories
+type testResolvers struct {
+	schema *Schema
+	users  *mocks.MockUserRepository
+	tasks  *mocks.MockTaskRepository
+}
+
+func newTestResolvers(t *testing.T) *testResolvers {
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tr := &testResolvers{
+		users: mocks.NewMockUserRepository(ctrl),
+		tasks: mocks.NewMockTaskRepository(ctrl),
+	}
+	throttle := services.NewMemoryLoginThrottle(100, time.Second, time.Minute, time.Minute)
+	userUsecase := usecases.NewUserUsecase(tr.users, tr.tasks, mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), throttle, services.NewPrometheusMetrics(), usecases.DefaultAuthSettings(), logger)
+	tr.schema = NewTaskManagerSchema(usecases.NewTaskUsecase(tr.tasks), userUsecase, Limits{MaxDepth: 8, MaxComplexity: 2500})
+	return tr
+}
+
+// run executes query as viewer and returns the response as JSON
+func (tr *testResolvers) run(t *testing.T, viewer Viewer, query string, variables map[string]any) (map[string]any, []*Error) {
+	t.Helper()
+	resp := tr.schema.Execute(WithViewer(context.Background(), viewer), Request{Query: query, Variables: variables})
+	require.True(t, resp.Executed(), "request failed: %v", resp.Errors)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	return data, resp.Errors
+}
+
+func TestTasksQuery(t *testing.T) {
+	tr := newTestResolvers(t)
+	past := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	future := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tr.tasks.EXPECT().GetTasks(gomock.Any()).Times(2).Return([]entities.Task{
+		{ID: "t1", Title: "Write report", Status: "Pending", CreatedBy: "admin@example.com", DueDate: past},
+		{ID: "t2", Title: "Review report", Status: "Completed", CreatedBy: "admin@example.com"},
+		{ID: "t3", Title: "Book travel", Status: "Pending", CreatedBy: "gone@example.com", DueDate: future},
+		{ID: "t4", Title: "File expenses", Status: "Pending", CreatedBy: "admin@example.com"},
+	}, nil)
+	// Each creator is looked up once per request
+	tr.users.EXPECT().GetUserByEmail(gomock.Any(), "admin@example.com").Times(2).Return(entities.User{ID: "user1", Name: "Admin", Email: "admin@example.com", Role: "admin"}, nil)
+	tr.users.EXPECT().GetUserByEmail(gomock.Any(), "gone@example.com").Return(entities.User{}, domainErrors.UserNotFoundError{})
+
+	data, errs := tr.run(t, user, `query($filter: TaskFilter) {
+		tasks(filter: $filter, limit: 2) {
+			total page limit
+			items { id dueDate overdue creator { name role } }
+		}
+	}`, map[string]any{"filter": map[string]any{"status": "Pending"}})
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string]any{
+		"total": float64(3), "page": float64(1), "limit": float64(2),
+		"items": []any{
+			map[string]any{"id": "t1", "dueDate": past.Format(time.RFC3339), "overdue": true, "creator": map[string]any{"name": "Admin", "role": nil}},
+			map[string]any{"id": "t3", "dueDate": future.Format(time.RFC3339), "overdue": false, "creator": nil},
+		},
+	}, data["tasks"])
+
+	data, errs = tr.run(t, user, `{ tasks(page: 2, limit: 2, filter: {status: "Pending"}) { items { id creator { email } } } }`, nil)
+	require.Empty(t, errs)
+	assert.Equal(t, []any{map[string]any{"id": "t4", "creator": map[string]any{"email": "admin@example.com"}}},
+		data["tasks"].(map[string]any)["items"])
+}
+
+func TestTaskQuery(t *testing.T) {
+	tr := newTestResolvers(t)
+	tr.tasks.EXPECT().GetTaskByID(gomock.Any(), testTaskID).Return(entities.Task{ID: testTaskID, Title: "Write report"}, nil)
+	tr.tasks.EXPECT().GetTaskByID(gomock.Any(), "507f1f77bcf86cd799439012").Return(entities.Task{}, domainErrors.TaskNotFoundError{})
+
+	data, errs := tr.run(t, user, `{
+		found: task(id: "`+testTaskID+`") { title }
+		missing: task(id: "507f1f77bcf86cd799439012") { title }
+		invalid: task(id: "nope") { title }
+	}`, nil)
+	assert.Equal(t, map[string]any{"title": "Write report"}, data["found"])
+	assert.Nil(t, data["missing"])
+	assert.Nil(t, data["invalid"])
+	require.Len(t, errs, 1)
+	assert.Equal(t, "invalid_task_id", errs[0].Extensions["code"])
+	assert.Equal(t, []any{"invalid"}, errs[0].Path)
+}
+
+func TestAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		viewer Viewer
+		query  string
+	}{
+		{"users needs admin", user, `{ users { total } }`},
+		{"user needs admin", user, `{ user(id: "` + testTaskID + `") { name } }`},
+		{"createTask needs admin", user, `mutation { createTask(input: {title: "x"}) { id } }`},
+		{"deleteTask needs admin", user, `mutation { deleteTask(id: "` + testTaskID + `") }`},
+		{"promoteUser needs admin", user, `mutation { promoteUser(email: "user@example.com") }`},
+		{"API key scope", Viewer{Email: admin.Email, Role: "admin", APIKey: &entities.APIKey{Scopes: []string{entities.ScopeTasksRead}}}, `mutation { deleteTask(id: "` + testTaskID + `") }`},
+		{"API key without tasks:read", Viewer{Email: user.Email, Role: "user", APIKey: &entities.APIKey{Scopes: []string{entities.ScopeAccount}}}, `{ tasks { total } }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := newTestResolvers(t).run(t, tt.viewer, tt.query, nil)
+			require.Len(t, errs, 1)
+			assert.Equal(t, "forbidden", errs[0].Extensions["code"])
+		})
+	}
+}
+
+func TestMeAndPrivateFields(t *testing.T) {
+	tr := newTestResolvers(t)
+	account := entities.User{ID: "user2", Name: "User", Email: user.Email, Role: "user", MFAEnabled: true}
+	tr.users.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(account, nil)
+
+	data, errs := tr.run(t, user, `{ me { email role mfaEnabled } }`, nil)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]any{"email": user.Email, "role": "user", "mfaEnabled": true}, data["me"])
+}
+
+func TestUsersQuery(t *testing.T) {
+	tr := newTestResolvers(t)
+	tr.users.EXPECT().ListUsers(gomock.Any(), entities.UserFilter{Role: "user", Page: 1, Limit: entities.MaxPageSize}).
+		Return([]entities.User{{ID: "user2", Name: "User", Email: user.Email, Role: "user"}}, int64(1), nil)
+
+	data, errs := tr.run(t, admin, `{ users(filter: {role: "user"}, limit: 1000) { total items { email role } } }`, nil)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]any{"total": float64(1), "items": []any{map[string]any{"email": user.Email, "role": "user"}}}, data["users"])
+}
+
+func TestCreateTask(t *testing.T) {
+	tr := newTestResolvers(t)
+	due := time.Date(2027, 1, 31, 17, 0, 0, 0, time.UTC)
+	tr.tasks.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task entities.Task) (entities.Task, error) {
+		assert.Equal(t, "Write report", task.Title)
+		assert.Equal(t, "Pending", task.Status)
+		assert.Equal(t, admin.Email, task.CreatedBy)
+		assert.True(t, task.DueDate.Equal(due))
+		task.ID = testTaskID
+		return task, nil
+	})
+
+	data, errs := tr.run(t, admin, `mutation($input: CreateTaskInput!) { createTask(input: $input) { id title status dueDate } }`,
+		map[string]any{"input": map[string]any{"title": "Write report", "dueDate": "2027-01-31T17:00:00Z"}})
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]any{"id": testTaskID, "title": "Write report", "status": "Pending", "dueDate": "2027-01-31T17:00:00Z"}, data["createTask"])
+
+	for field, input := range map[string]string{
+		"title":  `{title: ""}`,
+		"status": `{title: "Write report", status: "Someday"}`,
+	} {
+		_, errs = tr.run(t, admin, `mutation { createTask(input: `+input+`) { id } }`, nil)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "validation_failed", errs[0].Extensions["code"])
+		assert.Equal(t, field, errs[0].Extensions["field"])
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	tr := newTestResolvers(t)
+	existing := entities.Task{ID: testTaskID, Title: "Write report", Description: "Quarterly", Status: "Pending", DueDate: time.Now()}
+	tr.tasks.EXPECT().GetTaskByID(gomock.Any(), testTaskID).Return(existing, nil)
+	tr.tasks.EXPECT().UpdateTask(gomock.Any(), testTaskID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, task entities.Task) (entities.Task, error) {
+		return task, nil
+	})
+
+	data, errs := tr.run(t, admin, `mutation { updateTask(id: "`+testTaskID+`", input: {status: "Completed", dueDate: null}) { title description status dueDate } }`, nil)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]any{"title": "Write report", "description": "Quarterly", "status": "Completed", "dueDate": nil}, data["updateTask"])
+
+	// An unknown status is rejected before the task is looked up
+	_, errs = tr.run(t, admin, `mutation { updateTask(id: "`+testTaskID+`", input: {status: "Done"}) { status } }`, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "validation_failed", errs[0].Extensions["code"])
+	assert.Equal(t, "status", errs[0].Extensions["field"])
+}
+
+func TestDeleteTask(t *testing.T) {
+	tr := newTestResolvers(t)
+	tr.tasks.EXPECT().DeleteTask(gomock.Any(), testTaskID).Return(nil)
+	tr.tasks.EXPECT().DeleteTask(gomock.Any(), "507f1f77bcf86cd799439012").Return(domainErrors.TaskNotFoundError{})
+
+	data, errs := tr.run(t, admin, `mutation { deleteTask(id: "`+testTaskID+`") }`, nil)
+	require.Empty(t, errs)
+	assert.Equal(t, true, data["deleteTask"])
+
+	data, errs = tr.run(t, admin, `mutation { deleteTask(id: "507f1f77bcf86cd799439012") }`, nil)
+	assert.Nil(t, data)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "task_not_found", errs[0].Extensions["code"])
+}
+
+func TestSchemaSDL(t *testing.T) {
+	sdl := newTestResolvers(t).schema.SDL()
+	for _, want := range []string{
+		"schema {\n  query: Query\n  mutation: Mutation\n}",
+		fmt.Sprintf("  tasks(filter: TaskFilter, page: Int = 1, limit: Int = %d): TaskPage!", entities.DefaultPageSize),
+		"input CreateTaskInput {\n  title: String!",
+		"scalar Time",
+	} {
+		assert.Contains(t, sdl, want)
+	}
+	_, err := json.Marshal(sdl)
+	assert.NoError(t, err)
+}
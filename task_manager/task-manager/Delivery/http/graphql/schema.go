@@ -0,0 +1,250 @@
+// Package graphql serves tasks and users over GraphQL at POST /graphql. The
+// schema is in schema.graphql; graph-gophers/graphql-go parses, validates and
+// executes queries against the resolvers here. Queries are checked against
+// depth and complexity limits before any resolver runs.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecases "task_manager/Usecases"
+
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+//go:embed schema.graphql
+var sdl string
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is the result of a request. Data is absent when the request failed
+// before execution, e.g. on a syntax error or a limit, and null when an error
+// in a non-null root field nulled it.
+type Response struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []*Error        `json:"errors,omitempty"`
+}
+
+// Executed reports whether the operation ran. Requests that did not are
+// answered with 400 Bad Request.
+func (r *Response) Executed() bool {
+	return r.Data != nil
+}
+
+// InternalErrors returns the causes of the errors reported as internal, which
+// are only described in the logs
+func (r *Response) InternalErrors() []error {
+	var causes []error
+	for _, err := range r.Errors {
+		var failed *fieldError
+		if stderrors.As(err.ResolverError, &failed) && failed.cause != nil {
+			causes = append(causes, failed.cause)
+		}
+	}
+	return causes
+}
+
+// Error is a GraphQL error; extensions.code holds its problem code
+type Error = gqlerrors.QueryError
+
+// Limits bound the cost of a query before it runs. Zero means no limit.
+type Limits struct {
+	// MaxDepth is how deeply selection sets may nest; { tasks { items { id } } }
+	// is 3 deep
+	MaxDepth int
+	// MaxComplexity bounds the number of fields a query may resolve, counting
+	// the fields under a paginated field once per item its limit allows
+	MaxComplexity int
+}
+
+// Schema is the task manager schema with its resolvers and limits
+type Schema struct {
+	schema *gql.Schema
+	// parsed is the same schema for gqlparser, which checks variables and
+	// measures complexity
+	parsed *ast.Schema
+	limits Limits
+}
+
+// NewTaskManagerSchema builds the schema over tasks and users. Resolvers call
+// the use cases and authorize each field like the matching HTTP route.
+func NewTaskManagerSchema(taskUsecase usecases.TaskUsecase, userUsecase usecases.UserUsecase, limits Limits) *Schema {
+	root := &resolver{tasks: taskUsecase, users: userUsecase}
+	return &Schema{
+		schema: gql.MustParseSchema(sdl, root,
+			gql.UseStringDescriptions(),
+			gql.MaxDepth(limits.MaxDepth),
+			gql.PanicHandler(panicHandler{}),
+		),
+		parsed: gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl}),
+		limits: limits,
+	}
+}
+
+// SDL returns the schema in the GraphQL schema definition language
+func (s *Schema) SDL() string {
+	return sdl
+}
+
+// Execute validates the request against the schema and the limits, then runs
+// the operation. Resolvers find the caller with WithViewer.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	if errs := s.schema.ValidateWithVariables(req.Query, req.Variables); len(errs) > 0 {
+		for _, err := range errs {
+			switch err.Rule {
+			case "":
+				setCode(err, "GRAPHQL_PARSE_FAILED")
+			case "MaxDepthExceeded":
+				setCode(err, "QUERY_TOO_DEEP")
+			default:
+				setCode(err, "GRAPHQL_VALIDATION_FAILED")
+			}
+		}
+		return &Response{Errors: errs}
+	}
+	if err := s.check(req); err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+
+	result := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	resp := &Response{Data: result.Data, Errors: result.Errors}
+	if !resp.Executed() {
+		// e.g. an operationName the document does not define
+		for _, err := range resp.Errors {
+			if err.Extensions == nil {
+				setCode(err, "GRAPHQL_VALIDATION_FAILED")
+			}
+		}
+	}
+	return resp
+}
+
+// check validates the variables and measures the operation, which graphql-go
+// does not do before running it
+func (s *Schema) check(req Request) *Error {
+	doc, errs := gqlparser.LoadQuery(s.parsed, req.Query)
+	if len(errs) > 0 {
+		return setCode(&Error{Message: errs[0].Message}, "GRAPHQL_VALIDATION_FAILED")
+	}
+	op := doc.Operations.ForName(req.OperationName)
+	if op == nil {
+		// Execution reports the missing operation
+		return nil
+	}
+
+	variables, err := validator.VariableValues(s.parsed, op, req.Variables)
+	if err != nil {
+		return setCode(&Error{Message: err.Error()}, "GRAPHQL_VALIDATION_FAILED")
+	}
+	if complexity := selectionComplexity(op.SelectionSet, variables); s.limits.MaxComplexity > 0 && complexity > s.limits.MaxComplexity {
+		err := &Error{
+			Message:   fmt.Sprintf("The query may resolve %d fields, more than the limit of %d.", complexity, s.limits.MaxComplexity),
+			Locations: []gqlerrors.Location{{Line: op.Position.Line, Column: op.Position.Column}},
+		}
+		return setCode(err, "QUERY_TOO_COMPLEX")
+	}
+	return nil
+}
+
+// selectionComplexity counts the fields a selection may resolve. The fields
+// under one with a limit argument count once per item it allows.
+func selectionComplexity(set ast.SelectionSet, variables map[string]any) int {
+	total := 0
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			multiplier := 1
+			if selection.Definition != nil && selection.Definition.Arguments.ForName("limit") != nil {
+				multiplier = pageSize(selection.ArgumentMap(variables)["limit"])
+			}
+			total += 1 + multiplier*selectionComplexity(selection.SelectionSet, variables)
+		case *ast.InlineFragment:
+			total += selectionComplexity(selection.SelectionSet, variables)
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				total += selectionComplexity(selection.Definition.SelectionSet, variables)
+			}
+		}
+	}
+	return total
+}
+
+// pageSize is how many items a limit argument allows, as the use cases cap it
+func pageSize(limit any) int {
+	var n int
+	switch limit := limit.(type) {
+	case int64:
+		n = int(limit)
+	case float64:
+		n = int(limit)
+	case int:
+		n = limit
+	}
+	if n < 1 {
+		return entities.DefaultPageSize
+	}
+	return min(n, entities.MaxPageSize)
+}
+
+func setCode(err *Error, code string) *Error {
+	err.Extensions = map[string]any{"code": code}
+	return err
+}
+
+// fieldError is an error a resolver returned. The problem code of
+// errors.CodedError values goes in extensions.code; other failures are
+// reported as internal errors and keep their cause for the logs.
+type fieldError struct {
+	message    string
+	extensions map[string]any
+	cause      error
+}
+
+func (e *fieldError) Error() string { return e.message }
+
+// Extensions is how graphql-go finds the extensions of a resolver error
+func (e *fieldError) Extensions() map[string]any { return e.extensions }
+
+// reportError converts the error a resolver returns; it is deferred with a
+// pointer to the named result
+func reportError(err *error) {
+	if *err == nil {
+		return
+	}
+	*err = newFieldError(*err)
+}
+
+func newFieldError(err error) *fieldError {
+	var coded errors.CodedError
+	if stderrors.As(err, &coded) {
+		e := &fieldError{message: coded.Error(), extensions: map[string]any{"code": coded.Code()}}
+		var invalid *errors.ValidationError
+		if stderrors.As(err, &invalid) && invalid.Field != "" {
+			e.extensions["field"] = invalid.Field
+		}
+		return e
+	}
+	return &fieldError{message: "Internal server error", extensions: map[string]any{"code": "internal_error"}, cause: err}
+}
+
+// panicHandler reports a resolver that panicked as an internal error
+type panicHandler struct{}
+
+func (panicHandler) MakePanicError(_ context.Context, value any) *Error {
+	err := newFieldError(fmt.Errorf("resolver panicked: %v", value))
+	return &Error{Message: err.message, Extensions: err.extensions, ResolverError: err}
+}
@@ -0,0 +1,70 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"task_manager/Domain/entities"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute_Limits(t *testing.T) {
+	schema := NewTaskManagerSchema(nil, nil, Limits{MaxDepth: 3, MaxComplexity: 100})
+	execute := func(query string, variables map[string]any) *Response {
+		return schema.Execute(WithViewer(context.Background(), user), Request{Query: query, Variables: variables})
+	}
+	code := func(resp *Response) any {
+		t.Helper()
+		require.False(t, resp.Executed())
+		require.Len(t, resp.Errors, 1)
+		return resp.Errors[0].Extensions["code"]
+	}
+
+	// { tasks { items { creator { name } } } } is 4 deep
+	assert.Equal(t, "QUERY_TOO_DEEP", code(execute(`{ tasks { items { creator { name } } } }`, nil)))
+	assert.Equal(t, "QUERY_TOO_DEEP", code(execute(`{ tasks { ...items } } fragment items on TaskPage { items { creator { name } } }`, nil)))
+
+	// Fields under a page count once per item it allows, however the limit is given
+	assert.Equal(t, "QUERY_TOO_COMPLEX", code(execute(`{ tasks(limit: 50) { items { id title } } }`, nil)))
+	assert.Equal(t, "QUERY_TOO_COMPLEX", code(execute(`query($n: Int) { tasks(limit: $n) { items { id title } } }`, map[string]any{"n": float64(50)})))
+	assert.Equal(t, "QUERY_TOO_COMPLEX", code(execute(`{ tasks { items { ...fields } } } fragment fields on Task { id title description status }`, nil)))
+
+	assert.Equal(t, "GRAPHQL_PARSE_FAILED", code(execute(`{ tasks {`, nil)))
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", code(execute(`{ tasks { nope } }`, nil)))
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", code(execute(`query($n: Int) { tasks(limit: $n) { total } }`, map[string]any{"n": "ten"})))
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", code(schema.Execute(context.Background(), Request{Query: `query a { me { id } }`, OperationName: "b"})))
+}
+
+func TestExecute_InternalErrors(t *testing.T) {
+	tr := newTestResolvers(t)
+	tr.tasks.EXPECT().GetTasks(gomock.Any()).Return(nil, errors.New("connection reset"))
+	tr.tasks.EXPECT().GetTaskByID(gomock.Any(), testTaskID).DoAndReturn(func(context.Context, string) (entities.Task, error) {
+		panic("unexpected")
+	})
+
+	resp := tr.schema.Execute(WithViewer(context.Background(), user), Request{
+		Query: `{ tasks { total } task(id: "` + testTaskID + `") { id } }`,
+	})
+	require.True(t, resp.Executed())
+
+	// Neither failure is described to the caller, but both are kept for the logs
+	require.Len(t, resp.Errors, 2)
+	for _, err := range resp.Errors {
+		assert.Equal(t, "Internal server error", err.Message)
+		assert.Equal(t, "internal_error", err.Extensions["code"])
+	}
+	causes := resp.InternalErrors()
+	require.Len(t, causes, 2)
+	assert.ElementsMatch(t, []string{"connection reset", "resolver panicked: unexpected"}, []string{causes[0].Error(), causes[1].Error()})
+}
+
+func TestExecute_Introspection(t *testing.T) {
+	schema := NewTaskManagerSchema(nil, nil, Limits{MaxDepth: 8, MaxComplexity: 2500})
+
+	resp := schema.Execute(WithViewer(context.Background(), user), Request{Query: `{ __type(name: "TaskPage") { fields { name } } }`})
+	require.True(t, resp.Executed(), "request failed: %v", resp.Errors)
+	assert.JSONEq(t, `{"__type": {"fields": [{"name": "items"}, {"name": "page"}, {"name": "limit"}, {"name": "total"}]}}`, string(resp.Data))
+}
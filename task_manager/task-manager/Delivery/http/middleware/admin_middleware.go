@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"net/http"
+	"task_manager/Domain/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,9 +11,9 @@ func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("userRole")
 		if !exists || role != "admin" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			abortWithError(c, errors.ForbiddenError{Message: "admin access required"})
 			return
 		}
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
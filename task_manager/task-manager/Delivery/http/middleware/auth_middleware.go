@@ -1,33 +1,75 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
 	"task_manager/Domain/interfaces"
+	usecases "task_manager/Usecases"
+	"task_manager/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware creates authentication middleware using token service
-func AuthMiddleware(tokenService interfaces.TokenService) gin.HandlerFunc {
+// AuthMiddleware creates authentication middleware using token service.
+// The account is looked up on every request so that disabled or deleted users
+// and role changes take effect without waiting for the token to expire.
+// API keys are accepted as a Bearer token or in the X-API-Key header. The
+// request's context is scoped to the user's organization.
+func AuthMiddleware(tokenService interfaces.TokenService, userUsecase usecases.UserUsecase, apiKeyUsecase usecases.APIKeyUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing or invalid"})
-			return
+		credential := c.GetHeader("X-API-Key")
+		if credential == "" {
+			authHeader := c.GetHeader("Authorization")
+			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+				abortWithError(c, errors.AuthenticationRequiredError{})
+				return
+			}
+			credential = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+
+		var user entities.User
+		if utils.IsAPIKey(credential) {
+			keyUser, key, err := apiKeyUsecase.Authenticate(c.Request.Context(), credential)
+			if err != nil {
+				abortWithError(c, errors.InvalidAPIKeyError{})
+				return
+			}
+			user = keyUser
+			c.Set("apiKey", key)
+		} else {
+			email, _, organizationID, err := tokenService.ValidateToken(credential)
+			if err != nil {
+				abortWithError(c, errors.InvalidTokenError{})
+				return
+			}
+
+			user, err = userUsecase.GetUserByEmail(c.Request.Context(), email)
+			if err != nil {
+				abortWithError(c, errors.InvalidTokenError{})
+				return
+			}
+
+			// Tokens issued before organizations existed name none
+			if organizationID != "" && organizationID != user.OrganizationID {
+				abortWithError(c, errors.InvalidTokenError{})
+				return
+			}
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		email, role, err := tokenService.ValidateToken(tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		if !user.IsActive() {
+			abortWithError(c, errors.AccountDisabledError{})
 			return
 		}
 
 		// Set user data into context
-		c.Set("userEmail", email)
-		c.Set("userRole", role)
+		c.Set("userID", user.ID)
+		c.Set("userEmail", user.Email)
+		c.Set("userRole", user.Role)
+
+		// Whatever the request reads or changes is limited to the user's organization
+		c.Request = c.Request.WithContext(utils.ContextWithOrganization(c.Request.Context(), user.OrganizationID))
 
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes the lifecycle of a group of routes
+type Deprecation struct {
+	Since  time.Time // when the routes were deprecated; zero while they are current
+	Sunset time.Time // when the routes will be removed; zero when not yet decided
+}
+
+// DeprecationMiddleware announces the lifecycle of the routes it guards with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) response headers. Responses of
+// current routes without a sunset date are left alone.
+func DeprecationMiddleware(d Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !d.Since.IsZero() {
+			c.Header("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+		}
+		if !d.Sunset.IsZero() {
+			c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorMiddleware answers requests that ended with an error attached by
+// c.Error and no response written. Handlers and middleware only report the
+// error; this turns the last one into an RFC 7807 problem+json body:
+//
+//   - errors.CodedError values give their own status and code
+//   - errors attached with gin.ErrorTypeBind become validation problems listing
+//     the request fields at fault
+//   - anything else is a 500 whose details stay in the logs
+func ErrorMiddleware() gin.HandlerFunc {
+	fieldNamesOnce.Do(useRequestFieldNames)
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteProblem(c, c.Errors.Last())
+	}
+}
+
+// WriteProblem writes the problem+json response for err and aborts the request
+func WriteProblem(c *gin.Context, err *gin.Error) {
+	problem := toProblem(err)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = c.GetString("requestID")
+
+	var retryable errors.RetryableError
+	if stderrors.As(err.Err, &retryable) {
+		setRetryAfter(c, retryable.RetryAfterDuration().Seconds())
+	}
+
+	c.Abort()
+	c.Header("Content-Type", response.ProblemContentType)
+	c.Status(problem.Status)
+	// Render directly so the problem media type is kept
+	if encodeErr := json.NewEncoder(c.Writer).Encode(problem); encodeErr != nil {
+		_ = c.Error(encodeErr)
+	}
+}
+
+func toProblem(err *gin.Error) response.ProblemResponse {
+	if err.IsType(gin.ErrorTypeBind) {
+		return bindingProblem(err.Err)
+	}
+
+	var coded errors.CodedError
+	if !stderrors.As(err.Err, &coded) {
+		return response.ToProblemResponse(http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+
+	problem := response.ToProblemResponse(coded.Status(), coded.Code(), coded.Error())
+	var invalid *errors.ValidationError
+	if stderrors.As(err.Err, &invalid) && invalid.Field != "" {
+		problem.Errors = []response.FieldErrorResponse{{Field: invalid.Field, Message: invalid.Message}}
+	}
+	return problem
+}
+
+// bindingProblem describes a request body or query that could not be bound
+func bindingProblem(err error) response.ProblemResponse {
+	problem := response.ToProblemResponse(http.StatusBadRequest, (&errors.ValidationError{}).Code(), "The request is invalid")
+
+	var invalidFields validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case stderrors.As(err, &tooLarge):
+		problem = response.ToProblemResponse(http.StatusRequestEntityTooLarge, "request_too_large",
+			fmt.Sprintf("The request body is larger than %d bytes", tooLarge.Limit))
+	case stderrors.As(err, &invalidFields):
+		for _, field := range invalidFields {
+			problem.Errors = append(problem.Errors, response.FieldErrorResponse{
+				Field:   field.Field(),
+				Message: fieldMessage(field),
+			})
+		}
+	case stderrors.As(err, &typeErr):
+		problem.Errors = []response.FieldErrorResponse{{
+			Field:   typeErr.Field,
+			Message: "must be a " + typeErr.Type.String(),
+		}}
+	case stderrors.As(err, &syntaxErr), stderrors.Is(err, io.EOF), stderrors.Is(err, io.ErrUnexpectedEOF):
+		problem.Code = "malformed_request"
+		problem.Detail = "The request body is not valid JSON"
+	default:
+		problem.Detail = err.Error()
+	}
+	return problem
+}
+
+// fieldMessage explains a failed binding rule in words
+func fieldMessage(field validator.FieldError) string {
+	unit := ""
+	if field.Kind() == reflect.String {
+		unit = " characters"
+	}
+
+	switch field.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + field.Param() + unit
+	case "max":
+		return "must be at most " + field.Param() + unit
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(field.Param()), ", ")
+	default:
+		return "failed the " + field.Tag() + " check"
+	}
+}
+
+var fieldNamesOnce sync.Once
+
+// useRequestFieldNames makes binding errors name fields as clients send them,
+// by their json or form key, rather than by the Go struct field
+func useRequestFieldNames() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// abortWithError answers the request with the problem for err straight away, for
+// middleware that stops a request before any handler runs
+func abortWithError(c *gin.Context, err error) {
+	WriteProblem(c, c.Error(err))
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounding up
+func setRetryAfter(c *gin.Context, seconds float64) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(seconds))))
+}
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency headers. IdempotentReplayedHeader marks a response that was
+// stored rather than produced for this request.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength bounds keys; clients usually send a UUID
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored with the body. Others, such
+// as X-Request-ID, describe the request being answered and are set afresh.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// IdempotencyMiddleware lets clients retry POST, PUT, PATCH and DELETE requests
+// safely by sending an Idempotency-Key header. The first response for each key
+// and user is stored for ttl and replayed to retries with the same method,
+// path and body; the change itself is made only once. A key sent with a
+// different request is rejected, as is a retry while the first request still
+// runs. Server errors are not stored, so those requests can be retried.
+// Requests without the header are not affected. It must run after
+// AuthMiddleware, as keys belong to the caller.
+//
+// Requests are told apart by a fingerprint keyed with secret, so that the
+// stored fingerprints of bodies holding passwords cannot be used to guess them.
+func IdempotencyMiddleware(repo interfaces.IdempotencyRepository, ttl time.Duration, secret []byte) gin.HandlerFunc {
+	fingerprintKey := hmac.New(sha256.New, secret)
+	fingerprintKey.Write([]byte("idempotency fingerprint"))
+	fingerprintSecret := fingerprintKey.Sum(nil)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			abortWithError(c, errors.InvalidIdempotencyKeyError{})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := entities.IdempotencyRecord{
+			UserID:      c.GetString("userID"),
+			Key:         key,
+			Fingerprint: requestFingerprint(fingerprintSecret, c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		existing, reserved, err := repo.Reserve(c.Request.Context(), record)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				abortWithError(c, errors.IdempotencyKeyReusedError{})
+			case existing.Response == nil:
+				abortWithError(c, errors.IdempotencyKeyInUseError{RetryAfter: time.Second})
+			default:
+				replay(c, *existing.Response)
+			}
+			return
+		}
+
+		// The outcome is saved even if the client has gone, as it will retry
+		ctx := context.WithoutCancel(c.Request.Context())
+		stored := false
+		defer func() {
+			if !stored {
+				if err := repo.Release(ctx, record.UserID, key); err != nil {
+					_ = c.Error(err)
+				}
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Problems are written here rather than by ErrorMiddleware so that they
+		// are stored as well
+		if len(c.Errors) > 0 && !recorder.Written() {
+			WriteProblem(c, c.Errors.Last())
+		}
+		recorder.WriteHeaderNow()
+		c.Writer = recorder.ResponseWriter
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		response := entities.StoredResponse{
+			Status: recorder.Status(),
+			Header: map[string]string{},
+			Body:   recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				response.Header[name] = value
+			}
+		}
+		if err := repo.Complete(ctx, record.UserID, key, response); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		stored = true
+	}
+}
+
+// replay answers a retry with the stored response
+func replay(c *gin.Context, response entities.StoredResponse) {
+	for name, value := range response.Header {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Abort()
+	c.Status(response.Status)
+	_, _ = c.Writer.Write(response.Body)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// validIdempotencyKey accepts printable ASCII, as the key is stored and logged
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFingerprint identifies a request by its method, path, query and body
+func requestFingerprint(secret []byte, r *http.Request, body []byte) string {
+	hash := hmac.New(sha256.New, secret)
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
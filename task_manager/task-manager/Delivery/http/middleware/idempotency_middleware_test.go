@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey    = "0b0e4f52-6e0c-4a59-9f6d-55a4e2d1c9b7"
+	testSecret = "test-secret-test-secret-test-secret"
+)
+
+// setupIdempotencyRouter serves POST /tasks as user1, counting how often the
+// handler runs
+func setupIdempotencyRouter(repo *mocks.MockIdempotencyRepository, handler gin.HandlerFunc) (*gin.Engine, *int) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RecoveryMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil))), ErrorMiddleware())
+
+	calls := 0
+	setUser := func(c *gin.Context) { c.Set("userID", "user1") }
+	r.POST("/tasks", setUser, IdempotencyMiddleware(repo, time.Hour, []byte(testSecret)), func(c *gin.Context) {
+		calls++
+		handler(c)
+	})
+	r.GET("/tasks", setUser, IdempotencyMiddleware(repo, time.Hour, []byte(testSecret)), func(c *gin.Context) {
+		calls++
+		handler(c)
+	})
+	return r, &calls
+}
+
+func created(c *gin.Context) {
+	c.Header("Location", "/tasks/task1")
+	c.Header("X-Request-ID", "first")
+	c.JSON(http.StatusCreated, gin.H{"id": "task1"})
+}
+
+func sendWithKey(r *gin.Engine, method, body, key string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/tasks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func problemCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var problem map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	code, _ := problem["code"].(string)
+	return code
+}
+
+func TestIdempotencyMiddleware_StoresAndReplays(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+	r, calls := setupIdempotencyRouter(repo, created)
+
+	var reserved entities.IdempotencyRecord
+	repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error) {
+		reserved = record
+		return record, true, nil
+	})
+	repo.EXPECT().Complete(gomock.Any(), "user1", testKey, gomock.Any()).DoAndReturn(func(_ context.Context, _, _ string, response entities.StoredResponse) error {
+		reserved.Response = &response
+		return nil
+	})
+
+	w := sendWithKey(r, http.MethodPost, `{"title":"Report"}`, testKey)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "user1", reserved.UserID)
+	assert.Equal(t, testKey, reserved.Key)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), reserved.ExpiresAt, time.Minute)
+	require.NotNil(t, reserved.Response)
+	assert.Equal(t, http.StatusCreated, reserved.Response.Status)
+	assert.JSONEq(t, `{"id":"task1"}`, string(reserved.Response.Body))
+	assert.Equal(t, map[string]string{"Content-Type": "application/json; charset=utf-8", "Location": "/tasks/task1"}, reserved.Response.Header)
+
+	// A retry gets the same response without running the handler
+	repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(reserved, false, nil)
+	w = sendWithKey(r, http.MethodPost, `{"title":"Report"}`, testKey)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":"task1"}`, w.Body.String())
+	assert.Equal(t, "/tasks/task1", w.Header().Get("Location"))
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, w.Header().Get("X-Request-ID"))
+	assert.Equal(t, 1, *calls)
+
+	// The same key with a different body is refused
+	repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(reserved, false, nil)
+	w = sendWithKey(r, http.MethodPost, `{"title":"Other"}`, testKey)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "idempotency_key_reused", problemCode(t, w))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRequestFingerprint_IsKeyed(t *testing.T) {
+	body := []byte(`{"current_password":"hunter22"}`)
+	req := httptest.NewRequest(http.MethodPost, "/me/password", nil)
+
+	// Without the secret, a stored fingerprint cannot be matched by hashing guesses
+	unkeyed := sha256.Sum256(append([]byte("POST /me/password\n"), body...))
+	fingerprint := requestFingerprint([]byte(testSecret), req, body)
+	assert.NotEqual(t, hex.EncodeToString(unkeyed[:]), fingerprint)
+	assert.NotEqual(t, fingerprint, requestFingerprint([]byte("another-secret"), req, body))
+	assert.Equal(t, fingerprint, requestFingerprint([]byte(testSecret), req, body))
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+	r, calls := setupIdempotencyRouter(repo, created)
+
+	repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error) {
+		return record, false, nil
+	})
+
+	w := sendWithKey(r, http.MethodPost, `{"title":"Report"}`, testKey)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "idempotency_key_in_use", problemCode(t, w))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotencyMiddleware_StoresClientErrors(t *testing.T) {
+	repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+	r, _ := setupIdempotencyRouter(repo, func(c *gin.Context) {
+		c.Error(errors.TaskNotFoundError{})
+	})
+
+	repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error) {
+		return record, true, nil
+	})
+	repo.EXPECT().Complete(gomock.Any(), "user1", testKey, gomock.Any()).DoAndReturn(func(_ context.Context, _, _ string, response entities.StoredResponse) error {
+		assert.Equal(t, http.StatusNotFound, response.Status)
+		assert.Contains(t, string(response.Body), "task_not_found")
+		assert.Equal(t, "application/problem+json", response.Header["Content-Type"])
+		return nil
+	})
+
+	w := sendWithKey(r, http.MethodPost, `{}`, testKey)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "task_not_found", problemCode(t, w))
+}
+
+func TestIdempotencyMiddleware_ReleasesAfterServerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{"error", func(c *gin.Context) { c.Error(io.ErrUnexpectedEOF) }},
+		{"panic", func(c *gin.Context) { panic("boom") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+			r, _ := setupIdempotencyRouter(repo, tt.handler)
+
+			repo.EXPECT().Reserve(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error) {
+				return record, true, nil
+			})
+			repo.EXPECT().Release(gomock.Any(), "user1", testKey).Return(nil)
+
+			w := sendWithKey(r, http.MethodPost, `{}`, testKey)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_Skipped(t *testing.T) {
+	// No repository calls are expected
+	repo := mocks.NewMockIdempotencyRepository(gomock.NewController(t))
+	r, calls := setupIdempotencyRouter(repo, created)
+
+	assert.Equal(t, http.StatusCreated, sendWithKey(r, http.MethodPost, `{}`, "").Code)
+	assert.Equal(t, http.StatusCreated, sendWithKey(r, http.MethodGet, "", testKey).Code)
+	assert.Equal(t, 2, *calls)
+
+	for _, key := range []string{strings.Repeat("k", 256), "café", "line\nbreak"} {
+		w := sendWithKey(r, http.MethodPost, `{}`, key)
+		assert.Equal(t, http.StatusBadRequest, w.Code, key)
+		assert.Equal(t, "invalid_idempotency_key", problemCode(t, w))
+	}
+	assert.Equal(t, 2, *calls)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"task_manager/Domain/interfaces"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware reports every request's status and latency by route. The
+// route template is used rather than the path so IDs do not explode the label
+// values; requests that match no route are grouped together.
+func MetricsMiddleware(metrics interfaces.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
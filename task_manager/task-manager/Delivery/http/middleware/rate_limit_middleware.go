@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"task_manager/Domain/errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts the requests one client made in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimitMiddleware creates a per-client-IP rate limiting middleware allowing
+// limit requests per window. Each call gets its own counters, so groups that
+// use separate instances are limited independently.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		key := c.ClientIP()
+
+		mu.Lock()
+		// Drop finished windows now and then so idle clients don't accumulate
+		if now.Sub(lastSweep) > window {
+			for k, w := range windows {
+				if now.Sub(w.start) >= window {
+					delete(windows, k)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, ok := windows[key]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			windows[key] = w
+		}
+		w.count++
+		count, resetAt := w.count, w.start.Add(window)
+		mu.Unlock()
+
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if count > limit {
+			abortWithError(c, errors.RateLimitedError{RetryAfter: resetAt.Sub(now)})
+			return
+		}
+
+		c.Next()
+	}
+}
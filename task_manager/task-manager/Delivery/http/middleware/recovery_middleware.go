@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware turns a panic in a handler into a 500 problem response,
+// and logs it with its stack trace
+func RecoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// A client that went away is not a server bug, and cannot be answered
+			if err, ok := recovered.(error); ok && isBrokenConnection(err) {
+				logger.WarnContext(c.Request.Context(), "client connection lost", slog.Any("error", err))
+				c.Abort()
+				return
+			}
+
+			logger.ErrorContext(c.Request.Context(), "panic serving request",
+				slog.Any("panic", recovered),
+				slog.String("stack", string(debug.Stack())),
+			)
+			abortWithError(c, fmt.Errorf("panic: %v", recovered))
+		}()
+		c.Next()
+	}
+}
+
+func isBrokenConnection(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"regexp"
+	"task_manager/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the correlation ID of a request and its response
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits which incoming IDs are trusted, so clients cannot inject
+// arbitrary text into the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware gives every request a correlation ID, reusing one set by a
+// proxy or client when it looks sane. The ID is echoed in the response, stored as
+// "requestID" and carried by the request context for logging.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			generated, err := utils.GenerateRandomToken(12)
+			if err != nil {
+				c.Next()
+				return
+			}
+			requestID = generated
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(utils.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
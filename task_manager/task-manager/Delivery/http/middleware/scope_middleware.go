@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScopeMiddleware limits API key requests to keys granted the scope. Requests
+// authenticated with a login token are not restricted.
+func ScopeMiddleware(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := c.Get("apiKey")
+		if ok && !key.(entities.APIKey).HasScope(scope) {
+			abortWithError(c, errors.ForbiddenError{Message: "API key lacks the " + scope + " scope"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnlyMiddleware refuses API keys, for routes such as key management
+// that need an interactive login
+func SessionOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("apiKey"); ok {
+			abortWithError(c, errors.ForbiddenError{Message: "this endpoint cannot be used with an API key"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// untracedPaths are polled by the platform and would only add noise to traces
+var untracedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// TracingMiddleware starts a server span for each request, continuing the trace
+// from an incoming W3C traceparent header. The span travels in the request
+// context, so handlers must pass c.Request.Context() on for their work to
+// appear under it.
+func TracingMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
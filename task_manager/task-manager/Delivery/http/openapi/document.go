@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"task_manager/Delivery/http/response"
+	"unicode"
+)
+
+// Version is the version of the API the document describes
+const Version = "1.0.0"
+
+// pathParam matches a gin path parameter such as :id
+var pathParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// SpecPath turns a gin route path into an OpenAPI path, e.g. /tasks/:id into
+// /tasks/{id}
+func SpecPath(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+// Document builds the OpenAPI 3 document for operations
+func Document(operations []Operation) map[string]any {
+	s := &schemas{components: object{}}
+	problem := s.ref(response.ProblemResponse{}, false)
+
+	paths := object{}
+	for _, op := range operations {
+		item, ok := paths[SpecPath(op.Path)].(object)
+		if !ok {
+			item = object{}
+			paths[SpecPath(op.Path)] = item
+		}
+		item[strings.ToLower(op.Method)] = s.operation(op, problem)
+	}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "Task Manager API",
+			"version":     Version,
+			"description": "Errors are answered with RFC 7807 problem documents carrying a stable `code`.",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": s.components,
+			"securitySchemes": object{
+				"bearerAuth": object{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT or API key",
+				},
+				"apiKeyAuth": object{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-API-Key",
+				},
+			},
+		},
+	}
+}
+
+func (s *schemas) operation(op Operation, problem object) object {
+	operation := object{
+		"tags":        []string{op.Tag},
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+	}
+	if op.Scope != "" {
+		operation["description"] = "API keys need the `" + op.Scope + "` scope."
+	}
+	if op.Deprecated {
+		operation["deprecated"] = true
+	}
+
+	var parameters []object
+	for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		parameters = append(parameters, object{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   object{"type": "string", "pattern": "^[0-9a-f]{24}$"},
+		})
+	}
+	if op.Query != nil {
+		parameters = append(parameters, queryParameters(op.Query)...)
+	}
+	if op.Idempotent {
+		parameters = append(parameters, object{
+			"name":        "Idempotency-Key",
+			"in":          "header",
+			"description": "A unique value, e.g. a UUID. Retries with the same key and body get the first response again instead of repeating the change.",
+			"schema":      object{"type": "string", "maxLength": 255},
+		})
+	}
+	if op.Conditional {
+		parameters = append(parameters, object{
+			"name":        "If-None-Match",
+			"in":          "header",
+			"description": "ETags of representations the client has. If one is current, the answer is 304 without a body.",
+			"schema":      object{"type": "string"},
+		}, object{
+			"name":        "If-Modified-Since",
+			"in":          "header",
+			"description": "Answered with 304 if the resource has not changed since; ignored when If-None-Match is sent.",
+			"schema":      object{"type": "string"},
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if op.Body != nil {
+		operation["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": s.ref(op.Body, true)}},
+		}
+	}
+
+	responses := object{}
+	success := object{"description": http.StatusText(op.Status)}
+	if op.Response != nil {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success["content"] = object{contentType: object{"schema": s.ref(op.Response, false)}}
+	}
+	if op.Conditional {
+		success["headers"] = object{
+			"ETag":          object{"schema": object{"type": "string"}},
+			"Last-Modified": object{"description": "When a single task last changed, if known", "schema": object{"type": "string"}},
+		}
+		responses[strconv.Itoa(http.StatusNotModified)] = object{"description": http.StatusText(http.StatusNotModified)}
+	}
+	responses[strconv.Itoa(op.Status)] = success
+
+	for _, status := range errorStatuses(op) {
+		responses[strconv.Itoa(status)] = object{
+			"description": http.StatusText(status),
+			"content":     object{response.ProblemContentType: object{"schema": problem}},
+		}
+	}
+	operation["responses"] = responses
+
+	switch op.Access {
+	case Authenticated, AdminOnly:
+		operation["security"] = []object{{"bearerAuth": []string{}}, {"apiKeyAuth": []string{}}}
+	case SessionOnly:
+		operation["security"] = []object{{"bearerAuth": []string{}}}
+	}
+	return operation
+}
+
+// errorStatuses lists the problem responses an operation may give
+func errorStatuses(op Operation) []int {
+	statuses := map[int]bool{http.StatusInternalServerError: true}
+	for _, status := range op.Errors {
+		statuses[status] = true
+	}
+	if op.Access != Public {
+		statuses[http.StatusUnauthorized] = true
+		statuses[http.StatusForbidden] = true
+	}
+	if op.RateLimited {
+		statuses[http.StatusTooManyRequests] = true
+	}
+	if op.Idempotent {
+		statuses[http.StatusBadRequest] = true
+		statuses[http.StatusConflict] = true
+		statuses[http.StatusUnprocessableEntity] = true
+	}
+
+	var sorted []int
+	for status := range statuses {
+		sorted = append(sorted, status)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+// operationID names an operation after its method and path, e.g.
+// getApiV1TasksId
+func operationID(op Operation) string {
+	id := strings.ToLower(op.Method)
+	for _, word := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
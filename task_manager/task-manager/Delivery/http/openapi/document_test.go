@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Schemas(t *testing.T) {
+	doc := Document(Operations)
+	schemas := doc["components"].(object)["schemas"].(object)
+
+	register := schemas["RegisterInput"].(object)
+	assert.Equal(t, []string{"name", "email", "password"}, register["required"])
+	properties := register["properties"].(object)
+	assert.Equal(t, "email", properties["email"].(object)["format"])
+	assert.Equal(t, 6, properties["password"].(object)["minLength"])
+
+	// Embedded structs are flattened; omitted fields are optional
+	created := schemas["CreatedAPIKeyResponse"].(object)
+	assert.Contains(t, created["properties"], "key")
+	assert.Contains(t, created["properties"], "prefix")
+	assert.NotContains(t, created["required"], "last_used_at")
+
+	task := schemas["TaskResponse"].(object)["properties"].(object)
+	assert.Equal(t, object{"type": "string", "format": "date-time"}, task["due_date"])
+}
+
+func TestDocument_Operations(t *testing.T) {
+	doc := Document(Operations)
+	paths := doc["paths"].(object)
+
+	get := paths["/api/v1/tasks/{id}"].(object)["get"].(object)
+	assert.Equal(t, "getApiV1TasksId", get["operationId"])
+	assert.Equal(t, "id", get["parameters"].([]object)[0]["name"])
+	assert.Contains(t, get["responses"], "404")
+	assert.Contains(t, get["responses"], "401")
+
+	users := paths["/api/v1/users"].(object)["get"].(object)
+	var names []string
+	for _, parameter := range users["parameters"].([]object) {
+		names = append(names, parameter["name"].(string))
+	}
+	assert.Equal(t, []string{"q", "role", "page", "limit"}, names)
+
+	login := paths["/api/v1/login"].(object)["post"].(object)
+	assert.NotContains(t, login, "security")
+	assert.Contains(t, login["responses"], "429")
+	assert.NotContains(t, login, "parameters")
+
+	create := paths["/api/v2/tasks/"].(object)["post"].(object)
+	assert.Equal(t, "Idempotency-Key", create["parameters"].([]object)[0]["name"])
+	assert.Contains(t, create["responses"], "422")
+
+	// Responses holding secrets are not stored, so retries cannot replay them
+	createKey := paths["/api/v1/me/api-keys"].(object)["post"].(object)
+	assert.NotContains(t, createKey, "parameters")
+	assert.NotContains(t, createKey["responses"], "422")
+	assert.NotContains(t, paths["/api/v1/me"].(object)["patch"], "parameters")
+
+	list := paths["/api/v2/tasks/"].(object)["get"].(object)
+	assert.Equal(t, "If-None-Match", list["parameters"].([]object)[0]["name"])
+	assert.Contains(t, list["responses"], "304")
+	assert.NotContains(t, create["responses"], "304")
+}
+
+func TestDocument_ReferencesResolve(t *testing.T) {
+	doc := Document(Operations)
+	schemas := doc["components"].(object)["schemas"].(object)
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	for _, part := range strings.Split(string(data), `"$ref":"#/components/schemas/`)[1:] {
+		name, _, _ := strings.Cut(part, `"`)
+		assert.Contains(t, schemas, name)
+	}
+}
+
+func TestDeprecate(t *testing.T) {
+	doc := Document(Deprecate(Operations, "/api/v1"))
+	paths := doc["paths"].(object)
+
+	assert.Equal(t, true, paths["/api/v1/tasks/"].(object)["get"].(object)["deprecated"])
+	assert.NotContains(t, paths["/api/v2/tasks/"].(object)["get"], "deprecated")
+	assert.NotContains(t, paths["/healthz"].(object)["get"], "deprecated")
+
+	// Each version describes tasks its own way
+	schemas := doc["components"].(object)["schemas"].(object)
+	assert.Contains(t, schemas["TaskResponseV2"].(object)["properties"], "overdue")
+	assert.NotContains(t, schemas["TaskResponse"].(object)["properties"], "overdue")
+}
@@ -0,0 +1,249 @@
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+)
+
+// Access says who may call an operation
+type Access int
+
+const (
+	// Public routes need no credentials
+	Public Access = iota
+	// Authenticated routes take a login token or an API key
+	Authenticated
+	// SessionOnly routes take a login token but not an API key
+	SessionOnly
+	// AdminOnly routes take a login token or API key of an admin
+	AdminOnly
+)
+
+// Operation documents one route. Paths are written as they are registered
+// with gin, e.g. /tasks/:id.
+type Operation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Access      Access
+	Scope       string // API key scope needed, if any
+	RateLimited bool   // counted against the authentication rate limit
+	Idempotent  bool   // accepts an Idempotency-Key header; see versioned
+	Secret      bool   // the response holds a secret, so it is never stored for retries
+	Conditional bool   // sends ETag and answers 304 to If-None-Match or If-Modified-Since
+	Deprecated  bool   // see Deprecate
+
+	Query       any    // struct bound from the query string
+	Body        any    // struct bound from the JSON body
+	Status      int    // success status
+	Response    any    // success body; nil when there is none
+	ContentType string // of the success body; JSON when empty
+	Errors      []int  // error statuses beyond those implied by Access and RateLimited
+}
+
+// ssoCallbackQuery is what the identity provider sends back to the callback
+type ssoCallbackQuery struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+	Error string `form:"error"`
+}
+
+// graphQLResponse documents graphql.Response, whose data is raw JSON
+type graphQLResponse struct {
+	Data   map[string]any  `json:"data,omitempty"`
+	Errors []graphql.Error `json:"errors,omitempty"`
+}
+
+// Operations lists every route the API serves, in the order they are set up.
+// The versions share every operation; only tasks are represented differently.
+var Operations = slices.Concat(
+	[]Operation{
+		// Token verification keys
+		{Method: http.MethodGet, Path: "/.well-known/jwks.json", Tag: "Authentication", Summary: "Public keys that verify access tokens",
+			Status: http.StatusOK, Response: response.JWKSResponse{}},
+
+		// Single sign-on, when configured
+		{Method: http.MethodGet, Path: "/auth/oidc/login", Tag: "Single sign-on", Summary: "Redirect to the identity provider", RateLimited: true,
+			Status: http.StatusFound},
+		{Method: http.MethodGet, Path: "/auth/oidc/callback", Tag: "Single sign-on", Summary: "Complete a login at the identity provider", RateLimited: true,
+			Query: ssoCallbackQuery{}, Status: http.StatusOK, Response: response.LoginResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}},
+
+		// GraphQL; fields are authorized like the routes they mirror
+		{Method: http.MethodPost, Path: "/graphql", Tag: "GraphQL", Summary: "Run a GraphQL query or mutation", Access: Authenticated,
+			Body: graphql.Request{}, Status: http.StatusOK, Response: graphQLResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge}},
+		{Method: http.MethodGet, Path: "/graphql/schema", Tag: "GraphQL", Summary: "The GraphQL schema in SDL",
+			Status: http.StatusOK, Response: "", ContentType: "text/plain"},
+	},
+	versioned("/api/v1", apiOperations(response.TaskResponse{}, response.TaskListResponse{})),
+	versioned("/api/v2", apiOperations(response.TaskResponseV2{}, response.TaskListResponseV2{})),
+	[]Operation{
+		// Documentation
+		{Method: http.MethodGet, Path: "/openapi.json", Tag: "Documentation", Summary: "This OpenAPI document",
+			Status: http.StatusOK, Response: map[string]any{}},
+		{Method: http.MethodGet, Path: "/docs", Tag: "Documentation", Summary: "Interactive API documentation",
+			Status: http.StatusOK, Response: "", ContentType: "text/html"},
+		{Method: http.MethodGet, Path: "/docs/swagger-ui.css", Tag: "Documentation", Summary: "Styles for the documentation page",
+			Status: http.StatusOK, Response: "", ContentType: "text/css"},
+		{Method: http.MethodGet, Path: "/docs/swagger-ui-bundle.js", Tag: "Documentation", Summary: "Script for the documentation page",
+			Status: http.StatusOK, Response: "", ContentType: "text/javascript"},
+
+		// Operational routes
+		{Method: http.MethodGet, Path: "/healthz", Tag: "Operations", Summary: "Liveness probe",
+			Status: http.StatusOK, Response: response.HealthResponse{}},
+		{Method: http.MethodGet, Path: "/readyz", Tag: "Operations", Summary: "Readiness probe; 503 while a dependency is down",
+			Status: http.StatusOK, Response: response.HealthResponse{}},
+		{Method: http.MethodGet, Path: "/metrics", Tag: "Operations", Summary: "Prometheus metrics",
+			Status: http.StatusOK, Response: "", ContentType: "text/plain"},
+	},
+)
+
+// apiOperations lists the routes of a version of the API, relative to its
+// prefix, given how the version represents tasks
+func apiOperations(task, taskList any) []Operation {
+	return []Operation{
+		// Public authentication routes
+		{Method: http.MethodPost, Path: "/register", Tag: "Authentication", Summary: "Register a new account", RateLimited: true,
+			Body: request.RegisterInput{}, Status: http.StatusCreated, Response: response.UserResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/organizations", Tag: "Authentication", Summary: "Start an organization with its first admin account", RateLimited: true,
+			Body: request.CreateOrganizationInput{}, Status: http.StatusCreated, Response: response.CreateOrganizationResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/login", Tag: "Authentication", Summary: "Log in with email and password", RateLimited: true,
+			Body: request.LoginInput{}, Status: http.StatusOK, Response: response.LoginResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}},
+		{Method: http.MethodPost, Path: "/login/mfa", Tag: "Authentication", Summary: "Complete a login with a two-factor code", RateLimited: true,
+			Body: request.MFALoginInput{}, Status: http.StatusOK, Response: response.LoginResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden}},
+		{Method: http.MethodGet, Path: "/verify-email", Tag: "Authentication", Summary: "Verify an email address from the emailed link", RateLimited: true,
+			Query: request.VerifyEmailInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPost, Path: "/verify-email", Tag: "Authentication", Summary: "Verify an email address", RateLimited: true,
+			Body: request.VerifyEmailInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPost, Path: "/verify-email/resend", Tag: "Authentication", Summary: "Send a new verification email", RateLimited: true,
+			Body: request.EmailInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPost, Path: "/forgot-password", Tag: "Authentication", Summary: "Email a password reset link", RateLimited: true,
+			Body: request.EmailInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPost, Path: "/reset-password", Tag: "Authentication", Summary: "Set a new password with a reset token", RateLimited: true,
+			Body: request.ResetPasswordInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+
+		// Self-service account
+		{Method: http.MethodGet, Path: "/me", Tag: "Account", Summary: "Get your profile", Access: Authenticated, Scope: entities.ScopeAccount,
+			Status: http.StatusOK, Response: response.UserResponse{}},
+		{Method: http.MethodGet, Path: "/me/organization", Tag: "Account", Summary: "Get your organization", Access: Authenticated, Scope: entities.ScopeAccount,
+			Status: http.StatusOK, Response: response.OrganizationResponse{},
+			Errors: []int{http.StatusNotFound}},
+		{Method: http.MethodPatch, Path: "/me", Tag: "Account", Summary: "Change your name or email", Access: Authenticated, Scope: entities.ScopeAccount,
+			Secret: true, Body: request.UpdateProfileInput{}, Status: http.StatusOK, Response: response.ProfileUpdateResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/me/password", Tag: "Account", Summary: "Change your password", Access: Authenticated, Scope: entities.ScopeAccount,
+			Body: request.ChangePasswordInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodDelete, Path: "/me", Tag: "Account", Summary: "Delete your account", Access: Authenticated, Scope: entities.ScopeAccount,
+			Body: request.DeleteAccountInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/me/mfa/enroll", Tag: "Account", Summary: "Start two-factor enrollment", Access: Authenticated, Scope: entities.ScopeAccount,
+			Secret: true, Status: http.StatusOK, Response: response.MFAEnrollmentResponse{},
+			Errors: []int{http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/me/mfa/confirm", Tag: "Account", Summary: "Confirm two-factor enrollment", Access: Authenticated, Scope: entities.ScopeAccount,
+			Secret: true, Body: request.MFACodeInput{}, Status: http.StatusOK, Response: response.RecoveryCodesResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodDelete, Path: "/me/mfa", Tag: "Account", Summary: "Turn off two-factor authentication", Access: Authenticated, Scope: entities.ScopeAccount,
+			Body: request.DeleteAccountInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+
+		// API keys
+		{Method: http.MethodGet, Path: "/me/api-keys", Tag: "API keys", Summary: "List your API keys", Access: SessionOnly,
+			Status: http.StatusOK, Response: response.APIKeyListResponse{}},
+		{Method: http.MethodPost, Path: "/me/api-keys", Tag: "API keys", Summary: "Create an API key; the key is only shown once", Access: SessionOnly,
+			Secret: true, Body: request.CreateAPIKeyInput{}, Status: http.StatusCreated, Response: response.CreatedAPIKeyResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodDelete, Path: "/me/api-keys/:id", Tag: "API keys", Summary: "Revoke an API key", Access: SessionOnly,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+		// User management
+		{Method: http.MethodGet, Path: "/users", Tag: "Users", Summary: "List and search users", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Query: request.ListUsersInput{}, Status: http.StatusOK, Response: response.UserListResponse{},
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPost, Path: "/users", Tag: "Users", Summary: "Add an account to your organization and mail it an invitation", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Body: request.InviteUserInput{}, Status: http.StatusCreated, Response: response.UserResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict}},
+		{Method: http.MethodGet, Path: "/users/:id", Tag: "Users", Summary: "Get a user", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.UserResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: "/users/promote", Tag: "Users", Summary: "Make a user an admin", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Body: request.PromoteInput{}, Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: "/users/:id/demote", Tag: "Users", Summary: "Make an admin a regular user", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/users/:id/disable", Tag: "Users", Summary: "Disable an account", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+		{Method: http.MethodPost, Path: "/users/:id/enable", Tag: "Users", Summary: "Enable a disabled account", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: "/users/:id/unlock", Tag: "Users", Summary: "Clear a login lockout", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: "/users/:id", Tag: "Users", Summary: "Delete an account", Access: AdminOnly, Scope: entities.ScopeUsersAdmin,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+
+		// Background jobs
+		{Method: http.MethodGet, Path: "/jobs", Tag: "Jobs", Summary: "List background jobs and their states", Access: AdminOnly, Scope: entities.ScopeJobsRead,
+			Query: request.ListJobsInput{}, Status: http.StatusOK, Response: response.JobListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden}},
+
+		// Tasks
+		{Method: http.MethodGet, Path: "/tasks/", Tag: "Tasks", Summary: "List tasks", Access: Authenticated, Scope: entities.ScopeTasksRead,
+			Conditional: true, Status: http.StatusOK, Response: taskList},
+		{Method: http.MethodGet, Path: "/tasks/:id", Tag: "Tasks", Summary: "Get a task", Access: Authenticated, Scope: entities.ScopeTasksRead,
+			Conditional: true, Status: http.StatusOK, Response: task,
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodPost, Path: "/tasks/", Tag: "Tasks", Summary: "Create a task", Access: AdminOnly, Scope: entities.ScopeTasksWrite,
+			Body: request.CreateTaskInput{}, Status: http.StatusCreated, Response: task,
+			Errors: []int{http.StatusBadRequest}},
+		{Method: http.MethodPut, Path: "/tasks/:id", Tag: "Tasks", Summary: "Replace a task", Access: AdminOnly, Scope: entities.ScopeTasksWrite,
+			Body: request.UpdateTaskInput{}, Status: http.StatusOK, Response: task,
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: http.MethodDelete, Path: "/tasks/:id", Tag: "Tasks", Summary: "Delete a task", Access: AdminOnly, Scope: entities.ScopeTasksWrite,
+			Status: http.StatusOK, Response: response.MessageResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	}
+}
+
+// versioned mounts operations under prefix
+func versioned(prefix string, operations []Operation) []Operation {
+	for i, op := range operations {
+		operations[i].Path = prefix + op.Path
+		// Authenticated changes can be retried with an Idempotency-Key, unless
+		// the response would have to be stored with its secret
+		operations[i].Idempotent = op.Access != Public && op.Method != http.MethodGet && !op.Secret
+	}
+	return operations
+}
+
+// Deprecate returns a copy of operations with those under prefix marked
+// deprecated
+func Deprecate(operations []Operation, prefix string) []Operation {
+	deprecated := slices.Clone(operations)
+	for i, op := range deprecated {
+		if strings.HasPrefix(op.Path, prefix+"/") {
+			deprecated[i].Deprecated = true
+		}
+	}
+	return deprecated
+}
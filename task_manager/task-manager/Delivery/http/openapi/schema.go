@@ -0,0 +1,176 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// object is a JSON object in the document
+type object = map[string]any
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemas collects the component schemas of the request and response types an
+// operation refers to, named after their Go types
+type schemas struct {
+	components object
+}
+
+// ref returns a reference to the schema of v's type, adding it to the
+// components. Input types are described by their binding rules, response types
+// by which fields are always present.
+func (s *schemas) ref(v any, input bool) object {
+	return s.schema(reflect.TypeOf(v), input)
+}
+
+func (s *schemas) schema(t reflect.Type, input bool) object {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		if _, ok := s.components[t.Name()]; !ok {
+			s.components[t.Name()] = s.structSchema(t, input)
+		}
+		return object{"$ref": "#/components/schemas/" + t.Name()}
+	case t.Kind() == reflect.Slice:
+		return object{"type": "array", "items": s.schema(t.Elem(), input)}
+	case t.Kind() == reflect.Map:
+		return object{"type": "object", "additionalProperties": s.schema(t.Elem(), input)}
+	case t.Kind() == reflect.Interface:
+		return object{}
+	default:
+		return scalarSchema(t)
+	}
+}
+
+func scalarSchema(t reflect.Type) object {
+	switch t.Kind() {
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int32:
+		return object{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	default:
+		return object{"type": "string"}
+	}
+}
+
+func (s *schemas) structSchema(t reflect.Type, input bool) object {
+	properties := object{}
+	var required []string
+
+	for _, field := range reflect.VisibleFields(t) {
+		name, omitEmpty := fieldName(field)
+		if field.Anonymous || !field.IsExported() || name == "" {
+			continue
+		}
+
+		property := s.schema(field.Type, input)
+		rules := bindingRules(field)
+		if input {
+			applyRules(property, field.Type, rules)
+		}
+		properties[name] = property
+
+		switch {
+		case input && rules["required"] != nil:
+			required = append(required, name)
+		case !input && !omitEmpty && field.Type.Kind() != reflect.Pointer:
+			required = append(required, name)
+		}
+	}
+
+	schema := object{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// fieldName is the key a field is sent under in JSON, or in the query string
+func fieldName(field reflect.StructField) (string, bool) {
+	for _, tag := range []string{"json", "form"} {
+		name, options, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, strings.Contains(options, "omitempty")
+		}
+	}
+	return field.Name, false
+}
+
+// bindingRules parses a field's binding tag into rule names and parameters
+func bindingRules(field reflect.StructField) map[string][]string {
+	rules := map[string][]string{}
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if name, param, ok := strings.Cut(rule, "="); ok {
+			rules[name] = strings.Fields(param)
+		} else if rule != "" {
+			rules[rule] = []string{}
+		}
+	}
+	return rules
+}
+
+// applyRules describes the binding rules the server enforces on a field
+func applyRules(property object, t reflect.Type, rules map[string][]string) {
+	minKey, maxKey := "minimum", "maximum"
+	switch t.Kind() {
+	case reflect.String:
+		minKey, maxKey = "minLength", "maxLength"
+	case reflect.Slice:
+		minKey, maxKey = "minItems", "maxItems"
+	}
+
+	for rule, params := range rules {
+		switch rule {
+		case "email":
+			property["format"] = "email"
+		case "oneof":
+			property["enum"] = params
+		case "min", "max":
+			limit, err := strconv.Atoi(params[0])
+			if err != nil {
+				continue
+			}
+			if rule == "min" {
+				property[minKey] = limit
+			} else {
+				property[maxKey] = limit
+			}
+		}
+	}
+}
+
+// queryParameters describes the fields of a struct bound from the query string
+func queryParameters(v any) []object {
+	t := reflect.TypeOf(v)
+	var parameters []object
+	for _, field := range reflect.VisibleFields(t) {
+		name := field.Tag.Get("form")
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		schema := scalarSchema(field.Type)
+		rules := bindingRules(field)
+		applyRules(schema, field.Type, rules)
+		parameter := object{"name": name, "in": "query", "schema": schema}
+		if rules["required"] != nil {
+			parameter["required"] = true
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	_ "embed"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// UI is the page served at /docs. It renders the document with Swagger UI,
+// whose scripts and styles are served alongside it from UIAssets.
+//
+//go:embed ui.html
+var UI []byte
+
+// UIAssets holds the Swagger UI distribution the page loads, embedded in the
+// binary so the page needs no CDN
+var UIAssets = swaggerFiles.FS
@@ -0,0 +1,8 @@
+package request
+
+type ListJobsInput struct {
+	Status string `form:"status" binding:"omitempty,oneof=queued running succeeded failed"`
+	Type   string `form:"type"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
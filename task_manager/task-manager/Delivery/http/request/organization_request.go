@@ -0,0 +1,9 @@
+package request
+
+// CreateOrganizationInput starts an organization along with its first account
+type CreateOrganizationInput struct {
+	Organization string `json:"organization" binding:"required"`
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	Password     string `json:"password" binding:"required,min=6"`
+}
@@ -13,4 +13,54 @@ type LoginInput struct {
 
 type PromoteInput struct {
 	Email string `json:"email" binding:"required,email"`
-} 
\ No newline at end of file
+}
+
+type UpdateProfileInput struct {
+	Name            *string `json:"name"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type DeleteAccountInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type InviteUserInput struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty,oneof=admin user"`
+}
+
+type ListUsersInput struct {
+	Query string `form:"q"`
+	Role  string `form:"role" binding:"omitempty,oneof=admin user"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type EmailInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type MFACodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFALoginInput struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// APIKeyResponse represents an API key sent in HTTP responses. The secret itself
+// is never included.
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// ToAPIKeyResponse converts domain APIKey to APIKeyResponse
+func ToAPIKeyResponse(key entities.APIKey) APIKeyResponse {
+	var lastUsedAt *time.Time
+	if !key.LastUsedAt.IsZero() {
+		lastUsedAt = &key.LastUsedAt
+	}
+
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: lastUsedAt,
+	}
+}
+
+// CreatedAPIKeyResponse represents a newly created key, with the secret shown once
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ToCreatedAPIKeyResponse creates a response for a newly created key
+func ToCreatedAPIKeyResponse(key entities.APIKey, secret string) CreatedAPIKeyResponse {
+	return CreatedAPIKeyResponse{
+		APIKeyResponse: ToAPIKeyResponse(key),
+		Key:            secret,
+	}
+}
+
+// APIKeyListResponse represents a user's API keys
+type APIKeyListResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+}
+
+// ToAPIKeyListResponse converts domain API keys to APIKeyListResponse
+func ToAPIKeyListResponse(keys []entities.APIKey) APIKeyListResponse {
+	keyResponses := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		keyResponses = append(keyResponses, ToAPIKeyResponse(key))
+	}
+
+	return APIKeyListResponse{
+		APIKeys: keyResponses,
+	}
+}
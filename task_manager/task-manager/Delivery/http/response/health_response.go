@@ -0,0 +1,21 @@
+package response
+
+// HealthResponse represents the result of a liveness or readiness probe
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// ToHealthResponse creates a health response; the service is healthy when every check passed
+func ToHealthResponse(checks map[string]string) HealthResponse {
+	status := "ok"
+	for _, result := range checks {
+		if result != "ok" {
+			status = "unavailable"
+		}
+	}
+	return HealthResponse{
+		Status: status,
+		Checks: checks,
+	}
+}
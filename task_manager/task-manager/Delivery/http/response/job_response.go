@@ -0,0 +1,65 @@
+package response
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// JobResponse represents a background job sent in HTTP responses. The payload
+// is left out, as it may hold personal data such as an email's recipient.
+type JobResponse struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	RunAt       time.Time  `json:"run_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// ToJobResponse converts domain JobRecord to JobResponse
+func ToJobResponse(job entities.JobRecord) JobResponse {
+	var finishedAt *time.Time
+	if !job.FinishedAt.IsZero() {
+		finishedAt = &job.FinishedAt
+	}
+
+	return JobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		FinishedAt:  finishedAt,
+	}
+}
+
+// JobListResponse represents a page of jobs
+type JobListResponse struct {
+	Jobs  []JobResponse `json:"jobs"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+	Total int64         `json:"total"`
+}
+
+// ToJobListResponse converts a page of domain jobs to JobListResponse
+func ToJobListResponse(jobs []entities.JobRecord, filter entities.JobFilter, total int64) JobListResponse {
+	jobResponses := make([]JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponses = append(jobResponses, ToJobResponse(job))
+	}
+
+	return JobListResponse{
+		Jobs:  jobResponses,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}
+}
@@ -0,0 +1,15 @@
+package response
+
+import "task_manager/Domain/interfaces"
+
+// JWKSResponse represents a JSON Web Key Set (RFC 7517)
+type JWKSResponse struct {
+	Keys []interfaces.JSONWebKey `json:"keys"`
+}
+
+// ToJWKSResponse creates a JWKS response
+func ToJWKSResponse(keys []interfaces.JSONWebKey) JWKSResponse {
+	return JWKSResponse{
+		Keys: keys,
+	}
+}
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// OrganizationResponse represents an organization sent in HTTP responses. The
+// default organization has no creation time.
+type OrganizationResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// CreateOrganizationResponse is the organization just started and its admin
+type CreateOrganizationResponse struct {
+	Organization OrganizationResponse `json:"organization"`
+	Admin        UserResponse         `json:"admin"`
+}
+
+// ToOrganizationResponse converts domain Organization to OrganizationResponse
+func ToOrganizationResponse(organization entities.Organization) OrganizationResponse {
+	var createdAt *time.Time
+	if !organization.CreatedAt.IsZero() {
+		createdAt = &organization.CreatedAt
+	}
+
+	return OrganizationResponse{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,37 @@
+package response
+
+import "net/http"
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// ProblemResponse is the body of every error response. Clients should branch on
+// Code, which is stable; Detail is meant for people and may change.
+type ProblemResponse struct {
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Status    int                  `json:"status"`
+	Detail    string               `json:"detail,omitempty"`
+	Instance  string               `json:"instance,omitempty"`
+	Code      string               `json:"code"`
+	RequestID string               `json:"request_id,omitempty"`
+	Errors    []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+// FieldErrorResponse describes one invalid field of a request
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ToProblemResponse creates a problem response for status. The type is
+// about:blank, so the title is the standard text of the status.
+func ToProblemResponse(status int, code, detail string) ProblemResponse {
+	return ProblemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
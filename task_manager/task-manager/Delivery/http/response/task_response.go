@@ -12,6 +12,7 @@ type TaskResponse struct {
 	Description string    `json:"description"`
 	DueDate     time.Time `json:"due_date"`
 	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by,omitempty"`
 }
 
 // ToTaskResponse converts domain Task to TaskResponse
@@ -22,6 +23,7 @@ func ToTaskResponse(task entities.Task) TaskResponse {
 		Description: task.Description,
 		DueDate:     task.DueDate,
 		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
 	}
 }
 
@@ -36,8 +38,51 @@ func ToTaskListResponse(tasks []entities.Task) TaskListResponse {
 	for _, task := range tasks {
 		taskResponses = append(taskResponses, ToTaskResponse(task))
 	}
-	
+
 	return TaskListResponse{
 		Tasks: taskResponses,
 	}
-} 
\ No newline at end of file
+}
+
+// TaskResponseV2 is the task representation of API v2. It reports whether the
+// task is overdue so clients need not compare dates in their own time zone.
+type TaskResponseV2 struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date"`
+	Status      string    `json:"status"`
+	Overdue     bool      `json:"overdue"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+}
+
+// ToTaskResponseV2 converts domain Task to TaskResponseV2
+func ToTaskResponseV2(task entities.Task) TaskResponseV2 {
+	return TaskResponseV2{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+		Status:      task.Status,
+		Overdue:     task.IsOverdue(),
+		CreatedBy:   task.CreatedBy,
+	}
+}
+
+// TaskListResponseV2 represents a list of tasks in API v2. Data is an empty
+// array, never null, when there are no tasks.
+type TaskListResponseV2 struct {
+	Data []TaskResponseV2 `json:"data"`
+}
+
+// ToTaskListResponseV2 converts domain tasks to TaskListResponseV2
+func ToTaskListResponseV2(tasks []entities.Task) TaskListResponseV2 {
+	taskResponses := make([]TaskResponseV2, 0, len(tasks))
+	for _, task := range tasks {
+		taskResponses = append(taskResponses, ToTaskResponseV2(task))
+	}
+
+	return TaskListResponseV2{
+		Data: taskResponses,
+	}
+}
@@ -1,29 +1,94 @@
 package response
 
-import "task_manager/Domain/entities"
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
 
 // UserResponse represents the user data sent in HTTP responses
 type UserResponse struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	Disabled      bool   `json:"disabled"`
+	EmailVerified bool   `json:"email_verified"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+
+	OrganizationID string `json:"organization_id"`
 }
 
 // ToUserResponse converts domain User to UserResponse
 func ToUserResponse(user entities.User) UserResponse {
+	var lockedUntil *time.Time
+	if user.IsLocked(time.Now()) {
+		lockedUntil = &user.LockedUntil
+	}
+
 	return UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-		Role:  user.Role,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		Disabled:      user.Disabled,
+		EmailVerified: user.EmailVerified,
+		MFAEnabled:    user.MFAEnabled,
+
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         lockedUntil,
+
+		OrganizationID: user.OrganizationID,
 	}
 }
 
-// LoginResponse represents the login response
+// UserListResponse represents a page of users
+type UserListResponse struct {
+	Users []UserResponse `json:"users"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Total int64          `json:"total"`
+}
+
+// ToUserListResponse converts a page of domain users to UserListResponse
+func ToUserListResponse(users []entities.User, filter entities.UserFilter, total int64) UserListResponse {
+	userResponses := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, ToUserResponse(user))
+	}
+
+	return UserListResponse{
+		Users: userResponses,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}
+}
+
+// ProfileUpdateResponse represents the updated profile, with a fresh token
+// when the change invalidated the caller's old one
+type ProfileUpdateResponse struct {
+	UserResponse
+	Token string `json:"token,omitempty"`
+}
+
+// ToProfileUpdateResponse creates a profile update response
+func ToProfileUpdateResponse(user entities.User, token string) ProfileUpdateResponse {
+	return ProfileUpdateResponse{
+		UserResponse: ToUserResponse(user),
+		Token:        token,
+	}
+}
+
+// LoginResponse represents the login response. When a second factor is needed
+// Success is false and MFAToken must be exchanged at /login/mfa.
 type LoginResponse struct {
-	Success bool   `json:"success"`
-	Token   string `json:"token"`
+	Success     bool   `json:"success"`
+	Token       string `json:"token,omitempty"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
 }
 
 // ToLoginResponse creates a login response
@@ -34,6 +99,40 @@ func ToLoginResponse(token string) LoginResponse {
 	}
 }
 
+// ToMFAChallengeResponse creates a login response asking for a second factor
+func ToMFAChallengeResponse(mfaToken string) LoginResponse {
+	return LoginResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}
+}
+
+// MFAEnrollmentResponse represents a pending two-factor enrollment
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ToMFAEnrollmentResponse creates an MFA enrollment response
+func ToMFAEnrollmentResponse(secret, uri string) MFAEnrollmentResponse {
+	return MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+	}
+}
+
+// RecoveryCodesResponse represents the recovery codes issued when MFA is enabled
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ToRecoveryCodesResponse creates a recovery codes response
+func ToRecoveryCodesResponse(codes []string) RecoveryCodesResponse {
+	return RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+}
+
 // MessageResponse represents a simple message response
 type MessageResponse struct {
 	Message string `json:"message"`
@@ -44,4 +143,4 @@ func ToMessageResponse(message string) MessageResponse {
 	return MessageResponse{
 		Message: message,
 	}
-} 
\ No newline at end of file
+}
@@ -1,39 +1,195 @@
 package routers
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
 	"task_manager/Delivery/http/controllers"
 	"task_manager/Delivery/http/middleware"
+	"task_manager/Domain/entities"
 	"task_manager/Domain/interfaces"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, userController *controllers.UserController, taskController *controllers.TaskController, tokenService interfaces.TokenService) {
+// API versions and the prefixes they are mounted under
+const (
+	V1Prefix = "/api/v1"
+	V2Prefix = "/api/v2"
+)
+
+// LegacyDeprecated is when the unversioned paths were deprecated, the release
+// that introduced /api/v1
+var LegacyDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Versioning controls the lifecycle of the API versions
+type Versioning struct {
+	V1 middleware.Deprecation
+
+	// LegacyRedirects keeps the unversioned paths, e.g. /tasks, as redirects to
+	// /api/v1 until LegacySunset
+	LegacyRedirects bool
+	LegacySunset    time.Time
+}
+
+// SetupRoutes registers all routes. The API is served under V1Prefix and
+// V2Prefix, which differ only in how tasks are represented. The token keys and
+// single sign-on stay unversioned, as their URLs are registered with third
+// parties, and so does GraphQL, whose schema evolves without versions.
+// authRateLimit guards the public authentication endpoints; see
+// middleware.RateLimitMiddleware. idempotency replays authenticated changes
+// retried with an Idempotency-Key; see middleware.IdempotencyMiddleware.
+// ssoController is nil when single sign-on is not configured.
+func SetupRoutes(r *gin.Engine, userController *controllers.UserController, taskController *controllers.TaskController, apiKeyController *controllers.APIKeyController, ssoController *controllers.SSOController, graphqlController *controllers.GraphQLController, jobController *controllers.JobController, organizationController *controllers.OrganizationController, tokenService interfaces.TokenService, authRateLimit, idempotency gin.HandlerFunc, versioning Versioning) {
+	authMiddleware := middleware.AuthMiddleware(tokenService, userController.Service, apiKeyController.Service)
+
+	// === Token Verification Keys ===
+	jwksController := controllers.NewJWKSController(tokenService)
+	r.GET("/.well-known/jwks.json", jwksController.JWKS)
+
+	// === Single Sign-on (OpenID Connect) ===
+	if ssoController != nil {
+		ssoRoutes := r.Group("/auth/oidc")
+		ssoRoutes.Use(authRateLimit)
+		{
+			ssoRoutes.GET("/login", ssoController.Login)
+			ssoRoutes.GET("/callback", ssoController.Callback)
+		}
+	}
+
+	// === GraphQL ===
+	r.POST("/graphql", authMiddleware, graphqlController.Query)
+	r.GET("/graphql/schema", graphqlController.SchemaSDL)
+
+	// === Versioned API ===
+	v1 := r.Group(V1Prefix, middleware.DeprecationMiddleware(versioning.V1))
+	setupAPIRoutes(v1, authMiddleware, authRateLimit, idempotency, userController, taskController, apiKeyController, jobController, organizationController)
+
+	v2 := r.Group(V2Prefix)
+	setupAPIRoutes(v2, authMiddleware, authRateLimit, idempotency, userController, controllers.NewTaskControllerV2(taskController.Service), apiKeyController, jobController, organizationController)
+
+	// === Unversioned Paths (transition to /api/v1) ===
+	if versioning.LegacyRedirects {
+		legacyRoutes := r.Group("", middleware.DeprecationMiddleware(middleware.Deprecation{
+			Since:  LegacyDeprecated,
+			Sunset: versioning.LegacySunset,
+		}))
+		for _, route := range r.Routes() {
+			if path, ok := strings.CutPrefix(route.Path, V1Prefix); ok {
+				legacyRoutes.Handle(route.Method, path, redirectTo(V1Prefix))
+			}
+		}
+	}
+}
+
+// setupAPIRoutes registers the routes of one version of the API. idempotency
+// comes after the access checks, so refused requests do not use up keys. It
+// is left off routes whose responses hold secrets, as it stores responses.
+func setupAPIRoutes(api *gin.RouterGroup, authMiddleware, authRateLimit, idempotency gin.HandlerFunc, userController *controllers.UserController, taskController *controllers.TaskController, apiKeyController *controllers.APIKeyController, jobController *controllers.JobController, organizationController *controllers.OrganizationController) {
 	// === Public Routes ===
-	r.POST("/register", userController.Register)
-	r.POST("/login", userController.Login)
+	publicRoutes := api.Group("")
+	publicRoutes.Use(authRateLimit)
+	{
+		publicRoutes.POST("/register", userController.Register)
+		publicRoutes.POST("/organizations", organizationController.CreateOrganization)
+		publicRoutes.POST("/login", userController.Login)
+		publicRoutes.POST("/login/mfa", userController.LoginMFA)
+		publicRoutes.GET("/verify-email", userController.VerifyEmail)
+		publicRoutes.POST("/verify-email", userController.VerifyEmail)
+		publicRoutes.POST("/verify-email/resend", userController.ResendVerification)
+		publicRoutes.POST("/forgot-password", userController.ForgotPassword)
+		publicRoutes.POST("/reset-password", userController.ResetPassword)
+	}
+
+	// === Authenticated Self-service Account Routes ===
+	meRoutes := api.Group("/me")
+	meRoutes.Use(authMiddleware, middleware.ScopeMiddleware(entities.ScopeAccount))
+	{
+		meRoutes.GET("", userController.GetProfile)
+		meRoutes.GET("/organization", organizationController.GetOrganization)
+		meRoutes.PATCH("", userController.UpdateProfile) // a new email comes with a new token
+		meRoutes.POST("/password", idempotency, userController.ChangePassword)
+		meRoutes.DELETE("", idempotency, userController.DeleteAccount)
+		meRoutes.POST("/mfa/enroll", userController.EnrollMFA)
+		meRoutes.POST("/mfa/confirm", userController.ConfirmMFA)
+		meRoutes.DELETE("/mfa", idempotency, userController.DisableMFA)
+	}
+
+	// === API Key Management (login token only) ===
+	apiKeyRoutes := api.Group("/me/api-keys")
+	apiKeyRoutes.Use(authMiddleware, middleware.SessionOnlyMiddleware())
+	{
+		apiKeyRoutes.GET("", apiKeyController.ListAPIKeys)
+		apiKeyRoutes.POST("", apiKeyController.CreateAPIKey)
+		apiKeyRoutes.DELETE("/:id", idempotency, apiKeyController.RevokeAPIKey)
+	}
 
 	// === Admin-only User Management ===
-	adminUserRoutes := r.Group("/users")
-	adminUserRoutes.Use(middleware.AuthMiddleware(tokenService), middleware.AdminMiddleware())
+	adminUserRoutes := api.Group("/users")
+	adminUserRoutes.Use(authMiddleware, middleware.AdminMiddleware(), middleware.ScopeMiddleware(entities.ScopeUsersAdmin), idempotency)
 	{
+		adminUserRoutes.GET("", userController.ListUsers)
+		adminUserRoutes.POST("", userController.InviteUser)
+		adminUserRoutes.GET("/:id", userController.GetUserByID)
 		adminUserRoutes.POST("/promote", userController.Promote)
+		adminUserRoutes.POST("/:id/demote", userController.Demote)
+		adminUserRoutes.POST("/:id/disable", userController.Disable)
+		adminUserRoutes.POST("/:id/enable", userController.Enable)
+		adminUserRoutes.POST("/:id/unlock", userController.Unlock)
+		adminUserRoutes.DELETE("/:id", userController.DeleteUser)
+	}
+
+	// === Admin-only Background Jobs ===
+	adminJobRoutes := api.Group("/jobs")
+	adminJobRoutes.Use(authMiddleware, middleware.AdminMiddleware(), middleware.ScopeMiddleware(entities.ScopeJobsRead))
+	{
+		adminJobRoutes.GET("", jobController.ListJobs)
 	}
 
 	// === Authenticated User Routes (Tasks) ===
-	taskRoutes := r.Group("/tasks")
-	taskRoutes.Use(middleware.AuthMiddleware(tokenService))
+	taskRoutes := api.Group("/tasks")
+	taskRoutes.Use(authMiddleware, middleware.ScopeMiddleware(entities.ScopeTasksRead))
 	{
 		taskRoutes.GET("/", taskController.GetTasks)
 		taskRoutes.GET("/:id", taskController.GetTaskByID)
 	}
 
 	// === Admin-only Task Management ===
-	adminTaskRoutes := r.Group("/tasks")
-	adminTaskRoutes.Use(middleware.AuthMiddleware(tokenService), middleware.AdminMiddleware())
+	adminTaskRoutes := api.Group("/tasks")
+	adminTaskRoutes.Use(authMiddleware, middleware.AdminMiddleware(), middleware.ScopeMiddleware(entities.ScopeTasksWrite), idempotency)
 	{
 		adminTaskRoutes.POST("/", taskController.AddTask)
 		adminTaskRoutes.PUT("/:id", taskController.UpdateTask)
 		adminTaskRoutes.DELETE("/:id", taskController.DeleteTask)
 	}
-} 
\ No newline at end of file
+}
+
+// redirectTo sends a request to the same path under prefix. 308 Permanent
+// Redirect makes clients repeat the method and body.
+func redirectTo(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		target := url.URL{Path: prefix + c.Request.URL.Path, RawQuery: c.Request.URL.RawQuery}
+		c.Redirect(http.StatusPermanentRedirect, target.String())
+	}
+}
+
+// SetupOperationalRoutes registers the health probes and metrics used by the
+// platform rather than API clients. They are neither authenticated nor rate
+// limited, so /metrics should not be exposed outside the private network.
+func SetupOperationalRoutes(r *gin.Engine, healthController *controllers.HealthController, metricsHandler http.Handler) {
+	r.GET("/healthz", healthController.Liveness)
+	r.GET("/readyz", healthController.Readiness)
+	r.GET("/metrics", gin.WrapH(metricsHandler))
+}
+
+// SetupDocsRoutes registers the OpenAPI document and the documentation page
+// with its assets.
+// Every route set up here and in SetupRoutes must be listed in
+// openapi.Operations.
+func SetupDocsRoutes(r *gin.Engine, docsController *controllers.DocsController) {
+	r.GET("/openapi.json", docsController.OpenAPI)
+	r.GET("/docs", docsController.UI)
+	r.GET("/docs/swagger-ui.css", docsController.UIStylesheet)
+	r.GET("/docs/swagger-ui-bundle.js", docsController.UIScript)
+}
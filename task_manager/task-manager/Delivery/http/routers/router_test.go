@@ -0,0 +1,126 @@
+package routers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"task_manager/Delivery/http/controllers"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/openapi"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestRouter registers every route. Handlers past the middleware are
+// never reached, so the controllers need no dependencies.
+func setupTestRouter(t *testing.T, versioning Versioning) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	noop := func(c *gin.Context) { c.Next() }
+	SetupRoutes(r,
+		controllers.NewUserController(nil),
+		controllers.NewTaskController(nil),
+		controllers.NewAPIKeyController(nil),
+		controllers.NewSSOController(nil, false),
+		controllers.NewGraphQLController(graphql.NewTaskManagerSchema(nil, nil, graphql.Limits{})),
+		controllers.NewJobController(nil),
+		controllers.NewOrganizationController(nil),
+		nil,
+		noop,
+		noop,
+		versioning,
+	)
+	SetupOperationalRoutes(r, controllers.NewHealthController(), http.NotFoundHandler())
+	docsController, err := controllers.NewDocsController(openapi.Operations)
+	require.NoError(t, err)
+	SetupDocsRoutes(r, docsController)
+	return r
+}
+
+// TestOpenAPI_CoversRoutes fails when a route is added, changed or removed
+// without updating openapi.Operations, or the other way around
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	r := setupTestRouter(t, Versioning{})
+
+	var registered []string
+	for _, route := range r.Routes() {
+		registered = append(registered, route.Method+" "+route.Path)
+	}
+	var documented []string
+	for _, op := range openapi.Operations {
+		documented = append(documented, op.Method+" "+op.Path)
+	}
+	sort.Strings(registered)
+	sort.Strings(documented)
+
+	assert.Equal(t, registered, documented)
+}
+
+func TestSetupRoutes_Deprecation(t *testing.T) {
+	since := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.July, 1, 0, 0, 0, 0, time.UTC)
+	r := setupTestRouter(t, Versioning{V1: middleware.Deprecation{Since: since, Sunset: sunset}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+	assert.Equal(t, "@1798761600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Jul 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/me", nil))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+
+	// Without legacy redirects the unversioned paths are gone
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSetupRoutes_LegacyRedirects(t *testing.T) {
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+	r := setupTestRouter(t, Versioning{LegacyRedirects: true, LegacySunset: sunset})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/verify-email?token=abc", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "/api/v1/verify-email?token=abc", w.Header().Get("Location"))
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/tasks/507f1f77bcf86cd799439011", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "/api/v1/tasks/507f1f77bcf86cd799439011", w.Header().Get("Location"))
+
+	// Routes outside the API are not redirected
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetupDocsRoutes_ServesUIAssets(t *testing.T) {
+	r := setupTestRouter(t, Versioning{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "https://", "the page must not load anything from elsewhere")
+
+	for path, contentType := range map[string]string{
+		"/docs/swagger-ui.css":       "text/css; charset=utf-8",
+		"/docs/swagger-ui-bundle.js": "text/javascript; charset=utf-8",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), path)
+		assert.Contains(t, w.Body.String(), "swagger-ui", path)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"task_manager/config"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Server runs the HTTP server and, when asked to stop, drains it and closes the
+// application's resources in order
+type Server struct {
+	http            *http.Server
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+	closers         []closer
+}
+
+type closer struct {
+	name  string
+	close func(context.Context) error
+}
+
+// New creates a server for the handler listening on addr
+func New(addr string, handler http.Handler, serverConfig *config.ServerConfig, logger *slog.Logger) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadTimeout:       serverConfig.ReadTimeout,
+			ReadHeaderTimeout: serverConfig.ReadHeaderTimeout,
+			WriteTimeout:      serverConfig.WriteTimeout,
+			IdleTimeout:       serverConfig.IdleTimeout,
+			ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+		},
+		shutdownTimeout: serverConfig.ShutdownTimeout,
+		logger:          logger,
+	}
+}
+
+// NewEngine creates the gin engine for the server. The client address is only
+// taken from X-Forwarded-For on requests from the configured trusted proxies,
+// and otherwise from the connection, so clients cannot choose the address that
+// rate limits and login throttling count them by.
+func NewEngine(serverConfig *config.ServerConfig) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(serverConfig.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// OnShutdown registers a resource to close once requests have drained. They are
+// closed in the reverse order they were registered, like deferred calls, so
+// something registered early can still be used while later ones close.
+func (s *Server) OnShutdown(name string, close func(context.Context) error) {
+	s.closers = append(s.closers, closer{name: name, close: close})
+}
+
+// Run listens on the server's address and serves until ctx is done, then shuts down
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on the listener until ctx is done or serving fails. It then stops
+// accepting connections, waits for in-flight requests and closes the registered
+// resources, all within the shutdown timeout. Resources are closed even if
+// draining runs out of time.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(listener)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// The server failed on its own; still close what was opened
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	case <-ctx.Done():
+		s.logger.Info("Shutting down", "timeout", s.shutdownTimeout.String())
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := s.http.Shutdown(shutdownCtx); shutdownErr != nil {
+		s.logger.Error("In-flight requests did not finish in time", "error", shutdownErr)
+		s.http.Close()
+		err = errors.Join(err, shutdownErr)
+	}
+
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		c := s.closers[i]
+		if closeErr := c.close(shutdownCtx); closeErr != nil {
+			s.logger.Error("Shutdown failed", "resource", c.name, "error", closeErr)
+			err = errors.Join(err, closeErr)
+		}
+	}
+
+	if err == nil {
+		s.logger.Info("Shutdown complete")
+	}
+	return err
+}
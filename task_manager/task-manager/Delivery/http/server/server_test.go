@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/config"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServerConfig(shutdownTimeout time.Duration) *config.ServerConfig {
+	return &config.ServerConfig{
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: time.Second,
+		WriteTimeout:      5 * time.Second,
+		IdleTimeout:       time.Second,
+		ShutdownTimeout:   shutdownTimeout,
+	}
+}
+
+func TestServer_DrainsRequestsThenCloses(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})
+
+	srv := New("", handler, testServerConfig(5*time.Second), slog.New(slog.DiscardHandler))
+	var closed []string
+	srv.OnShutdown("first", func(context.Context) error { closed = append(closed, "first"); return nil })
+	srv.OnShutdown("second", func(context.Context) error { closed = append(closed, "second"); return nil })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, stop := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- srv.Serve(ctx, listener) }()
+
+	// Shut down while a request is in flight
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+	<-started
+	stop()
+
+	// New connections are refused while the request drains
+	time.Sleep(50 * time.Millisecond)
+	_, err = net.DialTimeout("tcp", listener.Addr().String(), time.Second)
+	assert.Error(t, err)
+	assert.Empty(t, closed)
+
+	close(release)
+	assert.Equal(t, "done", <-response)
+	assert.NoError(t, <-result)
+	assert.Equal(t, []string{"second", "first"}, closed)
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	srv := New("", handler, testServerConfig(50*time.Millisecond), slog.New(slog.DiscardHandler))
+	closeErr := errors.New("disconnect failed")
+	srv.OnShutdown("mongodb", func(context.Context) error { return closeErr })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, stop := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- srv.Serve(ctx, listener) }()
+
+	go http.Get("http://" + listener.Addr().String())
+	<-started
+	stop()
+
+	// A request that outlives the deadline is cut off; resources are still closed
+	err = <-result
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, closeErr)
+}
+
+func TestNewEngine_TrustedProxies(t *testing.T) {
+	// send makes a request from remoteAddr claiming to come from forwardedFor
+	send := func(r http.Handler, remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	limited := func(serverConfig *config.ServerConfig) http.Handler {
+		r, err := NewEngine(serverConfig)
+		require.NoError(t, err)
+		r.Use(middleware.ErrorMiddleware(), middleware.RateLimitMiddleware(1, time.Minute))
+		r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+
+	// By default a forged header does not make a client look like a new one
+	r := limited(testServerConfig(time.Second))
+	assert.Equal(t, http.StatusOK, send(r, "203.0.113.7:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, send(r, "203.0.113.7:1234", "198.51.100.2"))
+
+	// Behind a trusted proxy each forwarded client is counted separately
+	serverConfig := testServerConfig(time.Second)
+	serverConfig.TrustedProxies = []string{"10.0.0.0/8"}
+	r = limited(serverConfig)
+	assert.Equal(t, http.StatusOK, send(r, "10.0.0.1:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, send(r, "10.0.0.1:1234", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, send(r, "10.0.0.1:1234", "198.51.100.2"))
+
+	_, err := NewEngine(&config.ServerConfig{TrustedProxies: []string{"not an address"}})
+	assert.Error(t, err)
+}
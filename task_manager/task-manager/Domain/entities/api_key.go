@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// API key scopes. A key can only reach routes covered by its scopes, and never
+// more than its owner's role allows.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	ScopeUsersAdmin = "users:admin"
+	ScopeJobsRead   = "jobs:read"
+	ScopeAccount    = "account"
+)
+
+// APIKeyScopes lists every scope a key may be granted
+var APIKeyScopes = []string{ScopeTasksRead, ScopeTasksWrite, ScopeUsersAdmin, ScopeJobsRead, ScopeAccount}
+
+// APIKey is a long-lived credential for scripts and CI. Only a hash of the
+// secret is kept; Prefix is stored in clear so users can tell keys apart.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+}
+
+// IsExpired checks if the key can no longer be used
+func (k APIKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// HasScope checks if the key was granted a scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope checks if a scope can be granted to a key
+func IsValidScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,12 @@
+package entities
+
+// ExternalIdentity is a user as asserted by an external identity provider. The
+// issuer and subject together identify the user at the provider for good; the
+// email may change.
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// IdempotencyRecord remembers the first request a user sent with an
+// Idempotency-Key, so that retries get the same response instead of repeating
+// the change. Response is nil while that request is still running.
+type IdempotencyRecord struct {
+	UserID      string
+	Key         string
+	Fingerprint string // identifies the method, path and body the key was first used with
+	Response    *StoredResponse
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IsExpired checks if the key may be used for a new request again
+func (r IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// StoredResponse is a response kept for replaying
+type StoredResponse struct {
+	Status int
+	Header map[string]string
+	Body   []byte
+}
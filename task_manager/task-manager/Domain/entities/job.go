@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// Job states. A job is queued until a worker claims it, and queued again with
+// a later RunAt when it fails with attempts left.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// JobStatuses lists every job state
+var JobStatuses = []string{JobQueued, JobRunning, JobSucceeded, JobFailed}
+
+// JobRecord is a unit of background work waiting in, or taken from, the queue.
+// Type names the Job that runs it, which receives Payload.
+type JobRecord struct {
+	ID          string
+	Type        string
+	Payload     []byte // JSON
+	Status      string
+	Attempts    int // runs started so far, including the current one
+	MaxAttempts int
+	RunAt       time.Time // earliest time the next attempt may start
+	LockedUntil time.Time // while running: when another worker may take the job over
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  time.Time // zero until the job succeeds or fails for good
+}
+
+// IsFinished checks if the job will not run again
+func (j JobRecord) IsFinished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+// CanRetry checks if a failed attempt may be followed by another
+func (j JobRecord) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// JobFilter selects a page of jobs, newest first
+type JobFilter struct {
+	Status string
+	Type   string
+	Page   int
+	Limit  int
+}
+
+// WithDefaults returns the filter with page and limit clamped to valid values
+func (f JobFilter) WithDefaults() JobFilter {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+	return f
+}
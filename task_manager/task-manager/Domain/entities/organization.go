@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// DefaultOrganizationID identifies the organization that users join when they
+// register without creating one. Accounts and tasks stored before
+// organizations existed belong to it too.
+const DefaultOrganizationID = "default"
+
+// Organization is a team sharing one deployment. Its users and tasks are
+// never visible to other organizations.
+type Organization struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+// DefaultOrganization returns the organization with DefaultOrganizationID,
+// which exists without being stored
+func DefaultOrganization() Organization {
+	return Organization{ID: DefaultOrganizationID, Name: "Default"}
+}
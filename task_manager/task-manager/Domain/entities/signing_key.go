@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// SigningKey is a private key used to sign access tokens. A key is published from
+// CreatedAt, signs new tokens from ActiveAt until RotateAt and is kept for
+// verification until ExpiresAt, by which time every token it signed has expired.
+type SigningKey struct {
+	ID         string // published as the JWT "kid"
+	Algorithm  string
+	PrivateKey []byte // PKCS #8, DER encoded, then encrypted if Sealed
+	Sealed     bool   // false for keys stored before keys were encrypted
+	CreatedAt  time.Time
+	ActiveAt   time.Time
+	RotateAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// CanSign checks if the key may still sign new tokens
+func (k SigningKey) CanSign(now time.Time) bool {
+	return !now.Before(k.ActiveAt) && now.Before(k.RotateAt)
+}
+
+// CanVerify checks if tokens signed with the key are still accepted
+func (k SigningKey) CanVerify(now time.Time) bool {
+	return now.Before(k.ExpiresAt)
+}
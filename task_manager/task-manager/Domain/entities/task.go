@@ -1,6 +1,9 @@
 package entities
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Task is the core domain entity - pure business logic
 type Task struct {
@@ -9,6 +12,10 @@ type Task struct {
 	Description string
 	DueDate     time.Time
 	Status      string
+	CreatedBy   string
+	UpdatedAt   time.Time // last change; zero for tasks stored before it was recorded
+
+	OrganizationID string // set from the creator's organization when the task is added
 }
 
 // NewTask creates a new task with validation
@@ -39,4 +46,61 @@ func (t *Task) SetStatus(status string) {
 // ValidStatuses returns valid task statuses
 func ValidStatuses() []string {
 	return []string{"Pending", "In Progress", "Completed"}
-} 
\ No newline at end of file
+}
+
+// TaskFilter selects a page of tasks
+type TaskFilter struct {
+	Status    string
+	Query     string // matched against title and description
+	CreatedBy string
+	Overdue   bool // only tasks that are overdue
+	Page      int
+	Limit     int
+}
+
+// WithDefaults returns the filter with page and limit clamped to valid values
+func (f TaskFilter) WithDefaults() TaskFilter {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+	return f
+}
+
+// Matches reports whether the task passes the filter; paging is not applied
+func (f TaskFilter) Matches(task Task) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+	if f.CreatedBy != "" && !strings.EqualFold(task.CreatedBy, f.CreatedBy) {
+		return false
+	}
+	if f.Overdue && !task.IsOverdue() {
+		return false
+	}
+	if f.Query != "" {
+		query := strings.ToLower(f.Query)
+		return strings.Contains(strings.ToLower(task.Title), query) || strings.Contains(strings.ToLower(task.Description), query)
+	}
+	return true
+}
+
+// Apply returns the matching tasks on the filter's page and how many match in all
+func (f TaskFilter) Apply(tasks []Task) ([]Task, int64) {
+	var matching []Task
+	for _, task := range tasks {
+		if f.Matches(task) {
+			matching = append(matching, task)
+		}
+	}
+
+	total := int64(len(matching))
+	start := min((f.Page-1)*f.Limit, len(matching))
+	end := min(start+f.Limit, len(matching))
+	return matching[start:end], total
+}
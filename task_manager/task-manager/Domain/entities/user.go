@@ -1,12 +1,34 @@
 package entities
 
+import "time"
+
 // User is the core domain entity - pure business logic
 type User struct {
-	ID       string
-	Name     string
-	Email    string
-	Password string
-	Role     string
+	ID            string
+	Name          string
+	Email         string
+	Password      string
+	Role          string
+	Disabled      bool
+	EmailVerified bool
+
+	FailedLoginAttempts int
+	LockedUntil         time.Time
+
+	// Two-factor authentication. MFASecret is set on enrollment but only enforced
+	// once MFAEnabled; recovery codes are stored hashed.
+	MFAEnabled       bool
+	MFASecret        string
+	MFARecoveryCodes []string
+	MFALastUsedStep  int64
+
+	// Link to an external identity provider account, set on first SSO login
+	OIDCIssuer  string
+	OIDCSubject string
+
+	// The organization the account belongs to; it only sees that
+	// organization's users and tasks
+	OrganizationID string
 }
 
 // NewUser creates a new user with validation
@@ -16,6 +38,8 @@ func NewUser(name, email, password string) User {
 		Email:    email,
 		Password: password,
 		Role:     "user", // default role
+
+		OrganizationID: DefaultOrganizationID,
 	}
 }
 
@@ -27,4 +51,42 @@ func (u User) IsAdmin() bool {
 // SetRole sets the user role
 func (u *User) SetRole(role string) {
 	u.Role = role
-} 
\ No newline at end of file
+}
+
+// IsActive checks if user is allowed to sign in
+func (u User) IsActive() bool {
+	return !u.Disabled
+}
+
+// Pagination limits for listings
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// IsLocked checks if the account is temporarily locked after failed logins
+func (u User) IsLocked(now time.Time) bool {
+	return u.LockedUntil.After(now)
+}
+
+// UserFilter describes an admin search over user accounts
+type UserFilter struct {
+	Query string // matched against name and email
+	Role  string
+	Page  int
+	Limit int
+}
+
+// WithDefaults returns the filter with page and limit clamped to valid values
+func (f UserFilter) WithDefaults() UserFilter {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+	return f
+}
@@ -0,0 +1,58 @@
+package errors
+
+import "net/http"
+
+// APIKeyNotFoundError occurs when an API key does not exist or belongs to someone else
+type APIKeyNotFoundError struct{}
+
+func (e APIKeyNotFoundError) Error() string {
+	return "API key not found"
+}
+
+func (e APIKeyNotFoundError) Code() string { return "api_key_not_found" }
+func (e APIKeyNotFoundError) Status() int  { return http.StatusNotFound }
+
+// InvalidAPIKeyIDError occurs when an API key ID is malformed
+type InvalidAPIKeyIDError struct{}
+
+func (e InvalidAPIKeyIDError) Error() string {
+	return "invalid API key ID"
+}
+
+func (e InvalidAPIKeyIDError) Code() string { return "invalid_api_key_id" }
+func (e InvalidAPIKeyIDError) Status() int  { return http.StatusBadRequest }
+
+// InvalidScopeError occurs when an API key is requested with an unknown scope, or none
+type InvalidScopeError struct {
+	Scope string
+}
+
+func (e InvalidScopeError) Error() string {
+	if e.Scope == "" {
+		return "at least one scope is required"
+	}
+	return "invalid scope: " + e.Scope
+}
+
+func (e InvalidScopeError) Code() string { return "invalid_scope" }
+func (e InvalidScopeError) Status() int  { return http.StatusBadRequest }
+
+// APIKeyLimitError occurs when a user already has the maximum number of API keys
+type APIKeyLimitError struct{}
+
+func (e APIKeyLimitError) Error() string {
+	return "API key limit reached"
+}
+
+func (e APIKeyLimitError) Code() string { return "api_key_limit_reached" }
+func (e APIKeyLimitError) Status() int  { return http.StatusConflict }
+
+// InvalidAPIKeyError occurs when a presented API key is unknown, revoked or expired
+type InvalidAPIKeyError struct{}
+
+func (e InvalidAPIKeyError) Error() string {
+	return "invalid or expired API key"
+}
+
+func (e InvalidAPIKeyError) Code() string { return "invalid_api_key" }
+func (e InvalidAPIKeyError) Status() int  { return http.StatusUnauthorized }
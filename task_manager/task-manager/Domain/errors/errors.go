@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"net/http"
+	"time"
+)
+
+// CodedError is implemented by every error meant to reach API clients. The code
+// is part of the API contract and never changes once published; the status is
+// the HTTP status the error is answered with.
+type CodedError interface {
+	error
+	Code() string
+	Status() int
+}
+
+// RetryableError is implemented by errors after which the client has to wait
+type RetryableError interface {
+	CodedError
+	RetryAfterDuration() time.Duration
+}
+
+// ValidationError occurs when a value supplied by the client is invalid
+type ValidationError struct {
+	Field   string // the request field at fault, if known
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Code() string { return "validation_failed" }
+func (e *ValidationError) Status() int  { return http.StatusBadRequest }
+
+// AuthenticationRequiredError occurs when a protected route is called without credentials
+type AuthenticationRequiredError struct{}
+
+func (e AuthenticationRequiredError) Error() string {
+	return "authorization header missing or invalid"
+}
+
+func (e AuthenticationRequiredError) Code() string { return "authentication_required" }
+func (e AuthenticationRequiredError) Status() int  { return http.StatusUnauthorized }
+
+// InvalidTokenError occurs when an access token is malformed, expired or belongs to no account
+type InvalidTokenError struct{}
+
+func (e InvalidTokenError) Error() string {
+	return "invalid or expired token"
+}
+
+func (e InvalidTokenError) Code() string { return "invalid_token" }
+func (e InvalidTokenError) Status() int  { return http.StatusUnauthorized }
+
+// ForbiddenError occurs when an authenticated caller may not use a route
+type ForbiddenError struct {
+	Message string
+}
+
+func (e ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e ForbiddenError) Code() string { return "forbidden" }
+func (e ForbiddenError) Status() int  { return http.StatusForbidden }
+
+// RateLimitedError occurs when a client exceeds a request rate limit
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e RateLimitedError) Error() string {
+	return "rate limit exceeded"
+}
+
+func (e RateLimitedError) Code() string                      { return "rate_limited" }
+func (e RateLimitedError) Status() int                       { return http.StatusTooManyRequests }
+func (e RateLimitedError) RetryAfterDuration() time.Duration { return e.RetryAfter }
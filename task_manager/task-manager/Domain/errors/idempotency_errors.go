@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"net/http"
+	"time"
+)
+
+// InvalidIdempotencyKeyError occurs when an Idempotency-Key header is malformed
+type InvalidIdempotencyKeyError struct{}
+
+func (e InvalidIdempotencyKeyError) Error() string {
+	return "Idempotency-Key must be 1 to 255 printable ASCII characters"
+}
+
+func (e InvalidIdempotencyKeyError) Code() string { return "invalid_idempotency_key" }
+func (e InvalidIdempotencyKeyError) Status() int  { return http.StatusBadRequest }
+
+// IdempotencyKeyReusedError occurs when an Idempotency-Key is sent again with a
+// different method, path or body
+type IdempotencyKeyReusedError struct{}
+
+func (e IdempotencyKeyReusedError) Error() string {
+	return "Idempotency-Key was already used for a different request"
+}
+
+func (e IdempotencyKeyReusedError) Code() string { return "idempotency_key_reused" }
+func (e IdempotencyKeyReusedError) Status() int  { return http.StatusUnprocessableEntity }
+
+// IdempotencyKeyInUseError occurs when a request is retried while the first one
+// with the same Idempotency-Key is still running
+type IdempotencyKeyInUseError struct {
+	RetryAfter time.Duration
+}
+
+func (e IdempotencyKeyInUseError) Error() string {
+	return "a request with this Idempotency-Key is still being processed"
+}
+
+func (e IdempotencyKeyInUseError) Code() string                      { return "idempotency_key_in_use" }
+func (e IdempotencyKeyInUseError) Status() int                       { return http.StatusConflict }
+func (e IdempotencyKeyInUseError) RetryAfterDuration() time.Duration { return e.RetryAfter }
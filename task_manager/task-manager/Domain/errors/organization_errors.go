@@ -0,0 +1,13 @@
+package errors
+
+import "net/http"
+
+// OrganizationNotFoundError occurs when an organization does not exist
+type OrganizationNotFoundError struct{}
+
+func (e OrganizationNotFoundError) Error() string {
+	return "organization not found"
+}
+
+func (e OrganizationNotFoundError) Code() string { return "organization_not_found" }
+func (e OrganizationNotFoundError) Status() int  { return http.StatusNotFound }
@@ -1,5 +1,7 @@
 package errors
 
+import "net/http"
+
 // TaskNotFoundError occurs when task is not found
 type TaskNotFoundError struct{}
 
@@ -7,6 +9,9 @@ func (e TaskNotFoundError) Error() string {
 	return "task not found"
 }
 
+func (e TaskNotFoundError) Code() string { return "task_not_found" }
+func (e TaskNotFoundError) Status() int  { return http.StatusNotFound }
+
 // InvalidTaskIDError occurs when task ID is invalid
 type InvalidTaskIDError struct{}
 
@@ -14,6 +19,9 @@ func (e InvalidTaskIDError) Error() string {
 	return "invalid task ID"
 }
 
+func (e InvalidTaskIDError) Code() string { return "invalid_task_id" }
+func (e InvalidTaskIDError) Status() int  { return http.StatusBadRequest }
+
 // TaskCreationError occurs when task creation fails
 type TaskCreationError struct {
 	Message string
@@ -23,6 +31,9 @@ func (e TaskCreationError) Error() string {
 	return e.Message
 }
 
+func (e TaskCreationError) Code() string { return "task_creation_failed" }
+func (e TaskCreationError) Status() int  { return http.StatusInternalServerError }
+
 // TaskUpdateError occurs when task update fails
 type TaskUpdateError struct {
 	Message string
@@ -30,4 +41,7 @@ type TaskUpdateError struct {
 
 func (e TaskUpdateError) Error() string {
 	return e.Message
-} 
\ No newline at end of file
+}
+
+func (e TaskUpdateError) Code() string { return "task_update_failed" }
+func (e TaskUpdateError) Status() int  { return http.StatusInternalServerError }
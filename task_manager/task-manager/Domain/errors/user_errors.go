@@ -1,5 +1,10 @@
 package errors
 
+import (
+	"net/http"
+	"time"
+)
+
 // UserNotFoundError occurs when user is not found
 type UserNotFoundError struct{}
 
@@ -7,6 +12,9 @@ func (e UserNotFoundError) Error() string {
 	return "user not found"
 }
 
+func (e UserNotFoundError) Code() string { return "user_not_found" }
+func (e UserNotFoundError) Status() int  { return http.StatusNotFound }
+
 // EmailAlreadyExistsError occurs when email is already registered
 type EmailAlreadyExistsError struct{}
 
@@ -14,6 +22,9 @@ func (e EmailAlreadyExistsError) Error() string {
 	return "email already exists"
 }
 
+func (e EmailAlreadyExistsError) Code() string { return "email_taken" }
+func (e EmailAlreadyExistsError) Status() int  { return http.StatusConflict }
+
 // InvalidCredentialsError occurs when login credentials are invalid
 type InvalidCredentialsError struct{}
 
@@ -21,6 +32,9 @@ func (e InvalidCredentialsError) Error() string {
 	return "invalid credentials"
 }
 
+func (e InvalidCredentialsError) Code() string { return "invalid_credentials" }
+func (e InvalidCredentialsError) Status() int  { return http.StatusUnauthorized }
+
 // UserPromotionError occurs when user promotion fails
 type UserPromotionError struct {
 	Message string
@@ -28,4 +42,158 @@ type UserPromotionError struct {
 
 func (e UserPromotionError) Error() string {
 	return e.Message
-} 
\ No newline at end of file
+}
+
+func (e UserPromotionError) Code() string { return "role_update_failed" }
+func (e UserPromotionError) Status() int  { return http.StatusInternalServerError }
+
+// IncorrectPasswordError occurs when a user confirms an action with the wrong current password
+type IncorrectPasswordError struct{}
+
+func (e IncorrectPasswordError) Error() string {
+	return "current password is incorrect"
+}
+
+func (e IncorrectPasswordError) Code() string { return "incorrect_password" }
+func (e IncorrectPasswordError) Status() int  { return http.StatusForbidden }
+
+// InvalidUserIDError occurs when user ID is invalid
+type InvalidUserIDError struct{}
+
+func (e InvalidUserIDError) Error() string {
+	return "invalid user ID"
+}
+
+func (e InvalidUserIDError) Code() string { return "invalid_user_id" }
+func (e InvalidUserIDError) Status() int  { return http.StatusBadRequest }
+
+// AccountDisabledError occurs when a disabled account tries to authenticate
+type AccountDisabledError struct{}
+
+func (e AccountDisabledError) Error() string {
+	return "account is disabled"
+}
+
+func (e AccountDisabledError) Code() string { return "account_disabled" }
+func (e AccountDisabledError) Status() int  { return http.StatusForbidden }
+
+// LastAdminError occurs when an action would leave the system without an active admin
+type LastAdminError struct{}
+
+func (e LastAdminError) Error() string {
+	return "cannot remove the last admin"
+}
+
+func (e LastAdminError) Code() string { return "last_admin" }
+func (e LastAdminError) Status() int  { return http.StatusConflict }
+
+// EmailNotVerifiedError occurs when an unverified account tries to log in
+type EmailNotVerifiedError struct{}
+
+func (e EmailNotVerifiedError) Error() string {
+	return "email address not verified"
+}
+
+func (e EmailNotVerifiedError) Code() string { return "email_not_verified" }
+func (e EmailNotVerifiedError) Status() int  { return http.StatusForbidden }
+
+// InvalidActionTokenError occurs when a verification or reset token is invalid, expired or already used
+type InvalidActionTokenError struct{}
+
+func (e InvalidActionTokenError) Error() string {
+	return "invalid or expired token"
+}
+
+func (e InvalidActionTokenError) Code() string { return "invalid_action_token" }
+func (e InvalidActionTokenError) Status() int  { return http.StatusBadRequest }
+
+// AccountLockedError occurs when an account is temporarily locked after repeated failed logins
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e AccountLockedError) Error() string {
+	return "account temporarily locked due to failed login attempts"
+}
+
+func (e AccountLockedError) Code() string                      { return "account_locked" }
+func (e AccountLockedError) Status() int                       { return http.StatusTooManyRequests }
+func (e AccountLockedError) RetryAfterDuration() time.Duration { return e.RetryAfter }
+
+// TooManyAttemptsError occurs when a client is throttled after repeated failed logins
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e TooManyAttemptsError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+func (e TooManyAttemptsError) Code() string                      { return "too_many_attempts" }
+func (e TooManyAttemptsError) Status() int                       { return http.StatusTooManyRequests }
+func (e TooManyAttemptsError) RetryAfterDuration() time.Duration { return e.RetryAfter }
+
+// MFAAlreadyEnabledError occurs when enrolling an account that already uses two-factor authentication
+type MFAAlreadyEnabledError struct{}
+
+func (e MFAAlreadyEnabledError) Error() string {
+	return "two-factor authentication is already enabled"
+}
+
+func (e MFAAlreadyEnabledError) Code() string { return "mfa_already_enabled" }
+func (e MFAAlreadyEnabledError) Status() int  { return http.StatusConflict }
+
+// MFANotEnrolledError occurs when confirming or using two-factor authentication before enrolling
+type MFANotEnrolledError struct{}
+
+func (e MFANotEnrolledError) Error() string {
+	return "two-factor authentication is not enrolled"
+}
+
+func (e MFANotEnrolledError) Code() string { return "mfa_not_enrolled" }
+func (e MFANotEnrolledError) Status() int  { return http.StatusConflict }
+
+// InvalidMFACodeError occurs when a TOTP or recovery code is wrong or already used
+type InvalidMFACodeError struct{}
+
+func (e InvalidMFACodeError) Error() string {
+	return "invalid authentication code"
+}
+
+func (e InvalidMFACodeError) Code() string { return "invalid_mfa_code" }
+func (e InvalidMFACodeError) Status() int  { return http.StatusBadRequest }
+
+// SSOLoginFailedError occurs when the identity provider does not return a valid identity
+type SSOLoginFailedError struct {
+	Reason string // the error reported by the provider, if any
+}
+
+func (e SSOLoginFailedError) Error() string {
+	if e.Reason != "" {
+		return "single sign-on was not completed: " + e.Reason
+	}
+	return "single sign-on failed"
+}
+
+func (e SSOLoginFailedError) Code() string { return "sso_failed" }
+func (e SSOLoginFailedError) Status() int  { return http.StatusUnauthorized }
+
+// SSOAccountNotAllowedError occurs when an SSO identity may not be linked to or provisioned as an account
+type SSOAccountNotAllowedError struct{}
+
+func (e SSOAccountNotAllowedError) Error() string {
+	return "this account cannot sign in with single sign-on"
+}
+
+func (e SSOAccountNotAllowedError) Code() string { return "sso_account_not_allowed" }
+func (e SSOAccountNotAllowedError) Status() int  { return http.StatusForbidden }
+
+// InvalidSSORequestError occurs when a single sign-on callback does not match a login started by this browser
+type InvalidSSORequestError struct{}
+
+func (e InvalidSSORequestError) Error() string {
+	return "invalid or expired single sign-on request"
+}
+
+func (e InvalidSSORequestError) Code() string { return "invalid_sso_request" }
+func (e InvalidSSORequestError) Status() int  { return http.StatusBadRequest }
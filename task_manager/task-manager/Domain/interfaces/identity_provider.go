@@ -0,0 +1,13 @@
+package interfaces
+
+import "task_manager/Domain/entities"
+
+// IdentityProvider interface defines the authorization code flow against an
+// external OpenID Connect provider
+type IdentityProvider interface {
+	// AuthCodeURL returns where to send the user to sign in. The nonce is echoed
+	// in the ID token and the code verifier's challenge binds the code (PKCE).
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	// Exchange redeems the code returned to the callback and validates the ID token
+	Exchange(code, codeVerifier, nonce string) (entities.ExternalIdentity, error)
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// Job is a kind of background work, such as sending an email. Records whose
+// Type matches are handed to Run with their payload. An error makes the
+// attempt fail; it is retried with backoff until attempts run out.
+type Job interface {
+	Type() string
+	Run(ctx context.Context, payload []byte) error
+}
+
+// JobQueue interface defines storage for background jobs, shared by every
+// instance of the service so that any worker may run any job
+type JobQueue interface {
+	// Enqueue stores a queued job, giving it an ID unless it has one. If a job
+	// with that ID exists already, nothing is stored and that job is returned
+	// with false.
+	Enqueue(ctx context.Context, job entities.JobRecord) (entities.JobRecord, bool, error)
+
+	// Claim takes the job that has waited longest to run, marking it running
+	// until now plus lease and counting the attempt. A running job whose lease
+	// has passed is taken over, as its worker is presumed gone. It returns
+	// false when no job is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (entities.JobRecord, bool, error)
+
+	// Finish stores the outcome of a claimed attempt: its Status, RunAt,
+	// LastError, UpdatedAt and FinishedAt. The payload of a job that succeeded
+	// is removed, as it is not needed again and may hold secrets. It returns
+	// false if the attempt was taken over meanwhile and the outcome was
+	// discarded.
+	Finish(ctx context.Context, job entities.JobRecord) (bool, error)
+
+	List(ctx context.Context, filter entities.JobFilter) ([]entities.JobRecord, int64, error)
+	PurgeFinished(ctx context.Context, before time.Time) (int64, error) // deletes jobs that finished before then
+}
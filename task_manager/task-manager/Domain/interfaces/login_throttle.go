@@ -0,0 +1,11 @@
+package interfaces
+
+import "time"
+
+// LoginThrottle tracks failed logins per client (e.g. IP address) so that
+// guessing from one source is slowed down whichever accounts it targets
+type LoginThrottle interface {
+	RetryAfter(key string) time.Duration // zero when another attempt is allowed
+	RecordFailure(key string)
+	Reset(key string)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+// Mailer interface defines outgoing email delivery
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import "time"
+
+// Login methods reported to Metrics
+const (
+	LoginMethodPassword = "password"
+	LoginMethodMFA      = "mfa"
+	LoginMethodSSO      = "sso"
+)
+
+// Login outcomes reported to Metrics
+const (
+	LoginOutcomeSuccess     = "success"
+	LoginOutcomeMFARequired = "mfa_required"
+	LoginOutcomeFailure     = "failure"   // wrong credentials or code
+	LoginOutcomeThrottled   = "throttled" // client throttled or account locked
+	LoginOutcomeRefused     = "refused"   // valid credentials, but the account may not sign in
+)
+
+// Metrics interface defines the measurements the application exports for monitoring
+type Metrics interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+	ObserveLogin(method, outcome string)
+}
@@ -1,21 +1,93 @@
 package interfaces
 
-import "task_manager/Domain/entities"
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"time"
+)
 
-// UserRepository interface defines user data access operations
+// UserRepository interface defines user data access operations. Through a
+// context scoped to an organization (see utils.ContextWithOrganization) only
+// that organization's accounts are found or changed, and new accounts join it.
+// Emails are unique across organizations, so that sign-in needs none;
+// EmailInUse checks every organization whatever the context.
 type UserRepository interface {
-	GetUserByEmail(email string) (entities.User, error)
-	CountDocuments(email string) (int64, error)
-	InsertOne(user entities.User) (entities.User, error)
-	UpdateOne(email string, user entities.User) (entities.User, error)
-	UpdateRole(email, role string) error
+	GetUserByEmail(ctx context.Context, email string) (entities.User, error)
+	GetUserByID(ctx context.Context, id string) (entities.User, error)
+	GetUserByExternalID(ctx context.Context, issuer, subject string) (entities.User, error)
+	ListUsers(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error)
+	CountDocuments(ctx context.Context, email string) (int64, error)
+	EmailInUse(ctx context.Context, email string) (bool, error)
+	InsertOne(ctx context.Context, user entities.User) (entities.User, error)
+	UpdateOne(ctx context.Context, email string, user entities.User) (entities.User, error) // replaces the whole account
+	// Field-level changes, which leave concurrent changes to other fields alone
+	UpdateProfile(ctx context.Context, email, name, newEmail string) (entities.User, error) // a new email is unverified
+	SetPassword(ctx context.Context, email, currentHash, newHash string) (bool, error)      // false if the password changed meanwhile
+	MarkEmailVerified(ctx context.Context, email string) error
+	LinkIdentity(ctx context.Context, email, issuer, subject string) (bool, error) // false if an identity is linked already
+	UpdateRole(ctx context.Context, email, role string) error
+	SetDisabled(ctx context.Context, email string, disabled bool) error
+	CountUsers(ctx context.Context) (int64, error)
+	CountActiveAdmins(ctx context.Context) (int64, error)
+	RecordFailedLogin(ctx context.Context, email string) (int, error) // returns the new failure count
+	LockUntil(ctx context.Context, email string, until time.Time) error
+	ResetFailedLogins(ctx context.Context, email string) error
+	// Atomic second-factor bookkeeping; both return false if the code was already used
+	MarkMFAStepUsed(ctx context.Context, email string, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, email, codeHash string) (bool, error)
+	// Second-factor setup. SetMFASecret returns false once MFA is enabled, and
+	// EnableMFA also if the secret was replaced meanwhile.
+	SetMFASecret(ctx context.Context, email, secret string) (bool, error)
+	EnableMFA(ctx context.Context, email, secret string, recoveryCodeHashes []string, step int64) (bool, error)
+	ClearMFA(ctx context.Context, email string) error
+	DeleteOne(ctx context.Context, email string) error
 }
 
-// TaskRepository interface defines task data access operations
+// TaskRepository interface defines task data access operations. Like
+// UserRepository, it is limited to the organization a context is scoped to.
 type TaskRepository interface {
-	GetTasks() []entities.Task
-	GetTaskByID(id string) (entities.Task, error)
-	AddTask(task entities.Task) (entities.Task, error)
-	UpdateTask(id string, updatedTask entities.Task) (entities.Task, error)
-	DeleteTask(id string) error
-} 
\ No newline at end of file
+	GetTasks(ctx context.Context) ([]entities.Task, error)
+	GetTaskByID(ctx context.Context, id string) (entities.Task, error)
+	AddTask(ctx context.Context, task entities.Task) (entities.Task, error)
+	UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error)
+	DeleteTask(ctx context.Context, id string) error
+	ReassignTasks(ctx context.Context, fromEmail, toEmail string) (int64, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+// OrganizationRepository interface defines organization data access operations
+type OrganizationRepository interface {
+	InsertOne(ctx context.Context, organization entities.Organization) (entities.Organization, error)
+	GetByID(ctx context.Context, id string) (entities.Organization, error)
+	DeleteOne(ctx context.Context, id string) error
+}
+
+// APIKeyRepository interface defines API key data access operations
+type APIKeyRepository interface {
+	InsertOne(ctx context.Context, key entities.APIKey) (entities.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (entities.APIKey, error)
+	ListByUser(ctx context.Context, userID string) ([]entities.APIKey, error)
+	CountByUser(ctx context.Context, userID string) (int64, error)
+	DeleteOne(ctx context.Context, id, userID string) error
+	DeleteByUser(ctx context.Context, userID string) (int64, error)
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// SigningKeyRepository interface defines storage for access token signing keys,
+// shared by every instance of the service
+type SigningKeyRepository interface {
+	ListKeys(ctx context.Context, algorithm string, now time.Time) ([]entities.SigningKey, error) // keys still valid for verification
+	InsertOne(ctx context.Context, key entities.SigningKey) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyRepository interface defines storage for the responses of requests
+// sent with an Idempotency-Key, shared by every instance of the service
+type IdempotencyRepository interface {
+	// Reserve stores record for a request about to run. If the user's key is
+	// already taken by a record that has not expired, nothing is stored and
+	// that record is returned with false.
+	Reserve(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, userID, key string, response entities.StoredResponse) error
+	Release(ctx context.Context, userID, key string) error // forgets a request that did not complete, so it can be retried
+}
@@ -1,8 +1,41 @@
 package interfaces
 
+import "time"
+
+// Purposes for single-use action tokens
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposePasswordReset = "password_reset"
+	TokenPurposeMFALogin      = "mfa_login"
+)
+
 // TokenService interface defines JWT token operations
 type TokenService interface {
-	GenerateToken(email, role string) (string, error)
-	ValidateToken(token string) (string, string, error) // returns email, role, error
+	GenerateToken(email, role, organizationID string) (string, error)
+	// ValidateToken returns email, role, organization ID, error. The organization
+	// is empty in tokens issued before organizations existed.
+	ValidateToken(token string) (string, string, string, error)
 	ExtractClaims(token string) (map[string]interface{}, error)
-} 
\ No newline at end of file
+	// Action tokens are short-lived, purpose-bound tokens mailed to users. The
+	// fingerprint ties a token to the account state it was issued for, so it stops
+	// validating once that state changes.
+	GenerateActionToken(email, purpose, fingerprint string, ttl time.Duration) (string, error)
+	ValidateActionToken(token, purpose string) (string, string, error) // returns email, fingerprint, error
+	// JWKS returns the public keys that verify access tokens, empty when they are
+	// signed with a shared secret
+	JWKS() ([]JSONWebKey, error)
+}
+
+// JSONWebKey is a public key in RFC 7517 form
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP (Ed25519) keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyDocument represents the MongoDB document structure
+type APIKeyDocument struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	Name       string             `bson:"name"`
+	Prefix     string             `bson:"prefix"`
+	KeyHash    string             `bson:"key_hash"`
+	Scopes     []string           `bson:"scopes"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+	LastUsedAt time.Time          `bson:"last_used_at"`
+}
+
+// APIKeyFromDomain converts domain APIKey to MongoDB APIKeyDocument
+func APIKeyFromDomain(key entities.APIKey) (APIKeyDocument, error) {
+	var objectID primitive.ObjectID
+	var err error
+
+	if key.ID != "" {
+		objectID, err = primitive.ObjectIDFromHex(key.ID)
+		if err != nil {
+			return APIKeyDocument{}, err
+		}
+	}
+
+	userID, err := primitive.ObjectIDFromHex(key.UserID)
+	if err != nil {
+		return APIKeyDocument{}, err
+	}
+
+	return APIKeyDocument{
+		ID:         objectID,
+		UserID:     userID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+	}, nil
+}
+
+// APIKeyToDomain converts MongoDB APIKeyDocument to domain APIKey
+func APIKeyToDomain(doc APIKeyDocument) entities.APIKey {
+	return entities.APIKey{
+		ID:         doc.ID.Hex(),
+		UserID:     doc.UserID.Hex(),
+		Name:       doc.Name,
+		Prefix:     doc.Prefix,
+		KeyHash:    doc.KeyHash,
+		Scopes:     doc.Scopes,
+		CreatedAt:  doc.CreatedAt,
+		ExpiresAt:  doc.ExpiresAt,
+		LastUsedAt: doc.LastUsedAt,
+	}
+}
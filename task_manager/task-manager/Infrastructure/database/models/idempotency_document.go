@@ -0,0 +1,75 @@
+package models
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// IdempotencyDocument represents the MongoDB document structure. _id combines
+// the user ID and the key, so each user's keys are unique.
+type IdempotencyDocument struct {
+	ID          string            `bson:"_id"`
+	UserID      string            `bson:"user_id"`
+	Key         string            `bson:"key"`
+	Fingerprint string            `bson:"fingerprint"`
+	Response    *ResponseDocument `bson:"response,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at"`
+	ExpiresAt   time.Time         `bson:"expires_at"`
+}
+
+// ResponseDocument is a stored HTTP response
+type ResponseDocument struct {
+	Status int               `bson:"status"`
+	Header map[string]string `bson:"header,omitempty"`
+	Body   []byte            `bson:"body"`
+}
+
+// IdempotencyID returns the _id of a user's key
+func IdempotencyID(userID, key string) string {
+	return userID + ":" + key
+}
+
+// IdempotencyFromDomain converts domain IdempotencyRecord to MongoDB IdempotencyDocument
+func IdempotencyFromDomain(record entities.IdempotencyRecord) IdempotencyDocument {
+	doc := IdempotencyDocument{
+		ID:          IdempotencyID(record.UserID, record.Key),
+		UserID:      record.UserID,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		CreatedAt:   record.CreatedAt,
+		ExpiresAt:   record.ExpiresAt,
+	}
+	if record.Response != nil {
+		response := ResponseFromDomain(*record.Response)
+		doc.Response = &response
+	}
+	return doc
+}
+
+// IdempotencyToDomain converts MongoDB IdempotencyDocument to domain IdempotencyRecord
+func IdempotencyToDomain(doc IdempotencyDocument) entities.IdempotencyRecord {
+	record := entities.IdempotencyRecord{
+		UserID:      doc.UserID,
+		Key:         doc.Key,
+		Fingerprint: doc.Fingerprint,
+		CreatedAt:   doc.CreatedAt,
+		ExpiresAt:   doc.ExpiresAt,
+	}
+	if doc.Response != nil {
+		record.Response = &entities.StoredResponse{
+			Status: doc.Response.Status,
+			Header: doc.Response.Header,
+			Body:   doc.Response.Body,
+		}
+	}
+	return record
+}
+
+// ResponseFromDomain converts domain StoredResponse to MongoDB ResponseDocument
+func ResponseFromDomain(response entities.StoredResponse) ResponseDocument {
+	return ResponseDocument{
+		Status: response.Status,
+		Header: response.Header,
+		Body:   response.Body,
+	}
+}
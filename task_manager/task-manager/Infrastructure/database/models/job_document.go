@@ -0,0 +1,59 @@
+package models
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// JobDocument represents the MongoDB document structure. _id is a string, as
+// scheduled runs are given IDs that identify the run.
+type JobDocument struct {
+	ID          string    `bson:"_id"`
+	Type        string    `bson:"type"`
+	Payload     []byte    `bson:"payload,omitempty"`
+	Status      string    `bson:"status"`
+	Attempts    int       `bson:"attempts"`
+	MaxAttempts int       `bson:"max_attempts"`
+	RunAt       time.Time `bson:"run_at"`
+	LockedUntil time.Time `bson:"locked_until,omitempty"`
+	LastError   string    `bson:"last_error,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+	FinishedAt  time.Time `bson:"finished_at,omitempty"`
+}
+
+// JobFromDomain converts domain JobRecord to MongoDB JobDocument
+func JobFromDomain(job entities.JobRecord) JobDocument {
+	return JobDocument{
+		ID:          job.ID,
+		Type:        job.Type,
+		Payload:     job.Payload,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+}
+
+// JobToDomain converts MongoDB JobDocument to domain JobRecord
+func JobToDomain(doc JobDocument) entities.JobRecord {
+	return entities.JobRecord{
+		ID:          doc.ID,
+		Type:        doc.Type,
+		Payload:     doc.Payload,
+		Status:      doc.Status,
+		Attempts:    doc.Attempts,
+		MaxAttempts: doc.MaxAttempts,
+		RunAt:       doc.RunAt,
+		LockedUntil: doc.LockedUntil,
+		LastError:   doc.LastError,
+		CreatedAt:   doc.CreatedAt,
+		UpdatedAt:   doc.UpdatedAt,
+		FinishedAt:  doc.FinishedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// OrganizationDocument represents the MongoDB document structure. _id is a
+// string, as the default organization's ID is not an ObjectID.
+type OrganizationDocument struct {
+	ID        string    `bson:"_id"`
+	Name      string    `bson:"name"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// OrganizationFromDomain converts domain Organization to MongoDB OrganizationDocument
+func OrganizationFromDomain(organization entities.Organization) OrganizationDocument {
+	return OrganizationDocument{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+// OrganizationToDomain converts MongoDB OrganizationDocument to domain Organization
+func OrganizationToDomain(doc OrganizationDocument) entities.Organization {
+	return entities.Organization{
+		ID:        doc.ID,
+		Name:      doc.Name,
+		CreatedAt: doc.CreatedAt,
+	}
+}
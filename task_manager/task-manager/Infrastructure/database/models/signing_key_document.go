@@ -0,0 +1,46 @@
+package models
+
+import (
+	"task_manager/Domain/entities"
+	"time"
+)
+
+// SigningKeyDocument represents the MongoDB document structure. The key ID is used as _id.
+type SigningKeyDocument struct {
+	ID         string    `bson:"_id"`
+	Algorithm  string    `bson:"algorithm"`
+	PrivateKey []byte    `bson:"private_key"`
+	Sealed     bool      `bson:"sealed,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+	ActiveAt   time.Time `bson:"active_at"`
+	RotateAt   time.Time `bson:"rotate_at"`
+	ExpiresAt  time.Time `bson:"expires_at"`
+}
+
+// SigningKeyFromDomain converts domain SigningKey to MongoDB SigningKeyDocument
+func SigningKeyFromDomain(key entities.SigningKey) SigningKeyDocument {
+	return SigningKeyDocument{
+		ID:         key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: key.PrivateKey,
+		Sealed:     key.Sealed,
+		CreatedAt:  key.CreatedAt,
+		ActiveAt:   key.ActiveAt,
+		RotateAt:   key.RotateAt,
+		ExpiresAt:  key.ExpiresAt,
+	}
+}
+
+// SigningKeyToDomain converts MongoDB SigningKeyDocument to domain SigningKey
+func SigningKeyToDomain(doc SigningKeyDocument) entities.SigningKey {
+	return entities.SigningKey{
+		ID:         doc.ID,
+		Algorithm:  doc.Algorithm,
+		PrivateKey: doc.PrivateKey,
+		Sealed:     doc.Sealed,
+		CreatedAt:  doc.CreatedAt,
+		ActiveAt:   doc.ActiveAt,
+		RotateAt:   doc.RotateAt,
+		ExpiresAt:  doc.ExpiresAt,
+	}
+}
@@ -1,9 +1,9 @@
 package models
 
 import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"task_manager/Domain/entities"
 	"time"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TaskDocument represents the MongoDB document structure
@@ -13,6 +13,10 @@ type TaskDocument struct {
 	Description string             `bson:"description"`
 	DueDate     time.Time          `bson:"due_date"`
 	Status      string             `bson:"status"`
+	CreatedBy   string             `bson:"created_by,omitempty"`
+	UpdatedAt   time.Time          `bson:"updated_at,omitempty"`
+
+	OrganizationID string `bson:"org_id,omitempty"`
 }
 
 // TaskFromDomain converts domain Task to MongoDB TaskDocument
@@ -33,6 +37,10 @@ func TaskFromDomain(task entities.Task) (TaskDocument, error) {
 		Description: task.Description,
 		DueDate:     task.DueDate,
 		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+		UpdatedAt:   task.UpdatedAt,
+
+		OrganizationID: task.OrganizationID,
 	}, nil
 }
 
@@ -44,5 +52,9 @@ func TaskToDomain(doc TaskDocument) entities.Task {
 		Description: doc.Description,
 		DueDate:     doc.DueDate,
 		Status:      doc.Status,
+		CreatedBy:   doc.CreatedBy,
+		UpdatedAt:   doc.UpdatedAt,
+
+		OrganizationID: organizationOrDefault(doc.OrganizationID),
 	}
-} 
\ No newline at end of file
+}
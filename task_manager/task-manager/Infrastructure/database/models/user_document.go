@@ -1,17 +1,33 @@
 package models
 
 import (
-	"task_manager/Domain/entities"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"task_manager/Domain/entities"
+	"time"
 )
 
 // UserDocument represents the MongoDB document structure
 type UserDocument struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Name     string             `bson:"name"`
-	Email    string             `bson:"email"`
-	Password string             `bson:"password"`
-	Role     string             `bson:"role"`
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Name          string             `bson:"name"`
+	Email         string             `bson:"email"`
+	Password      string             `bson:"password"`
+	Role          string             `bson:"role"`
+	Disabled      bool               `bson:"disabled"`
+	EmailVerified bool               `bson:"email_verified"`
+
+	FailedLoginAttempts int       `bson:"failed_login_attempts"`
+	LockedUntil         time.Time `bson:"locked_until"`
+
+	MFAEnabled       bool     `bson:"mfa_enabled"`
+	MFASecret        string   `bson:"mfa_secret"`
+	MFARecoveryCodes []string `bson:"mfa_recovery_codes"`
+	MFALastUsedStep  int64    `bson:"mfa_last_used_step"`
+
+	OIDCIssuer  string `bson:"oidc_issuer,omitempty"`
+	OIDCSubject string `bson:"oidc_subject,omitempty"`
+
+	OrganizationID string `bson:"org_id,omitempty"`
 }
 
 // UserFromDomain converts domain User to MongoDB UserDocument
@@ -27,21 +43,60 @@ func UserFromDomain(user entities.User) (UserDocument, error) {
 	}
 
 	return UserDocument{
-		ID:       objectID,
-		Name:     user.Name,
-		Email:    user.Email,
-		Password: user.Password,
-		Role:     user.Role,
+		ID:            objectID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Password:      user.Password,
+		Role:          user.Role,
+		Disabled:      user.Disabled,
+		EmailVerified: user.EmailVerified,
+
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+
+		MFAEnabled:       user.MFAEnabled,
+		MFASecret:        user.MFASecret,
+		MFARecoveryCodes: user.MFARecoveryCodes,
+		MFALastUsedStep:  user.MFALastUsedStep,
+
+		OIDCIssuer:  user.OIDCIssuer,
+		OIDCSubject: user.OIDCSubject,
+
+		OrganizationID: user.OrganizationID,
 	}, nil
 }
 
 // UserToDomain converts MongoDB UserDocument to domain User
 func UserToDomain(doc UserDocument) entities.User {
 	return entities.User{
-		ID:       doc.ID.Hex(),
-		Name:     doc.Name,
-		Email:    doc.Email,
-		Password: doc.Password,
-		Role:     doc.Role,
+		ID:            doc.ID.Hex(),
+		Name:          doc.Name,
+		Email:         doc.Email,
+		Password:      doc.Password,
+		Role:          doc.Role,
+		Disabled:      doc.Disabled,
+		EmailVerified: doc.EmailVerified,
+
+		FailedLoginAttempts: doc.FailedLoginAttempts,
+		LockedUntil:         doc.LockedUntil,
+
+		MFAEnabled:       doc.MFAEnabled,
+		MFASecret:        doc.MFASecret,
+		MFARecoveryCodes: doc.MFARecoveryCodes,
+		MFALastUsedStep:  doc.MFALastUsedStep,
+
+		OIDCIssuer:  doc.OIDCIssuer,
+		OIDCSubject: doc.OIDCSubject,
+
+		OrganizationID: organizationOrDefault(doc.OrganizationID),
 	}
-} 
\ No newline at end of file
+}
+
+// organizationOrDefault places documents stored before organizations existed
+// in the default organization
+func organizationOrDefault(organizationID string) string {
+	if organizationID == "" {
+		return entities.DefaultOrganizationID
+	}
+	return organizationID
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/database/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type apiKeyRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewAPIKeyRepository(collection *mongo.Collection, timeout time.Duration) interfaces.APIKeyRepository {
+	return &apiKeyRepository{
+		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *apiKeyRepository) InsertOne(ctx context.Context, key entities.APIKey) (entities.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	doc, err := models.APIKeyFromDomain(key)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	result, err := r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return entities.APIKey{}, err
+	}
+
+	doc.ID = result.InsertedID.(primitive.ObjectID)
+	return models.APIKeyToDomain(doc), nil
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (entities.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var doc models.APIKeyDocument
+	err := r.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entities.APIKey{}, errors.APIKeyNotFoundError{}
+		}
+		return entities.APIKey{}, err
+	}
+
+	return models.APIKeyToDomain(doc), nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID string) ([]entities.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.InvalidUserIDError{}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []entities.APIKey
+	for cursor.Next(ctx) {
+		var doc models.APIKeyDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, models.APIKeyToDomain(doc))
+	}
+
+	return keys, cursor.Err()
+}
+
+func (r *apiKeyRepository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, errors.InvalidUserIDError{}
+	}
+
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": objectID})
+}
+
+func (r *apiKeyRepository) DeleteOne(ctx context.Context, id, userID string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.InvalidAPIKeyIDError{}
+	}
+	ownerID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.InvalidUserIDError{}
+	}
+
+	// Scoped to the owner so users can only revoke their own keys
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID, "user_id": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.APIKeyNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) DeleteByUser(ctx context.Context, userID string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, errors.InvalidUserIDError{}
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.InvalidAPIKeyIDError{}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+	"time"
+)
+
+type cachedTask struct {
+	task    entities.Task
+	expires time.Time
+}
+
+type cachedTaskList struct {
+	tasks   []entities.Task
+	expires time.Time
+}
+
+// cacheScope separates what is cached for each organization, as reads are
+// limited to the organization of their context
+type cacheScope struct {
+	organizationID string
+	scoped         bool
+}
+
+func cacheScopeOf(ctx context.Context) cacheScope {
+	organizationID, scoped := utils.OrganizationFromContext(ctx)
+	return cacheScope{organizationID: organizationID, scoped: scoped}
+}
+
+type cachedTaskKey struct {
+	scope cacheScope
+	id    string
+}
+
+type cachedTaskRepository struct {
+	next interfaces.TaskRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu         sync.Mutex
+	generation uint64 // advanced by every change, so reads that overlap one are not kept
+	lists      map[cacheScope]cachedTaskList
+	byID       map[cachedTaskKey]cachedTask
+}
+
+// NewCachedTaskRepository keeps the tasks read from next in memory for ttl,
+// separately for each organization. Every change made through it empties the
+// cache, so this process sees its own writes at once; changes made by other
+// instances show within ttl. Counts are not cached.
+func NewCachedTaskRepository(next interfaces.TaskRepository, ttl time.Duration) interfaces.TaskRepository {
+	return &cachedTaskRepository{
+		next:  next,
+		ttl:   ttl,
+		now:   time.Now,
+		lists: make(map[cacheScope]cachedTaskList),
+		byID:  make(map[cachedTaskKey]cachedTask),
+	}
+}
+
+func (r *cachedTaskRepository) GetTasks(ctx context.Context) ([]entities.Task, error) {
+	scope := cacheScopeOf(ctx)
+	r.mu.Lock()
+	if list, ok := r.lists[scope]; ok && r.now().Before(list.expires) {
+		tasks := slices.Clone(list.tasks)
+		r.mu.Unlock()
+		return tasks, nil
+	}
+	generation := r.generation
+	r.mu.Unlock()
+
+	tasks, err := r.next.GetTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.lists[scope] = cachedTaskList{tasks: slices.Clone(tasks), expires: r.now().Add(r.ttl)}
+	}
+	r.mu.Unlock()
+	return tasks, nil
+}
+
+func (r *cachedTaskRepository) GetTaskByID(ctx context.Context, id string) (entities.Task, error) {
+	key := cachedTaskKey{scope: cacheScopeOf(ctx), id: id}
+	r.mu.Lock()
+	if cached, ok := r.byID[key]; ok && r.now().Before(cached.expires) {
+		r.mu.Unlock()
+		return cached.task, nil
+	}
+	generation := r.generation
+	r.mu.Unlock()
+
+	task, err := r.next.GetTaskByID(ctx, id)
+	if err != nil {
+		return entities.Task{}, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.byID[key] = cachedTask{task: task, expires: r.now().Add(r.ttl)}
+	}
+	r.mu.Unlock()
+	return task, nil
+}
+
+// The cache is emptied after every change, even a failed one, as a timed out
+// write may still have been applied
+
+func (r *cachedTaskRepository) AddTask(ctx context.Context, task entities.Task) (entities.Task, error) {
+	defer r.invalidate()
+	return r.next.AddTask(ctx, task)
+}
+
+func (r *cachedTaskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error) {
+	defer r.invalidate()
+	return r.next.UpdateTask(ctx, id, updatedTask)
+}
+
+func (r *cachedTaskRepository) DeleteTask(ctx context.Context, id string) error {
+	defer r.invalidate()
+	return r.next.DeleteTask(ctx, id)
+}
+
+func (r *cachedTaskRepository) ReassignTasks(ctx context.Context, fromEmail, toEmail string) (int64, error) {
+	defer r.invalidate()
+	return r.next.ReassignTasks(ctx, fromEmail, toEmail)
+}
+
+func (r *cachedTaskRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	return r.next.CountByStatus(ctx)
+}
+
+func (r *cachedTaskRepository) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	clear(r.lists)
+	clear(r.byID)
+}
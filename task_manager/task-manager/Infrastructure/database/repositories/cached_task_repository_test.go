@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/mocks"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCachedTaskRepository(t *testing.T) (*cachedTaskRepository, *mocks.MockTaskRepository, *time.Time) {
+	next := mocks.NewMockTaskRepository(gomock.NewController(t))
+	repo := NewCachedTaskRepository(next, time.Minute).(*cachedTaskRepository)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+	return repo, next, &now
+}
+
+func TestCachedTaskRepository_GetTasks(t *testing.T) {
+	repo, next, now := setupCachedTaskRepository(t)
+	ctx := context.Background()
+	tasks := []entities.Task{{ID: "1", Title: "First"}, {ID: "2", Title: "Second"}}
+
+	next.EXPECT().GetTasks(gomock.Any()).Return(tasks, nil)
+	first, err := repo.GetTasks(ctx)
+	require.NoError(t, err)
+	first[0].Title = "Changed by the caller"
+
+	// Served from memory, unaffected by changes to an earlier result
+	second, err := repo.GetTasks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "First", second[0].Title)
+
+	// Read again once the entry expires
+	*now = now.Add(time.Minute)
+	next.EXPECT().GetTasks(gomock.Any()).Return(tasks[:1], nil)
+	third, err := repo.GetTasks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, third, 1)
+}
+
+func TestCachedTaskRepository_GetTaskByID(t *testing.T) {
+	repo, next, _ := setupCachedTaskRepository(t)
+	ctx := context.Background()
+
+	next.EXPECT().GetTaskByID(gomock.Any(), "1").Return(entities.Task{ID: "1", Title: "First"}, nil)
+	for range 2 {
+		task, err := repo.GetTaskByID(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "First", task.Title)
+	}
+
+	// Errors are not cached
+	next.EXPECT().GetTaskByID(gomock.Any(), "2").Return(entities.Task{}, errors.TaskNotFoundError{}).Times(2)
+	for range 2 {
+		_, err := repo.GetTaskByID(ctx, "2")
+		assert.IsType(t, errors.TaskNotFoundError{}, err)
+	}
+}
+
+func TestCachedTaskRepository_ChangesInvalidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(next *mocks.MockTaskRepository)
+		change  func(repo *cachedTaskRepository) error
+		wantErr bool
+	}{
+		{"add", func(next *mocks.MockTaskRepository) {
+			next.EXPECT().AddTask(gomock.Any(), gomock.Any()).Return(entities.Task{ID: "3"}, nil)
+		}, func(repo *cachedTaskRepository) error {
+			_, err := repo.AddTask(context.Background(), entities.Task{})
+			return err
+		}, false},
+		{"update", func(next *mocks.MockTaskRepository) {
+			next.EXPECT().UpdateTask(gomock.Any(), "1", gomock.Any()).Return(entities.Task{ID: "1"}, nil)
+		}, func(repo *cachedTaskRepository) error {
+			_, err := repo.UpdateTask(context.Background(), "1", entities.Task{})
+			return err
+		}, false},
+		{"delete", func(next *mocks.MockTaskRepository) {
+			next.EXPECT().DeleteTask(gomock.Any(), "1").Return(nil)
+		}, func(repo *cachedTaskRepository) error {
+			return repo.DeleteTask(context.Background(), "1")
+		}, false},
+		{"reassign", func(next *mocks.MockTaskRepository) {
+			next.EXPECT().ReassignTasks(gomock.Any(), "old@example.com", "new@example.com").Return(int64(1), nil)
+		}, func(repo *cachedTaskRepository) error {
+			_, err := repo.ReassignTasks(context.Background(), "old@example.com", "new@example.com")
+			return err
+		}, false},
+		{"failed update", func(next *mocks.MockTaskRepository) {
+			next.EXPECT().UpdateTask(gomock.Any(), "1", gomock.Any()).Return(entities.Task{}, errors.TaskUpdateError{Message: "failed to update task"})
+		}, func(repo *cachedTaskRepository) error {
+			_, err := repo.UpdateTask(context.Background(), "1", entities.Task{})
+			return err
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, next, _ := setupCachedTaskRepository(t)
+			ctx := context.Background()
+
+			next.EXPECT().GetTasks(gomock.Any()).Return([]entities.Task{{ID: "1"}}, nil).Times(2)
+			next.EXPECT().GetTaskByID(gomock.Any(), "1").Return(entities.Task{ID: "1"}, nil).Times(2)
+			_, err := repo.GetTasks(ctx)
+			require.NoError(t, err)
+			_, err = repo.GetTaskByID(ctx, "1")
+			require.NoError(t, err)
+
+			tt.expect(next)
+			err = tt.change(repo)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			// Both are read again
+			_, err = repo.GetTasks(ctx)
+			require.NoError(t, err)
+			_, err = repo.GetTaskByID(ctx, "1")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCachedTaskRepository_DropsReadsOverlappingAChange(t *testing.T) {
+	repo, next, _ := setupCachedTaskRepository(t)
+	ctx := context.Background()
+
+	// A change lands while the list is being read, so the result may be stale
+	next.EXPECT().GetTasks(gomock.Any()).DoAndReturn(func(context.Context) ([]entities.Task, error) {
+		repo.invalidate()
+		return []entities.Task{{ID: "1"}}, nil
+	})
+	_, err := repo.GetTasks(ctx)
+	require.NoError(t, err)
+
+	next.EXPECT().GetTasks(gomock.Any()).Return([]entities.Task{{ID: "1"}, {ID: "2"}}, nil)
+	tasks, err := repo.GetTasks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+func TestCachedTaskRepository_Organizations(t *testing.T) {
+	repo, next, _ := setupCachedTaskRepository(t)
+	org1 := utils.ContextWithOrganization(context.Background(), "org1")
+	org2 := utils.ContextWithOrganization(context.Background(), "org2")
+
+	// What one organization read is never served to another
+	next.EXPECT().GetTasks(org1).Return([]entities.Task{{ID: "1", OrganizationID: "org1"}}, nil)
+	next.EXPECT().GetTasks(org2).Return(nil, nil)
+	for range 2 {
+		tasks, err := repo.GetTasks(org1)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 1)
+
+		tasks, err = repo.GetTasks(org2)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+	}
+
+	next.EXPECT().GetTaskByID(org1, "1").Return(entities.Task{ID: "1", OrganizationID: "org1"}, nil)
+	next.EXPECT().GetTaskByID(org2, "1").Return(entities.Task{}, errors.TaskNotFoundError{})
+	_, err := repo.GetTaskByID(org1, "1")
+	require.NoError(t, err)
+	_, err = repo.GetTaskByID(org2, "1")
+	assert.IsType(t, errors.TaskNotFoundError{}, err)
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/database/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type idempotencyRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewIdempotencyRepository(collection *mongo.Collection, timeout time.Duration) interfaces.IdempotencyRepository {
+	return &idempotencyRepository{
+		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	doc := models.IdempotencyFromDomain(record)
+	_, err := r.collection.InsertOne(ctx, doc)
+	if err == nil {
+		return record, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return entities.IdempotencyRecord{}, false, err
+	}
+
+	// The key is taken; an expired record is replaced as if it were gone.
+	// Matching on expires_at keeps two retries from both taking it over.
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID, "expires_at": bson.M{"$lte": record.CreatedAt}}, doc)
+	if err != nil {
+		return entities.IdempotencyRecord{}, false, err
+	}
+	if result.MatchedCount == 1 {
+		return record, true, nil
+	}
+
+	var existing models.IdempotencyDocument
+	if err := r.collection.FindOne(ctx, bson.M{"_id": doc.ID}).Decode(&existing); err != nil {
+		return entities.IdempotencyRecord{}, false, err
+	}
+	return models.IdempotencyToDomain(existing), false, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, userID, key string, response entities.StoredResponse) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"response": models.ResponseFromDomain(response)}}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": models.IdempotencyID(userID, key)}, update)
+	return err
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, userID, key string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// Only a request still running is released, never a stored response
+	filter := bson.M{"_id": models.IdempotencyID(userID, key), "response": bson.M{"$exists": false}}
+	_, err := r.collection.DeleteOne(ctx, filter)
+	return err
+}
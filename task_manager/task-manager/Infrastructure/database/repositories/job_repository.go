@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/database/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type jobRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewJobRepository(collection *mongo.Collection, timeout time.Duration) interfaces.JobQueue {
+	return &jobRepository{
+		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *jobRepository) Enqueue(ctx context.Context, job entities.JobRecord) (entities.JobRecord, bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if job.ID == "" {
+		job.ID = primitive.NewObjectID().Hex()
+	}
+	doc := models.JobFromDomain(job)
+	_, err := r.collection.InsertOne(ctx, doc)
+	if err == nil {
+		return job, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return entities.JobRecord{}, false, err
+	}
+
+	var existing models.JobDocument
+	if err := r.collection.FindOne(ctx, bson.M{"_id": doc.ID}).Decode(&existing); err != nil {
+		return entities.JobRecord{}, false, err
+	}
+	return models.JobToDomain(existing), false, nil
+}
+
+func (r *jobRepository) Claim(ctx context.Context, now time.Time, lease time.Duration) (entities.JobRecord, bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": entities.JobQueued, "run_at": bson.M{"$lte": now}},
+		bson.M{"status": entities.JobRunning, "locked_until": bson.M{"$lte": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": entities.JobRunning, "locked_until": now.Add(lease), "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "run_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var doc models.JobDocument
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return entities.JobRecord{}, false, nil
+	}
+	if err != nil {
+		return entities.JobRecord{}, false, err
+	}
+	return models.JobToDomain(doc), true, nil
+}
+
+func (r *jobRepository) Finish(ctx context.Context, job entities.JobRecord) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	set := bson.M{
+		"status":     job.Status,
+		"run_at":     job.RunAt,
+		"last_error": job.LastError,
+		"updated_at": job.UpdatedAt,
+	}
+	if !job.FinishedAt.IsZero() {
+		set["finished_at"] = job.FinishedAt
+	}
+	unset := bson.M{"locked_until": ""}
+	if job.Status == entities.JobSucceeded {
+		unset["payload"] = ""
+	}
+
+	// Matching the attempt keeps a worker that lost its lease from overwriting
+	// the outcome of the one that took over
+	filter := bson.M{"_id": job.ID, "status": entities.JobRunning, "attempts": job.Attempts}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set, "$unset": unset})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r *jobRepository) List(ctx context.Context, filter entities.JobFilter) ([]entities.JobRecord, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []entities.JobRecord
+	for cursor.Next(ctx) {
+		var doc models.JobDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, 0, err
+		}
+		jobs = append(jobs, models.JobToDomain(doc))
+	}
+
+	return jobs, total, cursor.Err()
+}
+
+func (r *jobRepository) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{
+		"status":      bson.M{"$in": bson.A{entities.JobSucceeded, entities.JobFailed}},
+		"finished_at": bson.M{"$lt": before},
+	}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
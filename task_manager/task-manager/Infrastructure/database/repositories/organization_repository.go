@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/database/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type organizationRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewOrganizationRepository(collection *mongo.Collection, timeout time.Duration) interfaces.OrganizationRepository {
+	return &organizationRepository{
+		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *organizationRepository) InsertOne(ctx context.Context, organization entities.Organization) (entities.Organization, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if organization.ID == "" {
+		organization.ID = primitive.NewObjectID().Hex()
+	}
+	if _, err := r.collection.InsertOne(ctx, models.OrganizationFromDomain(organization)); err != nil {
+		return entities.Organization{}, err
+	}
+	return organization, nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id string) (entities.Organization, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var doc models.OrganizationDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entities.Organization{}, errors.OrganizationNotFoundError{}
+		}
+		return entities.Organization{}, err
+	}
+
+	return models.OrganizationToDomain(doc), nil
+}
+
+func (r *organizationRepository) DeleteOne(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.OrganizationNotFoundError{}
+	}
+
+	return nil
+}
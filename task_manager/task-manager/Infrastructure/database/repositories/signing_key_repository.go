@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/database/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type signingKeyRepository struct {
+	collection *mongo.Collection
+	timeout    time.Duration
+}
+
+func NewSigningKeyRepository(collection *mongo.Collection, timeout time.Duration) interfaces.SigningKeyRepository {
+	return &signingKeyRepository{
+		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *signingKeyRepository) ListKeys(ctx context.Context, algorithm string, now time.Time) ([]entities.SigningKey, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := bson.M{"algorithm": algorithm, "expires_at": bson.M{"$gt": now}}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []entities.SigningKey
+	for cursor.Next(ctx) {
+		var doc models.SigningKeyDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, models.SigningKeyToDomain(doc))
+	}
+
+	return keys, cursor.Err()
+}
+
+func (r *signingKeyRepository) InsertOne(ctx context.Context, key entities.SigningKey) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	_, err := r.collection.InsertOne(ctx, models.SigningKeyFromDomain(key))
+	return err
+}
+
+func (r *signingKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
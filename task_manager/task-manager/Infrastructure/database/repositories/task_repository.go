@@ -2,11 +2,12 @@ package repositories
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
 	"task_manager/Domain/interfaces"
 	"task_manager/Infrastructure/database/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,57 +16,69 @@ import (
 
 type taskRepository struct {
 	collection *mongo.Collection
+	timeout    time.Duration
+	logger     *slog.Logger
 }
 
-func NewTaskRepository(collection *mongo.Collection) interfaces.TaskRepository {
-	return &taskRepository{collection: collection}
+func NewTaskRepository(collection *mongo.Collection, timeout time.Duration, logger *slog.Logger) interfaces.TaskRepository {
+	return &taskRepository{collection: collection, timeout: timeout, logger: logger}
 }
 
-func (r *taskRepository) GetTasks() []entities.Task {
-	cursor, err := r.collection.Find(context.TODO(), bson.M{})
+func (r *taskRepository) GetTasks(ctx context.Context) ([]entities.Task, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, scoped(ctx, bson.M{}))
 	if err != nil {
-		log.Println("Error fetching tasks:", err)
-		return nil
+		return nil, err
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var tasks []entities.Task
-	for cursor.Next(context.TODO()) {
+	for cursor.Next(ctx) {
 		var doc models.TaskDocument
 		if err := cursor.Decode(&doc); err != nil {
-			log.Println("Error decoding task:", err)
+			// One malformed document should not hide every other task
+			r.logger.WarnContext(ctx, "Skipping task that failed to decode", "id", cursor.Current.Lookup("_id").String(), "error", err)
 			continue
 		}
 		tasks = append(tasks, models.TaskToDomain(doc))
 	}
-	return tasks
+	return tasks, cursor.Err()
 }
 
-func (r *taskRepository) GetTaskByID(id string) (entities.Task, error) {
+func (r *taskRepository) GetTaskByID(ctx context.Context, id string) (entities.Task, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return entities.Task{}, errors.InvalidTaskIDError{}
 	}
-	
+
 	var doc models.TaskDocument
-	err = r.collection.FindOne(context.TODO(), bson.M{"_id": objectID}).Decode(&doc)
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": objectID})).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return entities.Task{}, errors.TaskNotFoundError{}
 		}
 		return entities.Task{}, err
 	}
-	
+
 	return models.TaskToDomain(doc), nil
 }
 
-func (r *taskRepository) AddTask(task entities.Task) (entities.Task, error) {
+func (r *taskRepository) AddTask(ctx context.Context, task entities.Task) (entities.Task, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	doc, err := models.TaskFromDomain(task)
 	if err != nil {
 		return entities.Task{}, err
 	}
+	doc.OrganizationID = organizationFor(ctx, doc.OrganizationID)
 
-	result, err := r.collection.InsertOne(context.TODO(), doc)
+	result, err := r.collection.InsertOne(ctx, doc)
 	if err != nil {
 		return entities.Task{}, errors.TaskCreationError{Message: "failed to create task"}
 	}
@@ -74,37 +87,99 @@ func (r *taskRepository) AddTask(task entities.Task) (entities.Task, error) {
 	return models.TaskToDomain(doc), nil
 }
 
-func (r *taskRepository) UpdateTask(id string, updatedTask entities.Task) (entities.Task, error) {
+func (r *taskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return entities.Task{}, errors.InvalidTaskIDError{}
 	}
-	
+
 	doc, err := models.TaskFromDomain(updatedTask)
 	if err != nil {
 		return entities.Task{}, err
 	}
-	
-	_, err = r.collection.UpdateOne(context.TODO(),
-		bson.M{"_id": objectID},
+	doc.OrganizationID = organizationFor(ctx, doc.OrganizationID)
+
+	result, err := r.collection.UpdateOne(ctx,
+		scoped(ctx, bson.M{"_id": objectID}),
 		bson.M{"$set": doc})
 	if err != nil {
 		return entities.Task{}, errors.TaskUpdateError{Message: "failed to update task"}
 	}
-	
+	if result.MatchedCount == 0 {
+		return entities.Task{}, errors.TaskNotFoundError{}
+	}
+
 	return models.TaskToDomain(doc), nil
 }
 
-func (r *taskRepository) DeleteTask(id string) error {
+func (r *taskRepository) DeleteTask(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return errors.InvalidTaskIDError{}
 	}
-	
-	_, err = r.collection.DeleteOne(context.TODO(), bson.M{"_id": objectID})
+
+	result, err := r.collection.DeleteOne(ctx, scoped(ctx, bson.M{"_id": objectID}))
 	if err != nil {
 		return errors.TaskUpdateError{Message: "failed to delete task"}
 	}
-	
+	if result.DeletedCount == 0 {
+		return errors.TaskNotFoundError{}
+	}
+
 	return nil
-} 
\ No newline at end of file
+}
+
+func (r *taskRepository) ReassignTasks(ctx context.Context, fromEmail, toEmail string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The tasks read differently afterwards, so they count as changed
+	filter := scoped(ctx, bson.M{"created_by": fromEmail})
+	update := bson.M{"$set": bson.M{"created_by": toEmail, "updated_at": time.Now()}}
+	if toEmail == "" {
+		update = bson.M{"$set": bson.M{"updated_at": time.Now()}, "$unset": bson.M{"created_by": ""}}
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, errors.TaskUpdateError{Message: "failed to reassign tasks"}
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *taskRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: scoped(ctx, bson.M{})}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+	return counts, nil
+}
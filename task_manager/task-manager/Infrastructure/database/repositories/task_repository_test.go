@@ -2,11 +2,12 @@ package repositories
 
 import (
 	"context"
+	"log/slog"
 	"path/filepath"
-	"testing"
-	"time"
 	"task_manager/Domain/entities"
 	"task_manager/Infrastructure/database/models"
+	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
@@ -53,13 +54,13 @@ func TestTaskRepository_AddTask(t *testing.T) {
 	collection, cleanup := setupTaskTestDB(t)
 	defer cleanup()
 
-	repo := NewTaskRepository(collection)
+	repo := NewTaskRepository(collection, 5*time.Second, slog.New(slog.DiscardHandler))
 
 	// Test task
 	task := entities.NewTask("Test Task", "Test Description", time.Now())
 
 	// Test AddTask
-	addedTask, err := repo.AddTask(task)
+	addedTask, err := repo.AddTask(context.Background(), task)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, addedTask.ID)
 
@@ -76,15 +77,15 @@ func TestTaskRepository_GetTaskByID(t *testing.T) {
 	collection, cleanup := setupTaskTestDB(t)
 	defer cleanup()
 
-	repo := NewTaskRepository(collection)
+	repo := NewTaskRepository(collection, 5*time.Second, slog.New(slog.DiscardHandler))
 
 	// Create test task
 	task := entities.NewTask("Test Task", "Test Description", time.Now())
-	addedTask, err := repo.AddTask(task)
+	addedTask, err := repo.AddTask(context.Background(), task)
 	require.NoError(t, err)
 
 	// Test GetTaskByID
-	foundTask, err := repo.GetTaskByID(addedTask.ID)
+	foundTask, err := repo.GetTaskByID(context.Background(), addedTask.ID)
 	assert.NoError(t, err)
 	assert.NotNil(t, foundTask)
 	assert.Equal(t, "Test Task", foundTask.Title)
@@ -92,7 +93,7 @@ func TestTaskRepository_GetTaskByID(t *testing.T) {
 	assert.Equal(t, "Pending", foundTask.Status)
 
 	// Test GetTaskByID with non-existent ID
-	notFoundTask, err := repo.GetTaskByID("nonexistentid")
+	notFoundTask, err := repo.GetTaskByID(context.Background(), "nonexistentid")
 	assert.Error(t, err)
 	assert.Equal(t, entities.Task{}, notFoundTask)
 }
@@ -101,19 +102,20 @@ func TestTaskRepository_GetTasks(t *testing.T) {
 	collection, cleanup := setupTaskTestDB(t)
 	defer cleanup()
 
-	repo := NewTaskRepository(collection)
+	repo := NewTaskRepository(collection, 5*time.Second, slog.New(slog.DiscardHandler))
 
 	// Create multiple tasks
 	task1 := entities.NewTask("Task 1", "Description 1", time.Now())
 	task2 := entities.NewTask("Task 2", "Description 2", time.Now())
 
-	_, err := repo.AddTask(task1)
+	_, err := repo.AddTask(context.Background(), task1)
 	require.NoError(t, err)
-	_, err = repo.AddTask(task2)
+	_, err = repo.AddTask(context.Background(), task2)
 	require.NoError(t, err)
 
 	// Test GetTasks
-	tasks := repo.GetTasks()
+	tasks, err := repo.GetTasks(context.Background())
+	require.NoError(t, err)
 	assert.Len(t, tasks, 2)
 
 	// Verify tasks
@@ -126,22 +128,22 @@ func TestTaskRepository_UpdateTask(t *testing.T) {
 	collection, cleanup := setupTaskTestDB(t)
 	defer cleanup()
 
-	repo := NewTaskRepository(collection)
+	repo := NewTaskRepository(collection, 5*time.Second, slog.New(slog.DiscardHandler))
 
 	// Create test task
 	task := entities.NewTask("Test Task", "Test Description", time.Now())
-	addedTask, err := repo.AddTask(task)
+	addedTask, err := repo.AddTask(context.Background(), task)
 	require.NoError(t, err)
 
 	// Update task
 	updatedTask := entities.NewTask("Updated Task", "Updated Description", time.Now())
 	updatedTask.SetStatus("Completed")
-	
-	resultTask, err := repo.UpdateTask(addedTask.ID, updatedTask)
+
+	resultTask, err := repo.UpdateTask(context.Background(), addedTask.ID, updatedTask)
 	assert.NoError(t, err)
 
 	// Verify update
-	foundTask, err := repo.GetTaskByID(addedTask.ID)
+	foundTask, err := repo.GetTaskByID(context.Background(), addedTask.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Task", foundTask.Title)
 	assert.Equal(t, "Updated Description", foundTask.Description)
@@ -153,19 +155,19 @@ func TestTaskRepository_DeleteTask(t *testing.T) {
 	collection, cleanup := setupTaskTestDB(t)
 	defer cleanup()
 
-	repo := NewTaskRepository(collection)
+	repo := NewTaskRepository(collection, 5*time.Second, slog.New(slog.DiscardHandler))
 
 	// Create test task
 	task := entities.NewTask("Test Task", "Test Description", time.Now())
-	addedTask, err := repo.AddTask(task)
+	addedTask, err := repo.AddTask(context.Background(), task)
 	require.NoError(t, err)
 
 	// Test DeleteTask
-	err = repo.DeleteTask(addedTask.ID)
+	err = repo.DeleteTask(context.Background(), addedTask.ID)
 	assert.NoError(t, err)
 
 	// Verify task was deleted
-	foundTask, err := repo.GetTaskByID(addedTask.ID)
+	foundTask, err := repo.GetTaskByID(context.Background(), addedTask.ID)
 	assert.Error(t, err)
 	assert.Equal(t, entities.Task{}, foundTask)
 }
@@ -196,4 +198,4 @@ func TestTaskDocument_Mapping(t *testing.T) {
 	assert.Equal(t, originalTask.Title, convertedDoc.Title)
 	assert.Equal(t, originalTask.Description, convertedDoc.Description)
 	assert.Equal(t, originalTask.Status, convertedDoc.Status)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// scoped adds the organization ctx is scoped to, if any, to a query filter, so
+// that other organizations' documents are never matched. The default
+// organization also holds the documents stored before organizations existed,
+// which have no org_id.
+func scoped(ctx context.Context, filter bson.M) bson.M {
+	organizationID, ok := utils.OrganizationFromContext(ctx)
+	if !ok {
+		return filter
+	}
+
+	if organizationID == entities.DefaultOrganizationID {
+		filter["org_id"] = bson.M{"$in": bson.A{organizationID, nil}}
+	} else {
+		filter["org_id"] = organizationID
+	}
+	return filter
+}
+
+// organizationFor returns the organization a document written through ctx
+// belongs to: the one ctx is scoped to, whatever the document says, or else
+// its own
+func organizationFor(ctx context.Context, own string) string {
+	if organizationID, ok := utils.OrganizationFromContext(ctx); ok {
+		return organizationID
+	}
+	return own
+}
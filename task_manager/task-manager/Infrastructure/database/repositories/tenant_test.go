@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestScoped(t *testing.T) {
+	// Sign-in flows and background work reach every organization
+	assert.Equal(t, bson.M{"email": "a@example.com"}, scoped(context.Background(), bson.M{"email": "a@example.com"}))
+	assert.Equal(t, "own", organizationFor(context.Background(), "own"))
+
+	ctx := utils.ContextWithOrganization(context.Background(), "org1")
+	assert.Equal(t, bson.M{"email": "a@example.com", "org_id": "org1"}, scoped(ctx, bson.M{"email": "a@example.com"}))
+	assert.Equal(t, "org1", organizationFor(ctx, "org2"))
+
+	// Documents without an organization belong to the default one
+	ctx = utils.ContextWithOrganization(context.Background(), entities.DefaultOrganizationID)
+	assert.Equal(t, bson.M{"org_id": bson.M{"$in": bson.A{entities.DefaultOrganizationID, nil}}}, scoped(ctx, bson.M{}))
+}
+
+// tenantCase is one repository call and the replies its commands get
+type tenantCase struct {
+	name      string
+	responses []bson.D
+	call      func(ctx context.Context, coll *mongo.Collection) error
+}
+
+// scopeFilters are where each command names the documents it reads or
+// changes, or for inserts the documents themselves
+var scopeFilters = map[string][]string{
+	"find":          {"filter"},
+	"aggregate":     {"pipeline", "0", "$match"},
+	"update":        {"updates", "0", "q"},
+	"delete":        {"deletes", "0", "q"},
+	"findAndModify": {"query"},
+	"insert":        {"documents", "0"},
+}
+
+// assertScoped runs each call through a context scoped to org1 and checks that
+// every command it sends is limited to org1, and that documents written are
+// kept in it
+func assertScoped(t *testing.T, cases []tenantCase) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ctx := utils.ContextWithOrganization(context.Background(), "org1")
+
+	for _, tc := range cases {
+		mt.Run(tc.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tc.responses...)
+			_ = tc.call(ctx, mt.Coll)
+
+			events := mt.GetAllStartedEvents()
+			require.NotEmpty(mt, events)
+			for _, event := range events {
+				path, ok := scopeFilters[event.CommandName]
+				require.True(mt, ok, "unexpected command %s", event.CommandName)
+				organizationID, _ := event.Command.Lookup(append(path, "org_id")...).StringValueOK()
+				assert.Equal(mt, "org1", organizationID, "%s", event.CommandName)
+
+				if set, err := event.Command.LookupErr("updates", "0", "u", "$set", "org_id"); err == nil {
+					organizationID, _ := set.StringValueOK()
+					assert.Equal(mt, "org1", organizationID)
+				}
+			}
+		})
+	}
+}
+
+func cursor(docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, "test.coll", mtest.FirstBatch, docs...)
+}
+
+func written(n int32) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+func TestTaskRepository_QueriesAreScoped(t *testing.T) {
+	const taskID = "507f1f77bcf86cd799439011"
+	repo := func(coll *mongo.Collection) interfaces.TaskRepository {
+		return NewTaskRepository(coll, time.Second, slog.New(slog.DiscardHandler))
+	}
+	// Another organization's ID in the task itself makes no difference
+	otherOrgTask := entities.Task{Title: "Plan", Status: "Pending", OrganizationID: "org2"}
+
+	assertScoped(t, []tenantCase{
+		{"GetTasks", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).GetTasks(ctx)
+			return err
+		}},
+		{"GetTaskByID", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).GetTaskByID(ctx, taskID)
+			return err
+		}},
+		{"AddTask", []bson.D{mtest.CreateSuccessResponse()}, func(ctx context.Context, coll *mongo.Collection) error {
+			task, err := repo(coll).AddTask(ctx, otherOrgTask)
+			assert.Equal(t, "org1", task.OrganizationID)
+			return err
+		}},
+		{"UpdateTask", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).UpdateTask(ctx, taskID, otherOrgTask)
+			return err
+		}},
+		{"DeleteTask", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).DeleteTask(ctx, taskID)
+		}},
+		{"ReassignTasks", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).ReassignTasks(ctx, "a@example.com", "b@example.com")
+			return err
+		}},
+		{"CountByStatus", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).CountByStatus(ctx)
+			return err
+		}},
+	})
+}
+
+func TestUserRepository_QueriesAreScoped(t *testing.T) {
+	const email = "a@example.com"
+	repo := func(coll *mongo.Collection) interfaces.UserRepository {
+		return NewUserRepository(coll, time.Second)
+	}
+	otherOrgUser := entities.User{Name: "A", Email: email, Role: "user", OrganizationID: "org2"}
+	found := mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "email", Value: email}}})
+
+	assertScoped(t, []tenantCase{
+		{"GetUserByEmail", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).GetUserByEmail(ctx, email)
+			return err
+		}},
+		{"GetUserByID", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).GetUserByID(ctx, "507f1f77bcf86cd799439011")
+			return err
+		}},
+		{"GetUserByExternalID", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).GetUserByExternalID(ctx, "https://idp.example.com", "subject")
+			return err
+		}},
+		{"ListUsers", []bson.D{cursor(), cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, _, err := repo(coll).ListUsers(ctx, entities.UserFilter{Query: "a", Role: "user"}.WithDefaults())
+			return err
+		}},
+		{"CountDocuments", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).CountDocuments(ctx, email)
+			return err
+		}},
+		{"InsertOne", []bson.D{mtest.CreateSuccessResponse()}, func(ctx context.Context, coll *mongo.Collection) error {
+			user, err := repo(coll).InsertOne(ctx, otherOrgUser)
+			assert.Equal(t, "org1", user.OrganizationID)
+			return err
+		}},
+		{"UpdateOne", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).UpdateOne(ctx, email, otherOrgUser)
+			return err
+		}},
+		{"UpdateProfile", []bson.D{found}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).UpdateProfile(ctx, email, "A", "b@example.com")
+			return err
+		}},
+		{"SetPassword", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).SetPassword(ctx, email, "old", "new")
+			return err
+		}},
+		{"MarkEmailVerified", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).MarkEmailVerified(ctx, email)
+		}},
+		{"LinkIdentity", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).LinkIdentity(ctx, email, "https://idp.example.com", "subject")
+			return err
+		}},
+		{"UpdateRole", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).UpdateRole(ctx, email, "admin")
+		}},
+		{"SetDisabled", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).SetDisabled(ctx, email, true)
+		}},
+		{"CountUsers", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).CountUsers(ctx)
+			return err
+		}},
+		{"CountActiveAdmins", []bson.D{cursor()}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).CountActiveAdmins(ctx)
+			return err
+		}},
+		{"RecordFailedLogin", []bson.D{found}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).RecordFailedLogin(ctx, email)
+			return err
+		}},
+		{"LockUntil", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).LockUntil(ctx, email, time.Now())
+		}},
+		{"ResetFailedLogins", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).ResetFailedLogins(ctx, email)
+		}},
+		{"MarkMFAStepUsed", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).MarkMFAStepUsed(ctx, email, 42)
+			return err
+		}},
+		{"ConsumeRecoveryCode", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).ConsumeRecoveryCode(ctx, email, "hash")
+			return err
+		}},
+		{"SetMFASecret", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).SetMFASecret(ctx, email, "secret")
+			return err
+		}},
+		{"EnableMFA", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			_, err := repo(coll).EnableMFA(ctx, email, "secret", []string{"hash"}, 42)
+			return err
+		}},
+		{"ClearMFA", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).ClearMFA(ctx, email)
+		}},
+		{"DeleteOne", []bson.D{written(1)}, func(ctx context.Context, coll *mongo.Collection) error {
+			return repo(coll).DeleteOne(ctx, email)
+		}},
+	})
+}
+
+func TestUserRepository_EmailInUseIsNotScoped(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ctx := utils.ContextWithOrganization(context.Background(), "org1")
+
+	mt.Run("EmailInUse", func(mt *mtest.T) {
+		// The address belongs to an account in another organization
+		mt.AddMockResponses(cursor(bson.D{{Key: "n", Value: int32(1)}}))
+		inUse, err := NewUserRepository(mt.Coll, time.Second).EmailInUse(ctx, "Taken@Example.com")
+		require.NoError(mt, err)
+		assert.True(mt, inUse)
+
+		event := mt.GetStartedEvent()
+		require.Equal(mt, "aggregate", event.CommandName)
+		match := event.Command.Lookup("pipeline", "0", "$match")
+		assert.Equal(mt, "taken@example.com", match.Document().Lookup("email").StringValue())
+		_, err = match.Document().LookupErr("org_id")
+		assert.Error(mt, err, "the check must cover every organization")
+	})
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout bounds a single repository operation. The caller's context still
+// applies, so a request that is abandoned cancels its queries as well. A zero
+// timeout leaves the operation bounded by the caller's context alone.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
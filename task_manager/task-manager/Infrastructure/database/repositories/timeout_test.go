@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := withTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	// No timeout leaves the caller's context in charge
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = withTimeout(parent, 0)
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+
+	cancelParent()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRepository_OperationTimeout(t *testing.T) {
+	// Nothing listens here, so operations wait for a server until they time out
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+
+	repo := NewTaskRepository(client.Database("timeout_test").Collection("tasks"), 50*time.Millisecond, slog.New(slog.DiscardHandler))
+
+	start := time.Now()
+	_, err = repo.GetTasks(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// A cancelled request stops its query as well
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = repo.GetTaskByID(ctx, "507f1f77bcf86cd799439011")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -2,58 +2,169 @@ package repositories
 
 import (
 	"context"
+	"regexp"
 	"strings"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
 	"task_manager/Domain/interfaces"
 	"task_manager/Infrastructure/database/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type userRepository struct {
 	collection *mongo.Collection
+	timeout    time.Duration
 }
 
-func NewUserRepository(collection *mongo.Collection) interfaces.UserRepository {
+func NewUserRepository(collection *mongo.Collection, timeout time.Duration) interfaces.UserRepository {
 	return &userRepository{
 		collection: collection,
+		timeout:    timeout,
+	}
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	var doc models.UserDocument
+
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entities.User{}, errors.UserNotFoundError{}
+		}
+		return entities.User{}, err
+	}
+
+	return models.UserToDomain(doc), nil
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id string) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.User{}, errors.InvalidUserIDError{}
+	}
+
+	var doc models.UserDocument
+	err = r.collection.FindOne(ctx, scoped(ctx, bson.M{"_id": objectID})).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entities.User{}, errors.UserNotFoundError{}
+		}
+		return entities.User{}, err
 	}
+
+	return models.UserToDomain(doc), nil
 }
 
-func (r *userRepository) GetUserByEmail(email string) (entities.User, error) {
-	filter := bson.M{"email": strings.ToLower(email)}
+func (r *userRepository) GetUserByExternalID(ctx context.Context, issuer, subject string) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"oidc_issuer": issuer, "oidc_subject": subject})
+
 	var doc models.UserDocument
-	
-	err := r.collection.FindOne(context.TODO(), filter).Decode(&doc)
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return entities.User{}, errors.UserNotFoundError{}
 		}
 		return entities.User{}, err
 	}
-	
+
 	return models.UserToDomain(doc), nil
 }
 
-func (r *userRepository) CountDocuments(email string) (int64, error) {
-	filter := bson.M{"email": strings.ToLower(email)}
-	count, err := r.collection.CountDocuments(context.TODO(), filter)
+func (r *userRepository) ListUsers(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := scoped(ctx, bson.M{})
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		query["$or"] = bson.A{
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "email", Value: 1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []entities.User
+	for cursor.Next(ctx) {
+		var doc models.UserDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, models.UserToDomain(doc))
+	}
+
+	return users, total, cursor.Err()
+}
+
+func (r *userRepository) CountDocuments(ctx context.Context, email string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	count, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *userRepository) InsertOne(user entities.User) (entities.User, error) {
+// EmailInUse reports whether any account uses the email. It is not scoped,
+// as an address taken in another organization is taken all the same.
+func (r *userRepository) EmailInUse(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"email": strings.ToLower(email)}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *userRepository) InsertOne(ctx context.Context, user entities.User) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	doc, err := models.UserFromDomain(user)
 	if err != nil {
 		return entities.User{}, err
 	}
+	doc.OrganizationID = organizationFor(ctx, doc.OrganizationID)
 
-	result, err := r.collection.InsertOne(context.TODO(), doc)
+	result, err := r.collection.InsertOne(ctx, doc)
 	if err != nil {
 		return entities.User{}, err
 	}
@@ -62,31 +173,306 @@ func (r *userRepository) InsertOne(user entities.User) (entities.User, error) {
 	return models.UserToDomain(doc), nil
 }
 
-func (r *userRepository) UpdateOne(email string, user entities.User) (entities.User, error) {
+func (r *userRepository) UpdateOne(ctx context.Context, email string, user entities.User) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
 	doc, err := models.UserFromDomain(user)
 	if err != nil {
 		return entities.User{}, err
 	}
+	doc.OrganizationID = organizationFor(ctx, doc.OrganizationID)
 
-	filter := bson.M{"email": strings.ToLower(email)}
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
 	update := bson.M{"$set": doc}
-	
-	_, err = r.collection.UpdateOne(context.TODO(), filter, update)
+
+	_, err = r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return entities.User{}, err
 	}
-	
+
 	return models.UserToDomain(doc), nil
 }
 
-func (r *userRepository) UpdateRole(email, role string) error {
-	filter := bson.M{"email": strings.ToLower(email)}
+func (r *userRepository) UpdateProfile(ctx context.Context, email, name, newEmail string) (entities.User, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	set := bson.M{"name": name}
+	if newEmail = strings.ToLower(newEmail); newEmail != strings.ToLower(email) {
+		set["email"] = newEmail
+		set["email_verified"] = false
+	}
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc models.UserDocument
+	err := r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return entities.User{}, errors.UserNotFoundError{}
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return entities.User{}, errors.EmailAlreadyExistsError{}
+		}
+		return entities.User{}, err
+	}
+
+	return models.UserToDomain(doc), nil
+}
+
+func (r *userRepository) SetPassword(ctx context.Context, email, currentHash, newHash string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// Matching the hash the caller checked keeps two changes from both succeeding
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "password": currentHash})
+	update := bson.M{"$set": bson.M{"password": newHash}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, email string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$set": bson.M{"email_verified": true}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *userRepository) LinkIdentity(ctx context.Context, email, issuer, subject string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "oidc_subject": bson.M{"$in": bson.A{"", nil}}})
+	update := bson.M{"$set": bson.M{"oidc_issuer": issuer, "oidc_subject": subject, "email_verified": true}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, email, role string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
 	update := bson.M{"$set": bson.M{"role": role}}
-	
-	_, err := r.collection.UpdateOne(context.TODO(), filter, update)
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return errors.UserPromotionError{Message: "failed to update user role"}
 	}
-	
+	if result.MatchedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, email string, disabled bool) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$set": bson.M{"disabled": disabled}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *userRepository) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, scoped(ctx, bson.M{}))
+}
+
+func (r *userRepository) CountActiveAdmins(ctx context.Context) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"role": "admin", "disabled": bson.M{"$ne": true}})
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+func (r *userRepository) RecordFailedLogin(ctx context.Context, email string) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$inc": bson.M{"failed_login_attempts": 1}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc models.UserDocument
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, errors.UserNotFoundError{}
+		}
+		return 0, err
+	}
+
+	return doc.FailedLoginAttempts, nil
+}
+
+func (r *userRepository) LockUntil(ctx context.Context, email string, until time.Time) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$set": bson.M{"locked_until": until}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *userRepository) ResetFailedLogins(ctx context.Context, email string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$set": bson.M{"failed_login_attempts": 0, "locked_until": time.Time{}}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *userRepository) MarkMFAStepUsed(ctx context.Context, email string, step int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// Only moves forward, so a code cannot be replayed within its validity window
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "mfa_last_used_step": bson.M{"$lt": step}})
+	update := bson.M{"$set": bson.M{"mfa_last_used_step": step}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) ConsumeRecoveryCode(ctx context.Context, email, codeHash string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "mfa_recovery_codes": codeHash})
+	update := bson.M{"$pull": bson.M{"mfa_recovery_codes": codeHash}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *userRepository) SetMFASecret(ctx context.Context, email, secret string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "mfa_enabled": bson.M{"$ne": true}})
+	update := bson.M{"$set": bson.M{"mfa_secret": secret}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *userRepository) EnableMFA(ctx context.Context, email, secret string, recoveryCodeHashes []string, step int64) (bool, error) {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The code was checked against secret, so enrolling again meanwhile must win
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email), "mfa_enabled": bson.M{"$ne": true}, "mfa_secret": secret})
+	update := bson.M{"$set": bson.M{
+		"mfa_enabled":        true,
+		"mfa_recovery_codes": recoveryCodeHashes,
+		"mfa_last_used_step": step,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *userRepository) ClearMFA(ctx context.Context, email string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+	update := bson.M{"$set": bson.M{
+		"mfa_enabled":        false,
+		"mfa_secret":         "",
+		"mfa_recovery_codes": nil,
+		"mfa_last_used_step": 0,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
 	return nil
-} 
\ No newline at end of file
+}
+
+func (r *userRepository) DeleteOne(ctx context.Context, email string) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	filter := scoped(ctx, bson.M{"email": strings.ToLower(email)})
+
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.UserNotFoundError{}
+	}
+
+	return nil
+}
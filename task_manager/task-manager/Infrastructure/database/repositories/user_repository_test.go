@@ -3,9 +3,10 @@ package repositories
 import (
 	"context"
 	"path/filepath"
-	"testing"
 	"task_manager/Domain/entities"
 	"task_manager/Infrastructure/database/models"
+	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +14,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -52,14 +54,14 @@ func TestUserRepository_InsertOne(t *testing.T) {
 	collection, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(collection)
+	repo := NewUserRepository(collection, 5*time.Second)
 
 	// Test user
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 	user.SetRole("user")
 
 	// Test InsertOne
-	insertedUser, err := repo.InsertOne(user)
+	insertedUser, err := repo.InsertOne(context.Background(), user)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, insertedUser.ID)
 
@@ -76,16 +78,16 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 	collection, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(collection)
+	repo := NewUserRepository(collection, 5*time.Second)
 
 	// Create test user
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 	user.SetRole("user")
-	_, err := repo.InsertOne(user)
+	_, err := repo.InsertOne(context.Background(), user)
 	require.NoError(t, err)
 
 	// Test GetUserByEmail
-	foundUser, err := repo.GetUserByEmail("test@example.com")
+	foundUser, err := repo.GetUserByEmail(context.Background(), "test@example.com")
 	assert.NoError(t, err)
 	assert.NotNil(t, foundUser)
 	assert.Equal(t, "Test User", foundUser.Name)
@@ -93,7 +95,7 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 	assert.Equal(t, "user", foundUser.Role)
 
 	// Test GetUserByEmail with non-existent email
-	notFoundUser, err := repo.GetUserByEmail("nonexistent@example.com")
+	notFoundUser, err := repo.GetUserByEmail(context.Background(), "nonexistent@example.com")
 	assert.Error(t, err)
 	assert.Equal(t, entities.User{}, notFoundUser)
 }
@@ -102,24 +104,24 @@ func TestUserRepository_UpdateOne(t *testing.T) {
 	collection, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(collection)
+	repo := NewUserRepository(collection, 5*time.Second)
 
 	// Create test user
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 	user.SetRole("user")
-	_, err := repo.InsertOne(user)
+	_, err := repo.InsertOne(context.Background(), user)
 	require.NoError(t, err)
 
 	// Update user
 	updatedUser := entities.NewUser("Updated User", "test@example.com", "password123")
 	updatedUser.SetRole("admin")
 	updatedUser.ID = user.ID
-	
-	resultUser, err := repo.UpdateOne("test@example.com", updatedUser)
+
+	resultUser, err := repo.UpdateOne(context.Background(), "test@example.com", updatedUser)
 	assert.NoError(t, err)
 
 	// Verify update
-	foundUser, err := repo.GetUserByEmail("test@example.com")
+	foundUser, err := repo.GetUserByEmail(context.Background(), "test@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated User", foundUser.Name)
 	assert.Equal(t, "admin", foundUser.Role)
@@ -130,21 +132,21 @@ func TestUserRepository_CountDocuments(t *testing.T) {
 	collection, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(collection)
+	repo := NewUserRepository(collection, 5*time.Second)
 
 	// Create test user
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 	user.SetRole("user")
-	_, err := repo.InsertOne(user)
+	_, err := repo.InsertOne(context.Background(), user)
 	require.NoError(t, err)
 
 	// Test CountDocuments
-	count, err := repo.CountDocuments("test@example.com")
+	count, err := repo.CountDocuments(context.Background(), "test@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), count)
 
 	// Test CountDocuments with non-existent email
-	count, err = repo.CountDocuments("nonexistent@example.com")
+	count, err = repo.CountDocuments(context.Background(), "nonexistent@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, int64(0), count)
 }
@@ -153,20 +155,20 @@ func TestUserRepository_UpdateRole(t *testing.T) {
 	collection, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	repo := NewUserRepository(collection)
+	repo := NewUserRepository(collection, 5*time.Second)
 
 	// Create test user
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 	user.SetRole("user")
-	_, err := repo.InsertOne(user)
+	_, err := repo.InsertOne(context.Background(), user)
 	require.NoError(t, err)
 
 	// Test UpdateRole
-	err = repo.UpdateRole("test@example.com", "admin")
+	err = repo.UpdateRole(context.Background(), "test@example.com", "admin")
 	assert.NoError(t, err)
 
 	// Verify role was updated
-	foundUser, err := repo.GetUserByEmail("test@example.com")
+	foundUser, err := repo.GetUserByEmail(context.Background(), "test@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, "admin", foundUser.Role)
 }
@@ -200,4 +202,76 @@ func TestUserDocument_Mapping(t *testing.T) {
 	assert.Equal(t, originalUser.Email, convertedDoc.Email)
 	assert.Equal(t, originalUser.Password, convertedDoc.Password)
 	assert.Equal(t, originalUser.Role, convertedDoc.Role)
-} 
\ No newline at end of file
+}
+
+func TestUserRepository_FieldUpdates(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	const email = "a@example.com"
+
+	// Each change writes only its own fields, under the conditions it relies on
+	tests := []struct {
+		name      string
+		call      func(repo *userRepository) error
+		condition bson.M
+		set       []string
+	}{
+		{"UpdateProfile name", func(repo *userRepository) error {
+			_, err := repo.UpdateProfile(context.Background(), email, "A", "A@example.com")
+			return err
+		}, nil, []string{"name"}},
+		{"UpdateProfile email", func(repo *userRepository) error {
+			_, err := repo.UpdateProfile(context.Background(), email, "A", "B@example.com")
+			return err
+		}, nil, []string{"name", "email", "email_verified"}},
+		{"SetPassword", func(repo *userRepository) error {
+			_, err := repo.SetPassword(context.Background(), email, "old", "new")
+			return err
+		}, bson.M{"password": "old"}, []string{"password"}},
+		{"MarkEmailVerified", func(repo *userRepository) error {
+			return repo.MarkEmailVerified(context.Background(), email)
+		}, nil, []string{"email_verified"}},
+		{"SetMFASecret", func(repo *userRepository) error {
+			_, err := repo.SetMFASecret(context.Background(), email, "secret")
+			return err
+		}, nil, []string{"mfa_secret"}},
+		{"EnableMFA", func(repo *userRepository) error {
+			_, err := repo.EnableMFA(context.Background(), email, "secret", []string{"hash"}, 42)
+			return err
+		}, bson.M{"mfa_secret": "secret"}, []string{"mfa_enabled", "mfa_recovery_codes", "mfa_last_used_step"}},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			// Answers both updates and findAndModify
+			mt.AddMockResponses(mtest.CreateSuccessResponse(
+				bson.E{Key: "n", Value: int32(1)},
+				bson.E{Key: "value", Value: bson.D{{Key: "email", Value: email}}},
+			))
+			require.NoError(mt, tt.call(NewUserRepository(mt.Coll, time.Second).(*userRepository)))
+
+			event := mt.GetStartedEvent()
+			var filter, update bson.Raw
+			switch event.CommandName {
+			case "update":
+				filter = event.Command.Lookup("updates", "0", "q").Document()
+				update = event.Command.Lookup("updates", "0", "u").Document()
+			case "findAndModify":
+				filter = event.Command.Lookup("query").Document()
+				update = event.Command.Lookup("update").Document()
+			default:
+				mt.Fatalf("unexpected command %s", event.CommandName)
+			}
+
+			for field, value := range tt.condition {
+				assert.Equal(mt, value, filter.Lookup(field).StringValue(), field)
+			}
+			elements, err := update.Lookup("$set").Document().Elements()
+			require.NoError(mt, err)
+			var set []string
+			for _, element := range elements {
+				set = append(set, element.Key())
+			}
+			assert.ElementsMatch(mt, tt.set, set)
+		})
+	}
+}
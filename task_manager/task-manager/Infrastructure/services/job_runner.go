@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"task_manager/utils"
+	"time"
+)
+
+// scheduleCheckInterval bounds how long the scheduler sleeps, so that clock
+// changes are noticed
+const scheduleCheckInterval = time.Minute
+
+type jobSchedule struct {
+	jobType  string
+	schedule utils.CronSchedule
+	payload  []byte
+	next     time.Time
+}
+
+// JobRunner runs background jobs from a JobQueue with a pool of workers, and
+// enqueues jobs on cron schedules. Jobs are registered and scheduled before
+// Start; any instance may enqueue jobs, whether or not it runs workers.
+type JobRunner struct {
+	queue  interfaces.JobQueue
+	config *config.JobsConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	jobs      map[string]interfaces.Job
+	schedules []*jobSchedule
+	wake      chan struct{} // lets an idle worker know a job was just enqueued
+
+	stopClaiming context.CancelFunc // stops workers taking new jobs
+	stopRunning  context.CancelFunc // cancels the attempts in progress
+	workers      sync.WaitGroup
+}
+
+// NewJobRunner creates a JobRunner with jobsConfig.Concurrency workers
+func NewJobRunner(queue interfaces.JobQueue, jobsConfig *config.JobsConfig, logger *slog.Logger) *JobRunner {
+	return &JobRunner{
+		queue:  queue,
+		config: jobsConfig,
+		logger: logger,
+		now:    time.Now,
+		jobs:   make(map[string]interfaces.Job),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Register makes the runner run records of job's type with job
+func (r *JobRunner) Register(job interfaces.Job) {
+	r.jobs[job.Type()] = job
+}
+
+// Schedule enqueues a job of jobType with payload at the times the cron
+// expression spec matches, in UTC. Each run is enqueued once however many
+// instances share the queue.
+func (r *JobRunner) Schedule(spec, jobType string, payload any) error {
+	schedule, err := utils.ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	data, err := r.payload(jobType, payload)
+	if err != nil {
+		return err
+	}
+	r.schedules = append(r.schedules, &jobSchedule{jobType: jobType, schedule: schedule, payload: data})
+	return nil
+}
+
+// Enqueue queues a job of jobType to run as soon as a worker is free.
+// payload is marshalled to JSON.
+func (r *JobRunner) Enqueue(ctx context.Context, jobType string, payload any) (entities.JobRecord, error) {
+	data, err := r.payload(jobType, payload)
+	if err != nil {
+		return entities.JobRecord{}, err
+	}
+	job, _, err := r.enqueue(ctx, entities.JobRecord{Type: jobType, Payload: data, RunAt: r.now()})
+	return job, err
+}
+
+func (r *JobRunner) payload(jobType string, payload any) ([]byte, error) {
+	if _, ok := r.jobs[jobType]; !ok {
+		return nil, fmt.Errorf("no job registered for type %q", jobType)
+	}
+	if payload == nil {
+		return nil, nil
+	}
+	return json.Marshal(payload)
+}
+
+func (r *JobRunner) enqueue(ctx context.Context, job entities.JobRecord) (entities.JobRecord, bool, error) {
+	now := r.now()
+	job.Status = entities.JobQueued
+	job.MaxAttempts = r.config.MaxAttempts
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	job, created, err := r.queue.Enqueue(ctx, job)
+	if err != nil {
+		return entities.JobRecord{}, false, err
+	}
+	if created {
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+	return job, created, nil
+}
+
+// Start starts the workers and the scheduler. It does nothing when the
+// configured concurrency is 0.
+func (r *JobRunner) Start() {
+	claimCtx, stopClaiming := context.WithCancel(context.Background())
+	runCtx, stopRunning := context.WithCancel(context.Background())
+	r.stopClaiming, r.stopRunning = stopClaiming, stopRunning
+	if r.config.Concurrency == 0 {
+		return
+	}
+
+	for range r.config.Concurrency {
+		r.workers.Add(1)
+		go func() {
+			defer r.workers.Done()
+			r.work(claimCtx, runCtx)
+		}()
+	}
+	if len(r.schedules) > 0 {
+		r.workers.Add(1)
+		go func() {
+			defer r.workers.Done()
+			r.runSchedules(claimCtx)
+		}()
+	}
+	r.logger.Info("Started job workers", "concurrency", r.config.Concurrency, "schedules", len(r.schedules))
+}
+
+// Shutdown stops taking jobs and waits for the attempts in progress. If ctx
+// ends first they are cancelled and Shutdown returns without waiting further;
+// a cancelled attempt is retried later like any failed one.
+func (r *JobRunner) Shutdown(ctx context.Context) error {
+	if r.stopClaiming == nil {
+		return nil
+	}
+	r.stopClaiming()
+
+	done := make(chan struct{})
+	go func() {
+		r.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		r.stopRunning()
+		return nil
+	case <-ctx.Done():
+		r.stopRunning()
+		return ctx.Err()
+	}
+}
+
+// work runs jobs until claimCtx ends, waiting for new ones when idle
+func (r *JobRunner) work(claimCtx, runCtx context.Context) {
+	for claimCtx.Err() == nil {
+		if r.runNext(claimCtx, runCtx) {
+			continue
+		}
+		select {
+		case <-claimCtx.Done():
+		case <-r.wake:
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+// runNext claims one due job and runs it. It returns false if there was none.
+func (r *JobRunner) runNext(claimCtx, runCtx context.Context) bool {
+	job, ok, err := r.queue.Claim(claimCtx, r.now(), r.config.Lease)
+	if err != nil {
+		if claimCtx.Err() == nil {
+			r.logger.Error("Failed to claim a job", "error", err)
+		}
+		return false
+	}
+	if !ok {
+		return false
+	}
+	r.run(runCtx, job)
+	return true
+}
+
+// run makes one attempt at job and stores the outcome
+func (r *JobRunner) run(ctx context.Context, job entities.JobRecord) {
+	logger := r.logger.With("job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts)
+
+	// An attempt may not outlive its lease, or another worker would start one too
+	ctx, cancel := context.WithTimeout(ctx, r.config.Lease)
+	defer cancel()
+
+	start := r.now()
+	handler, registered := r.jobs[job.Type]
+	var err error
+	if registered {
+		err = runJob(ctx, logger, handler, job.Payload)
+	} else {
+		err = fmt.Errorf("no job registered for type %q", job.Type)
+	}
+
+	now := r.now()
+	job.UpdatedAt = now
+	switch {
+	case err == nil:
+		job.Status = entities.JobSucceeded
+		job.LastError = ""
+		job.FinishedAt = now
+		logger.Info("Job succeeded", "duration", now.Sub(start))
+	case registered && job.CanRetry():
+		job.Status = entities.JobQueued
+		job.LastError = err.Error()
+		job.RunAt = now.Add(r.backoff(job.Attempts))
+		logger.Warn("Job failed; it will be retried", "error", err, "retry_at", job.RunAt)
+	default:
+		job.Status = entities.JobFailed
+		job.LastError = err.Error()
+		job.FinishedAt = now
+		logger.Error("Job failed", "error", err)
+	}
+
+	// The outcome is stored even when shutting down, so it is not run again
+	stored, err := r.queue.Finish(context.WithoutCancel(ctx), job)
+	if err != nil {
+		logger.Error("Failed to store the outcome of a job", "error", err)
+	} else if !stored {
+		logger.Warn("Job ran past its lease and was taken over; its outcome was discarded")
+	}
+}
+
+// runJob runs handler, turning a panic into an error. The stack is logged
+// rather than stored with the job.
+func runJob(ctx context.Context, logger *slog.Logger, handler interfaces.Job, payload []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("Job panicked", "panic", recovered, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler.Run(ctx, payload)
+}
+
+// backoff is the wait after a failed attempt: RetryBackoff, doubling with each
+// attempt up to MaxRetryBackoff
+func (r *JobRunner) backoff(attempts int) time.Duration {
+	delay := r.config.RetryBackoff
+	for i := 1; i < attempts && delay < r.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxRetryBackoff)
+}
+
+// runSchedules enqueues scheduled jobs as they come due, until ctx ends
+func (r *JobRunner) runSchedules(ctx context.Context) {
+	now := r.now().UTC()
+	for _, s := range r.schedules {
+		s.next = s.schedule.Next(now)
+	}
+
+	for {
+		wait := scheduleCheckInterval
+		for _, s := range r.schedules {
+			if !s.next.IsZero() {
+				wait = min(wait, s.next.Sub(r.now()))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		r.enqueueScheduled(ctx, r.now().UTC())
+	}
+}
+
+// enqueueScheduled enqueues the scheduled runs due by now. Each run's ID names
+// its job type and time, so instances sharing the queue enqueue it only once.
+// Runs missed while no instance was up, or that could not be enqueued, are
+// skipped.
+func (r *JobRunner) enqueueScheduled(ctx context.Context, now time.Time) {
+	for _, s := range r.schedules {
+		if s.next.IsZero() || s.next.After(now) {
+			continue
+		}
+		job := entities.JobRecord{
+			ID:      s.jobType + "@" + s.next.Format(time.RFC3339),
+			Type:    s.jobType,
+			Payload: s.payload,
+			RunAt:   s.next,
+		}
+		if _, _, err := r.enqueue(ctx, job); err != nil {
+			r.logger.Error("Failed to enqueue a scheduled job", "job_type", s.jobType, "error", err)
+		}
+		s.next = s.schedule.Next(now)
+	}
+}
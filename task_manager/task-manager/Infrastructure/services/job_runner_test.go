@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcJob runs a function as a job
+type funcJob struct {
+	jobType string
+	run     func(ctx context.Context, payload []byte) error
+}
+
+func (j funcJob) Type() string                                  { return j.jobType }
+func (j funcJob) Run(ctx context.Context, payload []byte) error { return j.run(ctx, payload) }
+
+// recordingMailer keeps the messages it is asked to send
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []emailPayload
+	err  error
+}
+
+func (m *recordingMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, emailPayload{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+func (m *recordingMailer) messages() []emailPayload {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]emailPayload(nil), m.sent...)
+}
+
+// recordingQueue keeps the context of the last job enqueued
+type recordingQueue struct {
+	interfaces.JobQueue
+	ctx context.Context
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, job entities.JobRecord) (entities.JobRecord, bool, error) {
+	q.ctx = ctx
+	return q.JobQueue.Enqueue(ctx, job)
+}
+
+func testJobsConfig() *config.JobsConfig {
+	jobsConfig := config.Default().Jobs
+	jobsConfig.PollInterval = 10 * time.Millisecond
+	return &jobsConfig
+}
+
+// setupJobRunner returns a runner over an in-memory queue whose clock the test
+// controls
+func setupJobRunner(t *testing.T) (*JobRunner, interfaces.JobQueue, *time.Time) {
+	queue := NewMemoryJobQueue()
+	runner := NewJobRunner(queue, testJobsConfig(), slog.New(slog.DiscardHandler))
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	runner.now = func() time.Time { return now }
+	return runner, queue, &now
+}
+
+func onlyJob(t *testing.T, queue interfaces.JobQueue) entities.JobRecord {
+	t.Helper()
+	jobs, total, err := queue.List(context.Background(), entities.JobFilter{}.WithDefaults())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	return jobs[0]
+}
+
+func TestJobRunner_RetriesWithBackoff(t *testing.T) {
+	runner, queue, now := setupJobRunner(t)
+	ctx := context.Background()
+
+	var payloads []string
+	runner.Register(funcJob{"flaky", func(_ context.Context, payload []byte) error {
+		payloads = append(payloads, string(payload))
+		return errors.New("mail server unavailable")
+	}})
+	_, err := runner.Enqueue(ctx, "flaky", map[string]string{"to": "user@example.com"})
+	require.NoError(t, err)
+
+	// Each failure waits twice as long, up to the limit
+	var delays []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		require.True(t, runner.runNext(ctx, ctx), "attempt %d", attempt)
+		job := onlyJob(t, queue)
+		if attempt < 5 {
+			assert.Equal(t, entities.JobQueued, job.Status)
+			delays = append(delays, job.RunAt.Sub(*now))
+
+			// Not due again until the backoff has passed
+			assert.False(t, runner.runNext(ctx, ctx))
+			*now = job.RunAt
+		} else {
+			assert.Equal(t, entities.JobFailed, job.Status)
+			assert.Equal(t, *now, job.FinishedAt)
+		}
+		assert.Equal(t, attempt, job.Attempts)
+		assert.Equal(t, "mail server unavailable", job.LastError)
+	}
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute}, delays)
+	assert.Equal(t, `{"to":"user@example.com"}`, payloads[0])
+	assert.False(t, runner.runNext(ctx, ctx))
+}
+
+func TestJobRunner_Backoff(t *testing.T) {
+	runner, _, _ := setupJobRunner(t)
+	assert.Equal(t, 30*time.Second, runner.backoff(1))
+	assert.Equal(t, 8*time.Minute, runner.backoff(5))
+	assert.Equal(t, time.Hour, runner.backoff(20))
+}
+
+func TestJobRunner_Failures(t *testing.T) {
+	runner, queue, _ := setupJobRunner(t)
+	ctx := context.Background()
+
+	// A panic is an ordinary failure
+	runner.Register(funcJob{"panics", func(context.Context, []byte) error { panic("boom") }})
+	_, err := runner.Enqueue(ctx, "panics", nil)
+	require.NoError(t, err)
+	require.True(t, runner.runNext(ctx, ctx))
+	job := onlyJob(t, queue)
+	assert.Equal(t, entities.JobQueued, job.Status)
+	assert.Equal(t, "panic: boom", job.LastError)
+
+	// Only registered types can be enqueued
+	_, err = runner.Enqueue(ctx, "unknown", nil)
+	assert.ErrorContains(t, err, `no job registered for type "unknown"`)
+}
+
+func TestJobRunner_UnknownTypeFailsAtOnce(t *testing.T) {
+	runner, queue, now := setupJobRunner(t)
+	ctx := context.Background()
+
+	// Enqueued by an instance that knows a type this one does not
+	_, _, err := queue.Enqueue(ctx, entities.JobRecord{Type: "newer", Status: entities.JobQueued, MaxAttempts: 5, RunAt: *now})
+	require.NoError(t, err)
+
+	require.True(t, runner.runNext(ctx, ctx))
+	job := onlyJob(t, queue)
+	assert.Equal(t, entities.JobFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestJobRunner_LeaseTakeover(t *testing.T) {
+	runner, queue, now := setupJobRunner(t)
+	ctx := context.Background()
+	runner.Register(funcJob{"slow", func(context.Context, []byte) error { return nil }})
+	_, err := runner.Enqueue(ctx, "slow", nil)
+	require.NoError(t, err)
+
+	first, ok, err := queue.Claim(ctx, *now, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Still leased
+	_, ok, err = queue.Claim(ctx, now.Add(59*time.Second), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The worker is presumed gone once the lease passes
+	second, ok, err := queue.Claim(ctx, now.Add(time.Minute), time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 2, second.Attempts)
+
+	// The first worker's late outcome is discarded
+	first.Status = entities.JobSucceeded
+	stored, err := queue.Finish(ctx, first)
+	require.NoError(t, err)
+	assert.False(t, stored)
+
+	second.Status = entities.JobSucceeded
+	second.FinishedAt = now.Add(time.Minute)
+	stored, err = queue.Finish(ctx, second)
+	require.NoError(t, err)
+	assert.True(t, stored)
+	assert.Equal(t, entities.JobSucceeded, onlyJob(t, queue).Status)
+}
+
+func TestJobRunner_ScheduledRunsAreEnqueuedOnce(t *testing.T) {
+	queue := NewMemoryJobQueue()
+	start := time.Date(2026, 3, 4, 2, 59, 0, 0, time.UTC)
+
+	// Two instances share the queue
+	var runners []*JobRunner
+	for range 2 {
+		runner := NewJobRunner(queue, testJobsConfig(), slog.New(slog.DiscardHandler))
+		runner.Register(funcJob{PurgeJobsJobType, func(context.Context, []byte) error { return nil }})
+		require.NoError(t, runner.Schedule("0 3 * * *", PurgeJobsJobType, nil))
+		runner.schedules[0].next = runner.schedules[0].schedule.Next(start)
+		runners = append(runners, runner)
+	}
+
+	for _, runner := range runners {
+		runner.enqueueScheduled(context.Background(), start.Add(30*time.Second))
+		runner.enqueueScheduled(context.Background(), start.Add(time.Minute))
+	}
+
+	job := onlyJob(t, queue)
+	assert.Equal(t, "purge_jobs@2026-03-04T03:00:00Z", job.ID)
+	assert.Equal(t, time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC), job.RunAt)
+	for _, runner := range runners {
+		assert.Equal(t, time.Date(2026, 3, 5, 3, 0, 0, 0, time.UTC), runner.schedules[0].next)
+	}
+
+	assert.Error(t, runners[0].Schedule("daily", PurgeJobsJobType, nil))
+}
+
+func TestJobRunner_WorkersSendQueuedMail(t *testing.T) {
+	queue := NewMemoryJobQueue()
+	runner := NewJobRunner(queue, testJobsConfig(), slog.New(slog.DiscardHandler))
+	mailer := &recordingMailer{}
+	runner.Register(NewSendEmailJob(mailer, []byte(testSecret)))
+
+	require.NoError(t, NewQueuedMailer(runner, []byte(testSecret)).Send(context.Background(), "user@example.com", "Reset your password", "https://example.com/reset?token=secret"))
+
+	// The stored job does not give the message away
+	queued := onlyJob(t, queue)
+	assert.NotContains(t, string(queued.Payload), "user@example.com")
+	assert.NotContains(t, string(queued.Payload), "token=secret")
+
+	runner.Start()
+	assert.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, emailPayload{To: "user@example.com", Subject: "Reset your password", Body: "https://example.com/reset?token=secret"}, mailer.messages()[0])
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.Eventually(t, func() bool { return onlyJob(t, queue).Status == entities.JobSucceeded }, time.Second, 5*time.Millisecond)
+
+	// Once sent, the payload is no longer kept
+	assert.Nil(t, onlyJob(t, queue).Payload)
+}
+
+func TestQueuedMailer_KeepsRequestContext(t *testing.T) {
+	queue := &recordingQueue{JobQueue: NewMemoryJobQueue()}
+	runner := NewJobRunner(queue, testJobsConfig(), slog.New(slog.DiscardHandler))
+	runner.Register(NewSendEmailJob(&recordingMailer{}, []byte(testSecret)))
+
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "org1"))
+	cancel()
+	require.NoError(t, NewQueuedMailer(runner, []byte(testSecret)).Send(ctx, "user@example.com", "Hello", "Body"))
+	require.NotNil(t, queue.ctx)
+	assert.Equal(t, "org1", queue.ctx.Value(key{}))
+	assert.NoError(t, queue.ctx.Err())
+}
+
+func TestJobRunner_Shutdown(t *testing.T) {
+	queue := NewMemoryJobQueue()
+	runner := NewJobRunner(queue, testJobsConfig(), slog.New(slog.DiscardHandler))
+	started := make(chan struct{})
+	runner.Register(funcJob{"stuck", func(ctx context.Context, _ []byte) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	runner.Start()
+	_, err := runner.Enqueue(context.Background(), "stuck", nil)
+	require.NoError(t, err)
+	<-started
+
+	// The attempt does not finish in time, so it is cancelled and retried later
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, runner.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Eventually(t, func() bool {
+		job := onlyJob(t, queue)
+		return job.Status == entities.JobQueued && job.LastError == context.Canceled.Error()
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMemoryJobQueue_ListAndPurge(t *testing.T) {
+	queue := NewMemoryJobQueue()
+	ctx := context.Background()
+	base := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	for i, status := range []string{entities.JobSucceeded, entities.JobFailed, entities.JobQueued} {
+		job := entities.JobRecord{Type: SendEmailJobType, Status: status, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if status != entities.JobQueued {
+			job.FinishedAt = job.CreatedAt
+		}
+		_, created, err := queue.Enqueue(ctx, job)
+		require.NoError(t, err)
+		assert.True(t, created)
+	}
+
+	jobs, total, err := queue.List(ctx, entities.JobFilter{Page: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, entities.JobQueued, jobs[0].Status)
+	assert.Equal(t, entities.JobFailed, jobs[1].Status)
+
+	jobs, total, err = queue.List(ctx, entities.JobFilter{Status: entities.JobSucceeded, Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Len(t, jobs, 1)
+
+	// Only jobs that finished before the cutoff go
+	purge := NewPurgeJobsJob(queue, time.Hour).(purgeJobsJob)
+	purge.now = func() time.Time { return base.Add(time.Hour + 30*time.Second) }
+	require.NoError(t, purge.Run(ctx, nil))
+	_, total, err = queue.List(ctx, entities.JobFilter{Page: 1, Limit: 20})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+}
+
+func TestSendEmailJob_InvalidPayload(t *testing.T) {
+	job := NewSendEmailJob(&recordingMailer{}, []byte(testSecret))
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, job.Run(context.Background(), []byte("{")), &syntaxErr)
+
+	// Sealed under another secret
+	sealed, err := json.Marshal(sealedEmailPayload{Sealed: newSealer([]byte("another-secret"), emailSealLabel).seal([]byte(`{}`))})
+	require.NoError(t, err)
+	assert.ErrorContains(t, job.Run(context.Background(), sealed), "opening queued email")
+}
+
+func TestSendEmailJob_UnsealedPayload(t *testing.T) {
+	// Mail queued before payloads were sealed is still delivered
+	mailer := &recordingMailer{}
+	job := NewSendEmailJob(mailer, []byte(testSecret))
+	require.NoError(t, job.Run(context.Background(), []byte(`{"to":"user@example.com","subject":"Hello","body":"Body"}`)))
+	assert.Equal(t, []emailPayload{{To: "user@example.com", Subject: "Hello", Body: "Body"}}, mailer.messages())
+}
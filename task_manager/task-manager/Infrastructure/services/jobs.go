@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"task_manager/Domain/interfaces"
+	"time"
+)
+
+// Job types run by the service
+const (
+	SendEmailJobType = "send_email"
+	PurgeJobsJobType = "purge_jobs"
+)
+
+// emailSealLabel derives the key that queued email is sealed with
+const emailSealLabel = "queued email"
+
+// emailPayload is an email waiting to be sent
+type emailPayload struct {
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body,omitempty"`
+}
+
+// sealedEmailPayload is the stored payload of a send_email job. Messages carry
+// reset and verification tokens, so the emailPayload is kept sealed in Sealed.
+// Jobs queued before sealing was added have its fields instead.
+type sealedEmailPayload struct {
+	emailPayload
+	Sealed []byte `json:"sealed,omitempty"`
+}
+
+type sendEmailJob struct {
+	mailer interfaces.Mailer
+	sealer sealer
+}
+
+// NewSendEmailJob creates the job that delivers email queued by a mailer from
+// NewQueuedMailer with the same secret, using mailer
+func NewSendEmailJob(mailer interfaces.Mailer, secret []byte) interfaces.Job {
+	return sendEmailJob{mailer: mailer, sealer: newSealer(secret, emailSealLabel)}
+}
+
+func (j sendEmailJob) Type() string { return SendEmailJobType }
+
+func (j sendEmailJob) Run(ctx context.Context, payload []byte) error {
+	var stored sealedEmailPayload
+	if err := json.Unmarshal(payload, &stored); err != nil {
+		return err
+	}
+
+	email := stored.emailPayload
+	if stored.Sealed != nil {
+		plaintext, err := j.sealer.open(stored.Sealed)
+		if err != nil {
+			return fmt.Errorf("opening queued email: %w", err)
+		}
+		if err := json.Unmarshal(plaintext, &email); err != nil {
+			return err
+		}
+	}
+	return j.mailer.Send(ctx, email.To, email.Subject, email.Body)
+}
+
+type queuedMailer struct {
+	runner *JobRunner
+	sealer sealer
+}
+
+// NewQueuedMailer creates a mailer that queues each message as a background
+// job, so requests do not wait for the mail server and failed deliveries are
+// retried. Messages are stored encrypted with a key derived from secret.
+// runner must have a job from NewSendEmailJob registered.
+func NewQueuedMailer(runner *JobRunner, secret []byte) interfaces.Mailer {
+	return queuedMailer{runner: runner, sealer: newSealer(secret, emailSealLabel)}
+}
+
+func (m queuedMailer) Send(ctx context.Context, to, subject, body string) error {
+	plaintext, err := json.Marshal(emailPayload{To: to, Subject: subject, Body: body})
+	if err != nil {
+		return err
+	}
+	// The request's trace and organization are kept, but a client going away
+	// must not stop mail for a change already made
+	_, err = m.runner.Enqueue(context.WithoutCancel(ctx), SendEmailJobType, sealedEmailPayload{Sealed: m.sealer.seal(plaintext)})
+	return err
+}
+
+type purgeJobsJob struct {
+	queue     interfaces.JobQueue
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewPurgeJobsJob creates the job that deletes jobs finished more than
+// retention ago
+func NewPurgeJobsJob(queue interfaces.JobQueue, retention time.Duration) interfaces.Job {
+	return purgeJobsJob{queue: queue, retention: retention, now: time.Now}
+}
+
+func (j purgeJobsJob) Type() string { return PurgeJobsJobType }
+
+func (j purgeJobsJob) Run(ctx context.Context, payload []byte) error {
+	_, err := j.queue.PurgeFinished(ctx, j.now().Add(-j.retention))
+	return err
+}
@@ -1,67 +1,136 @@
 package services
 
 import (
-	"time"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
 	"task_manager/Domain/interfaces"
 	"task_manager/config"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type jwtService struct {
-	secret []byte
+	method   jwt.SigningMethod
+	tokenTTL time.Duration
+	// Access tokens are signed with keys from the ring, or with secret when
+	// the configured algorithm is HS256
+	keys         *keyRing
+	secret       []byte
+	actionSecret []byte
 }
 
-// NewJWTService creates a new JWT service with secret from config
-func NewJWTService() interfaces.TokenService {
-	appConfig := config.NewAppConfig()
-	
-	return &jwtService{
-		secret: []byte(appConfig.JWTSecret),
+// NewJWTService creates a new JWT service from config. secret signs action
+// tokens, and access tokens too with HS256. With an asymmetric algorithm the
+// signing keys are kept in keyRepo, encrypted with a key derived from secret,
+// and rotated automatically.
+func NewJWTService(keyRepo interfaces.SigningKeyRepository, jwtConfig *config.JWTConfig, secret string, logger *slog.Logger) (interfaces.TokenService, error) {
+	service := &jwtService{
+		tokenTTL:     jwtConfig.TokenTTL,
+		secret:       []byte(secret),
+		actionSecret: deriveKey([]byte(secret), "action-token"),
+	}
+
+	switch jwtConfig.Algorithm {
+	case config.JWTAlgorithmHS256:
+		service.method = jwt.SigningMethodHS256
+	case config.JWTAlgorithmRS256:
+		service.method = jwt.SigningMethodRS256
+	case config.JWTAlgorithmEdDSA:
+		service.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported JWT algorithm " + jwtConfig.Algorithm)
+	}
+
+	if jwtConfig.UsesKeyPairs() {
+		keys, err := newKeyRing(keyRepo, jwtConfig, []byte(secret), logger)
+		if err != nil {
+			return nil, err
+		}
+		service.keys = keys
 	}
+
+	return service, nil
+}
+
+// deriveKey derives a purpose-specific signing key so that tokens signed for one
+// use can never validate as another (e.g. a reset token as an access token)
+func deriveKey(secret []byte, label string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
 }
 
 type CustomClaims struct {
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	OrganizationID string `json:"org_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActionClaims are carried by single-use action tokens; the audience holds the purpose
+type ActionClaims struct {
+	Email       string `json:"email"`
+	Fingerprint string `json:"fp"`
 	jwt.RegisteredClaims
 }
 
-func (j *jwtService) GenerateToken(email, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+func (j *jwtService) GenerateToken(email, role, organizationID string) (string, error) {
+	now := time.Now()
 	claims := &CustomClaims{
-		Email: email,
-		Role:  role,
+		Email:          email,
+		Role:           role,
+		OrganizationID: organizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.tokenTTL)),
 		},
 	}
-	
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+
+	token := jwt.NewWithClaims(j.method, claims)
+	if j.keys == nil {
+		return token.SignedString(j.secret)
+	}
+
+	key, err := j.keys.signingKey(now)
+	if err != nil {
+		return "", err
+	}
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signer)
 }
 
-func (j *jwtService) ValidateToken(tokenString string) (string, string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.secret, nil
-	})
+// parseAccessToken verifies an access token. Only the configured algorithm is
+// accepted, so a token cannot pick a weaker one (or HS256 keyed with a public key).
+func (j *jwtService) parseAccessToken(tokenString string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if j.keys == nil {
+			return j.secret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return j.keys.publicKey(kid, time.Now())
+	}, jwt.WithValidMethods([]string{j.method.Alg()}))
+}
+
+func (j *jwtService) ValidateToken(tokenString string) (string, string, string, error) {
+	token, err := j.parseAccessToken(tokenString)
 
 	if err != nil || !token.Valid {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	claims, ok := token.Claims.(*CustomClaims)
 	if !ok {
-		return "", "", jwt.ErrSignatureInvalid
+		return "", "", "", jwt.ErrSignatureInvalid
 	}
 
-	return claims.Email, claims.Role, nil
+	return claims.Email, claims.Role, claims.OrganizationID, nil
 }
 
 func (j *jwtService) ExtractClaims(tokenString string) (map[string]interface{}, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.secret, nil
-	})
+	token, err := j.parseAccessToken(tokenString)
 
 	if err != nil || !token.Valid {
 		return nil, err
@@ -73,8 +142,49 @@ func (j *jwtService) ExtractClaims(tokenString string) (map[string]interface{},
 	}
 
 	return map[string]interface{}{
-		"email": claims.Email,
-		"role":  claims.Role,
-		"exp":   claims.ExpiresAt,
+		"email":  claims.Email,
+		"role":   claims.Role,
+		"org_id": claims.OrganizationID,
+		"exp":    claims.ExpiresAt,
 	}, nil
-} 
\ No newline at end of file
+}
+
+func (j *jwtService) GenerateActionToken(email, purpose, fingerprint string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &ActionClaims{
+		Email:       email,
+		Fingerprint: fingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{purpose},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.actionSecret)
+}
+
+func (j *jwtService) ValidateActionToken(tokenString, purpose string) (string, string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return j.actionSecret, nil
+	}, jwt.WithAudience(purpose), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil || !token.Valid {
+		return "", "", err
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok {
+		return "", "", jwt.ErrSignatureInvalid
+	}
+
+	return claims.Email, claims.Fingerprint, nil
+}
+
+func (j *jwtService) JWKS() ([]interfaces.JSONWebKey, error) {
+	if j.keys == nil {
+		return []interfaces.JSONWebKey{}, nil
+	}
+	return j.keys.jwks(time.Now())
+}
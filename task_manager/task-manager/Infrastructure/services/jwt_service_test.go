@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"log/slog"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryKeyRepository is a SigningKeyRepository shared by the instances in a test
+type memoryKeyRepository struct {
+	mu   sync.Mutex
+	keys []entities.SigningKey
+}
+
+func (r *memoryKeyRepository) ListKeys(ctx context.Context, algorithm string, now time.Time) ([]entities.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var keys []entities.SigningKey
+	for _, key := range r.keys {
+		if key.Algorithm == algorithm && key.CanVerify(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryKeyRepository) InsertOne(ctx context.Context, key entities.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.keys[:0]
+	for _, key := range r.keys {
+		if key.CanVerify(now) {
+			kept = append(kept, key)
+		}
+	}
+	deleted := int64(len(r.keys) - len(kept))
+	r.keys = kept
+	return deleted, nil
+}
+
+var _ interfaces.SigningKeyRepository = (*memoryKeyRepository)(nil)
+
+// testLogger discards what the services log
+var testLogger = slog.New(slog.DiscardHandler)
+
+const testSecret = "test-secret-test-secret-test-secret"
+
+func testJWTConfig(algorithm string) *config.JWTConfig {
+	return &config.JWTConfig{Algorithm: algorithm, TokenTTL: time.Hour, KeyRotation: 24 * time.Hour}
+}
+
+func TestJWTService_KeyPairs(t *testing.T) {
+	for _, algorithm := range []string{config.JWTAlgorithmEdDSA, config.JWTAlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			tokenService, err := NewJWTService(&memoryKeyRepository{}, testJWTConfig(algorithm), testSecret, testLogger)
+			require.NoError(t, err)
+
+			token, err := tokenService.GenerateToken("test@example.com", "admin", "org1")
+			require.NoError(t, err)
+
+			email, role, organizationID, err := tokenService.ValidateToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "test@example.com", email)
+			assert.Equal(t, "admin", role)
+			assert.Equal(t, "org1", organizationID)
+
+			// The token names its key, which is published
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &CustomClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, parsed.Method.Alg())
+
+			keys, err := tokenService.JWKS()
+			require.NoError(t, err)
+			require.Len(t, keys, 1)
+			assert.Equal(t, parsed.Header["kid"], keys[0].KeyID)
+			assert.Equal(t, algorithm, keys[0].Algorithm)
+		})
+	}
+}
+
+func TestJWTService_RejectsOtherAlgorithms(t *testing.T) {
+	tokenService, err := NewJWTService(&memoryKeyRepository{}, testJWTConfig(config.JWTAlgorithmEdDSA), testSecret, testLogger)
+	require.NoError(t, err)
+
+	// An HS256 token made with the shared secret is not an access token
+	claims := &CustomClaims{Email: "test@example.com", Role: "admin", RegisteredClaims: jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+
+	_, _, _, err = tokenService.ValidateToken(forged)
+	assert.Error(t, err)
+
+	// Neither is an action token
+	actionToken, err := tokenService.GenerateActionToken("test@example.com", interfaces.TokenPurposePasswordReset, "fp", time.Hour)
+	require.NoError(t, err)
+
+	_, _, _, err = tokenService.ValidateToken(actionToken)
+	assert.Error(t, err)
+}
+
+func TestJWTService_HS256(t *testing.T) {
+	tokenService, err := NewJWTService(nil, testJWTConfig(config.JWTAlgorithmHS256), testSecret, testLogger)
+	require.NoError(t, err)
+
+	token, err := tokenService.GenerateToken("test@example.com", "user", entities.DefaultOrganizationID)
+	require.NoError(t, err)
+
+	email, _, organizationID, err := tokenService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", email)
+	assert.Equal(t, entities.DefaultOrganizationID, organizationID)
+
+	keys, err := tokenService.JWKS()
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestJWTService_TokensWithoutOrganization(t *testing.T) {
+	tokenService, err := NewJWTService(nil, testJWTConfig(config.JWTAlgorithmHS256), testSecret, testLogger)
+	require.NoError(t, err)
+
+	// Issued before organizations existed
+	claims := &CustomClaims{Email: "test@example.com", Role: "user", RegisteredClaims: jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+
+	email, _, organizationID, err := tokenService.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", email)
+	assert.Empty(t, organizationID)
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	repo := &memoryKeyRepository{}
+	jwtConfig := testJWTConfig(config.JWTAlgorithmEdDSA)
+
+	ring, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+
+	now := time.Now()
+	first, err := ring.signingKey(now)
+	require.NoError(t, err)
+
+	// A second instance sharing the repository uses the same key
+	other, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+	otherKey, err := other.signingKey(now)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, otherKey.ID)
+
+	// Once due, a new key signs while the old one still verifies
+	later := now.Add(jwtConfig.KeyRotation + time.Minute)
+	second, err := ring.signingKey(later)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	_, err = ring.publicKey(first.ID, later)
+	assert.NoError(t, err)
+
+	// The other instance picks up the new key when it sees it
+	_, err = other.publicKey(second.ID, later)
+	assert.NoError(t, err)
+
+	// After the old key's tokens have expired it is dropped
+	expired := first.ExpiresAt.Add(time.Second)
+	_, err = ring.publicKey(first.ID, expired)
+	assert.ErrorIs(t, err, errUnknownSigningKey)
+
+	third, err := ring.signingKey(expired.Add(jwtConfig.KeyRotation))
+	require.NoError(t, err)
+	assert.NotEqual(t, second.ID, third.ID)
+	for _, key := range repo.keys {
+		assert.NotEqual(t, first.ID, key.ID)
+	}
+}
+
+func TestKeyRing_PublishesNextKeyEarly(t *testing.T) {
+	repo := &memoryKeyRepository{}
+	jwtConfig := testJWTConfig(config.JWTAlgorithmEdDSA)
+
+	ring, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+	other, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+
+	first, err := ring.signingKey(time.Now())
+	require.NoError(t, err)
+
+	// Near rotation the next key is created, but the current one keeps signing
+	publish := first.RotateAt.Add(-keyPublishLead)
+	current, err := ring.signingKey(publish)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, current.ID)
+	require.Len(t, repo.keys, 2)
+	next := repo.keys[1]
+	assert.Equal(t, first.RotateAt, next.ActiveAt)
+
+	// Every instance publishes it well before a verifier's cached set runs out
+	jwks, err := other.jwks(publish.Add(jwksRefreshInterval))
+	require.NoError(t, err)
+	var kids []string
+	for _, jwk := range jwks {
+		kids = append(kids, jwk.KeyID)
+	}
+	assert.ElementsMatch(t, []string{first.ID, next.ID}, kids)
+
+	// It signs once the current key rotates, without creating another
+	for _, instance := range []*keyRing{ring, other} {
+		key, err := instance.signingKey(first.RotateAt)
+		require.NoError(t, err)
+		assert.Equal(t, next.ID, key.ID)
+	}
+	assert.Len(t, repo.keys, 2)
+}
+
+func TestKeyRing_StoresKeysSealed(t *testing.T) {
+	repo := &memoryKeyRepository{}
+	jwtConfig := testJWTConfig(config.JWTAlgorithmEdDSA)
+
+	ring, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+	key, err := ring.signingKey(time.Now())
+	require.NoError(t, err)
+
+	// What is stored is not a usable private key
+	require.Len(t, repo.keys, 1)
+	assert.True(t, repo.keys[0].Sealed)
+	_, err = x509.ParsePKCS8PrivateKey(repo.keys[0].PrivateKey)
+	assert.Error(t, err)
+
+	// Without the secret, the stored keys cannot be loaded
+	_, err = newKeyRing(repo, jwtConfig, []byte("another-secret"), testLogger)
+	assert.ErrorContains(t, err, "decrypting")
+
+	// Keys stored before keys were sealed are still read
+	_, legacy, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(legacy)
+	require.NoError(t, err)
+	plain := key.SigningKey
+	plain.ID, plain.PrivateKey, plain.Sealed = "legacy", der, false
+	require.NoError(t, repo.InsertOne(context.Background(), plain))
+
+	other, err := newKeyRing(repo, jwtConfig, []byte(testSecret), testLogger)
+	require.NoError(t, err)
+	public, err := other.publicKey("legacy", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, legacy.Public(), public)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"time"
+)
+
+const (
+	// keyReloadInterval limits how often an unknown kid triggers a reload, so
+	// forged tokens cannot be used to hammer the database
+	keyReloadInterval = 10 * time.Second
+	// jwksRefreshInterval bounds how stale the published key set can be when
+	// another instance rotates
+	jwksRefreshInterval = time.Minute
+	// keyExpiryLeeway keeps retired keys a little past their last token's expiry
+	keyExpiryLeeway = 5 * time.Minute
+	// keyPublishLead is how long the next key is published before it signs. It
+	// covers the five minutes verifiers may cache the JWKS, plus the
+	// jwksRefreshInterval other instances may take to list it.
+	keyPublishLead = 10 * time.Minute
+
+	rsaKeyBits = 2048
+
+	// signingKeySealLabel derives the key private keys are stored encrypted with
+	signingKeySealLabel = "signing key"
+)
+
+var errUnknownSigningKey = errors.New("unknown signing key")
+
+// keyRing holds the asymmetric keys access tokens are signed with. Keys are kept
+// in a repository shared by all instances; whichever instance first finds the
+// active key close to rotation creates the next one, which is published for
+// keyPublishLead before it starts signing. Private keys are stored sealed with
+// a key derived from the server secret, so the database alone cannot forge
+// tokens. Token operations are not tied to a request, so the repository's own
+// timeout bounds each call.
+type keyRing struct {
+	repo      interfaces.SigningKeyRepository
+	algorithm string
+	rotation  time.Duration
+	tokenTTL  time.Duration
+	sealer    sealer
+	logger    *slog.Logger
+
+	mu         sync.RWMutex
+	keys       map[string]ringKey
+	active     *ringKey
+	next       *ringKey // published, but not signing yet
+	lastReload time.Time
+}
+
+type ringKey struct {
+	entities.SigningKey
+	signer crypto.Signer
+}
+
+func newKeyRing(repo interfaces.SigningKeyRepository, jwtConfig *config.JWTConfig, secret []byte, logger *slog.Logger) (*keyRing, error) {
+	ring := &keyRing{
+		repo:      repo,
+		algorithm: jwtConfig.Algorithm,
+		rotation:  jwtConfig.KeyRotation,
+		tokenTTL:  jwtConfig.TokenTTL,
+		sealer:    newSealer(secret, signingKeySealLabel),
+		logger:    logger,
+		keys:      map[string]ringKey{},
+	}
+
+	// Make sure a key exists so the published key set is never empty
+	if _, err := ring.signingKey(time.Now()); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// signingKey returns the key new tokens are signed with, publishing the next key
+// when rotation is near. A key is only created to sign at once when none is
+// active, e.g. on first start or after no token was issued for the whole lead.
+func (r *keyRing) signingKey(now time.Time) (ringKey, error) {
+	r.mu.RLock()
+	active, next := r.active, r.next
+	r.mu.RUnlock()
+	if active != nil && active.CanSign(now) && (next != nil || now.Before(r.publishAt(*active))) {
+		return *active, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another request, or another instance, may have rotated in the meantime
+	if err := r.reloadLocked(now); err != nil {
+		return ringKey{}, err
+	}
+
+	if r.active == nil {
+		key, err := r.createLocked(now, now)
+		if err != nil {
+			return ringKey{}, err
+		}
+		r.active = &key
+	}
+	if r.next == nil && !now.Before(r.publishAt(*r.active)) {
+		key, err := r.createLocked(now, r.active.RotateAt)
+		if err != nil {
+			return ringKey{}, err
+		}
+		r.next = &key
+	}
+	return *r.active, nil
+}
+
+// publishAt returns when the key that follows active is due to be published
+func (r *keyRing) publishAt(active ringKey) time.Time {
+	return active.RotateAt.Add(-min(keyPublishLead, r.rotation/2))
+}
+
+// createLocked stores a new key that signs from activeAt. r.mu must be held.
+func (r *keyRing) createLocked(now, activeAt time.Time) (ringKey, error) {
+	key, err := r.generate(now, activeAt)
+	if err != nil {
+		return ringKey{}, err
+	}
+	if err := r.repo.InsertOne(context.Background(), key.SigningKey); err != nil {
+		return ringKey{}, err
+	}
+	r.keys[key.ID] = key
+
+	if _, err := r.repo.DeleteExpired(context.Background(), now); err != nil {
+		r.logger.Error("Deleting expired signing keys failed", "error", err)
+	}
+
+	r.logger.Info("Created signing key", "algorithm", r.algorithm, "kid", key.ID, "active_at", key.ActiveAt)
+	return key, nil
+}
+
+// publicKey returns the key that verifies tokens signed with kid
+func (r *keyRing) publicKey(kid string, now time.Time) (crypto.PublicKey, error) {
+	r.mu.RLock()
+	key, ok := r.keys[kid]
+	r.mu.RUnlock()
+
+	if !ok {
+		// The key may have been created by another instance
+		r.mu.Lock()
+		if now.Sub(r.lastReload) >= keyReloadInterval {
+			if err := r.reloadLocked(now); err != nil {
+				r.logger.Error("Reloading signing keys failed", "error", err)
+			}
+		}
+		key, ok = r.keys[kid]
+		r.mu.Unlock()
+	}
+
+	if !ok || !key.CanVerify(now) {
+		return nil, errUnknownSigningKey
+	}
+	return key.signer.Public(), nil
+}
+
+// jwks returns every key that still verifies tokens, in JWK form
+func (r *keyRing) jwks(now time.Time) ([]interfaces.JSONWebKey, error) {
+	r.mu.Lock()
+	if now.Sub(r.lastReload) >= jwksRefreshInterval {
+		if err := r.reloadLocked(now); err != nil {
+			r.mu.Unlock()
+			return nil, err
+		}
+	}
+	keys := make([]ringKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	r.mu.Unlock()
+
+	jwks := make([]interfaces.JSONWebKey, 0, len(keys))
+	for _, key := range keys {
+		if !key.CanVerify(now) {
+			continue
+		}
+		jwk, err := toJSONWebKey(key)
+		if err != nil {
+			return nil, err
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks, nil
+}
+
+// reloadLocked replaces the in-memory keys with the stored ones. r.mu must be held.
+func (r *keyRing) reloadLocked(now time.Time) error {
+	stored, err := r.repo.ListKeys(context.Background(), r.algorithm, now)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]ringKey, len(stored))
+	var active, next *ringKey
+	for _, signingKey := range stored {
+		signer, err := r.open(signingKey)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", signingKey.ID, err)
+		}
+
+		key := ringKey{SigningKey: signingKey, signer: signer}
+		keys[key.ID] = key
+		if key.CanSign(now) && (active == nil || key.CreatedAt.After(active.CreatedAt)) {
+			active = &key
+		}
+		if now.Before(key.ActiveAt) {
+			next = &key
+		}
+	}
+
+	r.keys = keys
+	r.active = active
+	r.next = next
+	r.lastReload = now
+	return nil
+}
+
+// open returns the private key of a stored key
+func (r *keyRing) open(key entities.SigningKey) (crypto.Signer, error) {
+	der := key.PrivateKey
+	if key.Sealed {
+		var err error
+		if der, err = r.sealer.open(der); err != nil {
+			return nil, fmt.Errorf("decrypting: %w", err)
+		}
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+	return signer, nil
+}
+
+func (r *keyRing) generate(now, activeAt time.Time) (ringKey, error) {
+	var signer crypto.Signer
+	var err error
+	switch r.algorithm {
+	case config.JWTAlgorithmRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case config.JWTAlgorithmEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm %q", r.algorithm)
+	}
+	if err != nil {
+		return ringKey{}, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return ringKey{}, err
+	}
+
+	kid := make([]byte, 12)
+	if _, err := rand.Read(kid); err != nil {
+		return ringKey{}, err
+	}
+
+	rotateAt := activeAt.Add(r.rotation)
+	return ringKey{
+		SigningKey: entities.SigningKey{
+			ID:         base64.RawURLEncoding.EncodeToString(kid),
+			Algorithm:  r.algorithm,
+			PrivateKey: r.sealer.seal(der),
+			Sealed:     true,
+			CreatedAt:  now,
+			ActiveAt:   activeAt,
+			RotateAt:   rotateAt,
+			ExpiresAt:  rotateAt.Add(r.tokenTTL + keyExpiryLeeway),
+		},
+		signer: signer,
+	}, nil
+}
+
+func toJSONWebKey(key ringKey) (interfaces.JSONWebKey, error) {
+	jwk := interfaces.JSONWebKey{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+
+	switch public := key.signer.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return interfaces.JSONWebKey{}, fmt.Errorf("signing key %s: unsupported key type %T", key.ID, public)
+	}
+
+	return jwk, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"task_manager/Domain/interfaces"
+	"time"
+)
+
+type logMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogMailer creates a mailer for local development that writes messages to
+// a file instead of sending them. An empty path writes to the application log.
+func NewLogMailer(path string) (interfaces.Mailer, error) {
+	if path == "" {
+		return &logMailer{out: log.Writer()}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening mail log: %w", err)
+	}
+	return &logMailer{out: file}, nil
+}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.out, "=== mail %s ===\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), to, subject, body)
+	return err
+}
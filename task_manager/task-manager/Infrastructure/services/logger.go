@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"task_manager/config"
+	"task_manager/utils"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewLogger creates the application logger. Records logged with a context are
+// tagged with the request ID and trace it carries, so everything logged while
+// serving a request can be correlated with its access log line and its spans.
+func NewLogger(loggingConfig *config.LoggingConfig, out io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(loggingConfig.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if strings.EqualFold(loggingConfig.Format, "text") {
+		handler = slog.NewTextHandler(out, options)
+	} else {
+		handler = slog.NewJSONHandler(out, options)
+	}
+
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler adds request-scoped attributes from the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"task_manager/config"
+	"task_manager/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewLogger_RequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&config.LoggingConfig{Level: "info", Format: "json"}, &out)
+
+	ctx := utils.ContextWithRequestID(context.Background(), "req-123")
+	logger.With("component", "test").InfoContext(ctx, "hello", "user", "test@example.com")
+	logger.Debug("not logged at info level")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "hello", record["msg"])
+	assert.Equal(t, "req-123", record["request_id"])
+	assert.Equal(t, "test", record["component"])
+	assert.Equal(t, "test@example.com", record["user"])
+}
+
+func TestNewLogger_Trace(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&config.LoggingConfig{Level: "info", Format: "json"}, &out)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	logger.InfoContext(ctx, "hello")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"sync"
+	"task_manager/Domain/interfaces"
+	"time"
+)
+
+// maxTrackedClients bounds memory use; expired entries are swept beyond it
+const maxTrackedClients = 10000
+
+type failureRecord struct {
+	failures    int
+	lastFailure time.Time
+}
+
+type memoryLoginThrottle struct {
+	mu        sync.Mutex
+	records   map[string]*failureRecord
+	threshold int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	window    time.Duration
+	now       func() time.Time
+}
+
+// NewMemoryLoginThrottle creates an in-process LoginThrottle. After threshold
+// failures within window, each further attempt must wait baseDelay, doubling
+// per failure up to maxDelay. Counts are forgotten after window without failures.
+func NewMemoryLoginThrottle(threshold int, baseDelay, maxDelay, window time.Duration) interfaces.LoginThrottle {
+	return &memoryLoginThrottle{
+		records:   make(map[string]*failureRecord),
+		threshold: threshold,
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		window:    window,
+		now:       time.Now,
+	}
+}
+
+func (t *memoryLoginThrottle) RetryAfter(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.current(key)
+	if record == nil || record.failures < t.threshold {
+		return 0
+	}
+
+	delay := t.baseDelay
+	for i := t.threshold; i < record.failures && delay < t.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+
+	wait := record.lastFailure.Add(delay).Sub(t.now())
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+func (t *memoryLoginThrottle) RecordFailure(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record := t.current(key)
+	if record == nil {
+		if len(t.records) >= maxTrackedClients {
+			t.sweep()
+		}
+		record = &failureRecord{}
+		t.records[key] = record
+	}
+
+	record.failures++
+	record.lastFailure = t.now()
+}
+
+func (t *memoryLoginThrottle) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.records, key)
+}
+
+// current returns the live record for key, dropping it if it has expired
+func (t *memoryLoginThrottle) current(key string) *failureRecord {
+	record, ok := t.records[key]
+	if !ok {
+		return nil
+	}
+	if t.now().Sub(record.lastFailure) > t.window {
+		delete(t.records, key)
+		return nil
+	}
+	return record
+}
+
+func (t *memoryLoginThrottle) sweep() {
+	now := t.now()
+	for key, record := range t.records {
+		if now.Sub(record.lastFailure) > t.window {
+			delete(t.records, key)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryJobQueue struct {
+	mu   sync.Mutex
+	jobs map[string]entities.JobRecord
+}
+
+// NewMemoryJobQueue creates an in-process JobQueue. Jobs are lost when the
+// process exits and are not shared with other instances, so it suits tests
+// and single-process development.
+func NewMemoryJobQueue() interfaces.JobQueue {
+	return &memoryJobQueue{jobs: make(map[string]entities.JobRecord)}
+}
+
+func (q *memoryJobQueue) Enqueue(ctx context.Context, job entities.JobRecord) (entities.JobRecord, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job.ID == "" {
+		job.ID = primitive.NewObjectID().Hex()
+	}
+	if existing, ok := q.jobs[job.ID]; ok {
+		return existing, false, nil
+	}
+	q.jobs[job.ID] = job
+	return job, true, nil
+}
+
+func (q *memoryJobQueue) Claim(ctx context.Context, now time.Time, lease time.Duration) (entities.JobRecord, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var next *entities.JobRecord
+	for _, job := range q.jobs {
+		due := (job.Status == entities.JobQueued && !job.RunAt.After(now)) ||
+			(job.Status == entities.JobRunning && !job.LockedUntil.After(now))
+		if due && (next == nil || job.RunAt.Before(next.RunAt)) {
+			next = &job
+		}
+	}
+	if next == nil {
+		return entities.JobRecord{}, false, nil
+	}
+
+	next.Status = entities.JobRunning
+	next.LockedUntil = now.Add(lease)
+	next.UpdatedAt = now
+	next.Attempts++
+	q.jobs[next.ID] = *next
+	return *next, true, nil
+}
+
+func (q *memoryJobQueue) Finish(ctx context.Context, job entities.JobRecord) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, ok := q.jobs[job.ID]
+	if !ok || stored.Status != entities.JobRunning || stored.Attempts != job.Attempts {
+		return false, nil
+	}
+
+	stored.Status = job.Status
+	stored.RunAt = job.RunAt
+	stored.LastError = job.LastError
+	stored.UpdatedAt = job.UpdatedAt
+	if !job.FinishedAt.IsZero() {
+		stored.FinishedAt = job.FinishedAt
+	}
+	if job.Status == entities.JobSucceeded {
+		stored.Payload = nil
+	}
+	stored.LockedUntil = time.Time{}
+	q.jobs[job.ID] = stored
+	return true, nil
+}
+
+func (q *memoryJobQueue) List(ctx context.Context, filter entities.JobFilter) ([]entities.JobRecord, int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var matching []entities.JobRecord
+	for _, job := range q.jobs {
+		if (filter.Status == "" || job.Status == filter.Status) && (filter.Type == "" || job.Type == filter.Type) {
+			matching = append(matching, job)
+		}
+	}
+	// Newest first, as in MongoDB
+	slices.SortFunc(matching, func(a, b entities.JobRecord) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	total := int64(len(matching))
+	start := min((filter.Page-1)*filter.Limit, len(matching))
+	end := min(start+filter.Limit, len(matching))
+	return matching[start:end], total, nil
+}
+
+func (q *memoryJobQueue) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var deleted int64
+	for id, job := range q.jobs {
+		if job.IsFinished() && job.FinishedAt.Before(before) {
+			delete(q.jobs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// oidcTimeout bounds each call to the identity provider
+const oidcTimeout = 10 * time.Second
+
+type oidcProvider struct {
+	oauth2Config oauth2.Config
+	verifier     *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider discovers the provider's endpoints and keys from its issuer URL
+func NewOIDCProvider(oidcConfig *config.OIDCConfig) (interfaces.IdentityProvider, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(ctx, oidcConfig.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC provider %s: %w", oidcConfig.IssuerURL, err)
+	}
+
+	return &oidcProvider{
+		oauth2Config: oauth2.Config{
+			ClientID:     oidcConfig.ClientID,
+			ClientSecret: oidcConfig.ClientSecret,
+			RedirectURL:  oidcConfig.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       oidcConfig.Scopes,
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: oidcConfig.ClientID}),
+	}, nil
+}
+
+func (p *oidcProvider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.oauth2Config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+func (p *oidcProvider) Exchange(code, codeVerifier, nonce string) (entities.ExternalIdentity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	token, err := p.oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return entities.ExternalIdentity{}, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return entities.ExternalIdentity{}, errors.New("token response has no id_token")
+	}
+
+	// Checks the signature, issuer, audience and expiry
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return entities.ExternalIdentity{}, err
+	}
+	if idToken.Nonce != nonce {
+		return entities.ExternalIdentity{}, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return entities.ExternalIdentity{}, err
+	}
+
+	return entities.ExternalIdentity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
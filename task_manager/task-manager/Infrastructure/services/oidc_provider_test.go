@@ -0,0 +1,172 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdentityProvider is a minimal OpenID Connect provider: discovery, keys, and
+// a token endpoint that checks the PKCE verifier of the code it issued
+type mockIdentityProvider struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	identity entities.ExternalIdentity
+
+	mu     sync.Mutex
+	grants map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newMockIdentityProvider(t *testing.T, clientID string, identity entities.ExternalIdentity) *mockIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdentityProvider{key: key, clientID: clientID, identity: identity, grants: map[string]mockGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/keys", idp.keys)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *mockIdentityProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                idp.URL,
+		"authorization_endpoint":                idp.URL + "/authorize",
+		"token_endpoint":                        idp.URL + "/token",
+		"jwks_uri":                              idp.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *mockIdentityProvider) keys(w http.ResponseWriter, r *http.Request) {
+	jwk, _ := toJSONWebKey(ringKey{SigningKey: entities.SigningKey{ID: "idp-key", Algorithm: "RS256"}, signer: idp.key})
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interfaces.JSONWebKey{jwk}})
+}
+
+// authorize stands in for the user signing in at the provider and returns the code
+func (idp *mockIdentityProvider) authorize(t *testing.T, authURL string) (string, string) {
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	code := "code-" + query.Get("state")
+	idp.mu.Lock()
+	idp.grants[code] = mockGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	idp.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func (idp *mockIdentityProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	idp.mu.Lock()
+	grant, ok := idp.grants[r.Form.Get("code")]
+	delete(idp.grants, r.Form.Get("code"))
+	idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            idp.URL,
+		"sub":            idp.identity.Subject,
+		"aud":            idp.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          grant.nonce,
+		"email":          idp.identity.Email,
+		"email_verified": idp.identity.EmailVerified,
+		"name":           idp.identity.Name,
+	})
+	idToken.Header["kid"] = "idp-key"
+	signed, _ := idToken.SignedString(idp.key)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	identity := entities.ExternalIdentity{Subject: "user-123", Email: "john@example.com", EmailVerified: true, Name: "John Doe"}
+	idp := newMockIdentityProvider(t, "task-manager", identity)
+
+	provider, err := NewOIDCProvider(&config.OIDCConfig{
+		IssuerURL:   idp.URL,
+		ClientID:    "task-manager",
+		RedirectURL: "http://localhost:8080/auth/oidc/callback",
+		Scopes:      []string{"openid", "email", "profile"},
+	})
+	require.NoError(t, err)
+
+	authURL := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	assert.Contains(t, authURL, idp.URL+"/authorize?")
+
+	code, state := idp.authorize(t, authURL)
+	assert.Equal(t, "state-1", state)
+
+	got, err := provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL, got.Issuer)
+	assert.Equal(t, "user-123", got.Subject)
+	assert.Equal(t, "john@example.com", got.Email)
+	assert.True(t, got.EmailVerified)
+	assert.Equal(t, "John Doe", got.Name)
+}
+
+func TestOIDCProvider_RejectsMismatches(t *testing.T) {
+	idp := newMockIdentityProvider(t, "task-manager", entities.ExternalIdentity{Subject: "user-123"})
+
+	provider, err := NewOIDCProvider(&config.OIDCConfig{IssuerURL: idp.URL, ClientID: "task-manager"})
+	require.NoError(t, err)
+
+	// The code is bound to the verifier it was requested with
+	code, _ := idp.authorize(t, provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier"))
+	_, err = provider.Exchange(code, "another-verifier-another-verifier-another", "nonce-1")
+	assert.Error(t, err)
+
+	// And the ID token to the nonce
+	code, _ = idp.authorize(t, provider.AuthCodeURL("state-2", "nonce-2", "verifier-verifier-verifier-verifier-verifier"))
+	_, err = provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-other")
+	assert.Error(t, err)
+
+	// ID tokens for another client are refused
+	other, err := NewOIDCProvider(&config.OIDCConfig{IssuerURL: idp.URL, ClientID: "someone-else"})
+	require.NoError(t, err)
+	code, _ = idp.authorize(t, other.AuthCodeURL("state-3", "nonce-3", "verifier-verifier-verifier-verifier-verifier"))
+	_, err = other.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-3")
+	assert.Error(t, err)
+}
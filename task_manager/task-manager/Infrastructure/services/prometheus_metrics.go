@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"task_manager/Domain/interfaces"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const metricsNamespace = "task_manager"
+
+// PrometheusMetrics exports the application's metrics in the Prometheus format.
+// Besides the interfaces.Metrics measurements it times MongoDB commands through
+// MongoMonitor and, once given the repository, reports task counts at scrape time.
+type PrometheusMetrics struct {
+	registry      *prometheus.Registry
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	logins        *prometheus.CounterVec
+	mongoDuration *prometheus.HistogramVec
+
+	// collections of in-flight MongoDB commands, by request ID
+	mongoCommands sync.Map
+}
+
+// NewPrometheusMetrics creates the metrics and registers them on a new registry
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to serve HTTP requests, by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "logins_total",
+			Help:      "Login attempts, by method and outcome.",
+		}, []string{"method", "outcome"}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "mongodb_command_duration_seconds",
+			Help:      "Time taken by MongoDB commands, by command, collection and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command", "collection", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.logins,
+		m.mongoDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+func (m *PrometheusMetrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) ObserveLogin(method, outcome string) {
+	m.logins.WithLabelValues(method, outcome).Inc()
+}
+
+// CollectTaskCounts reports the number of tasks by status, counted by taskRepo
+// whenever the metrics are scraped
+func (m *PrometheusMetrics) CollectTaskCounts(taskRepo interfaces.TaskRepository) {
+	m.registry.MustRegister(&taskCollector{taskRepo: taskRepo, desc: prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "tasks"),
+		"Tasks currently stored, by status.",
+		[]string{"status"}, nil,
+	)})
+}
+
+// Handler serves the metrics for scraping. A metric that cannot be collected,
+// such as task counts while MongoDB is down, is left out rather than failing
+// the whole scrape.
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry:      m.registry,
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// MongoMonitor returns a command monitor to install on the MongoDB client, which
+// times every command the repositories run
+func (m *PrometheusMetrics) MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			// Collection commands name their collection in the first field
+			var collection string
+			if element, err := evt.Command.IndexErr(0); err == nil {
+				collection, _ = element.Value().StringValueOK()
+			}
+			m.mongoCommands.Store(evt.RequestID, collection)
+		},
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			m.observeMongoCommand(evt.CommandFinishedEvent, "success")
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			m.observeMongoCommand(evt.CommandFinishedEvent, "error")
+		},
+	}
+}
+
+func (m *PrometheusMetrics) observeMongoCommand(evt event.CommandFinishedEvent, outcome string) {
+	collection, _ := m.mongoCommands.LoadAndDelete(evt.RequestID)
+	name, _ := collection.(string)
+	m.mongoDuration.WithLabelValues(evt.CommandName, name, outcome).Observe(evt.Duration.Seconds())
+}
+
+var _ interfaces.Metrics = (*PrometheusMetrics)(nil)
+
+// taskCountTimeout bounds the query run for each scrape
+const taskCountTimeout = 5 * time.Second
+
+// taskCollector reports task counts by status when scraped
+type taskCollector struct {
+	taskRepo interfaces.TaskRepository
+	desc     *prometheus.Desc
+}
+
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), taskCountTimeout)
+	defer cancel()
+
+	counts, err := c.taskRepo.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/Domain/interfaces"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// countingTaskRepository only answers CountByStatus
+type countingTaskRepository struct {
+	interfaces.TaskRepository
+	counts map[string]int64
+	err    error
+}
+
+func (r *countingTaskRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	return r.counts, r.err
+}
+
+func TestPrometheusMetrics_Observations(t *testing.T) {
+	m := NewPrometheusMetrics()
+
+	m.ObserveHTTPRequest("GET", "/tasks/:id", 200, 20*time.Millisecond)
+	m.ObserveHTTPRequest("GET", "/tasks/:id", 200, 30*time.Millisecond)
+	m.ObserveLogin(interfaces.LoginMethodPassword, interfaces.LoginOutcomeFailure)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/tasks/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.logins.WithLabelValues("password", "failure")))
+
+	// MongoDB commands are timed by collection
+	monitor := m.MongoMonitor()
+	command, _ := bson.Marshal(bson.D{{Key: "find", Value: "tasks"}})
+	monitor.Started(context.Background(), &event.CommandStartedEvent{Command: command, CommandName: "find", RequestID: 7})
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName: "find", RequestID: 7, Duration: 5 * time.Millisecond,
+	}})
+	assert.Equal(t, 1, testutil.CollectAndCount(m.mongoDuration, "task_manager_mongodb_command_duration_seconds"))
+}
+
+func TestPrometheusMetrics_Handler(t *testing.T) {
+	m := NewPrometheusMetrics()
+	m.CollectTaskCounts(&countingTaskRepository{counts: map[string]int64{"Pending": 3, "Completed": 1}})
+	m.ObserveLogin(interfaces.LoginMethodSSO, interfaces.LoginOutcomeSuccess)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `task_manager_tasks{status="Pending"} 3`)
+	assert.Contains(t, body, `task_manager_tasks{status="Completed"} 1`)
+	assert.Contains(t, body, `task_manager_logins_total{method="sso",outcome="success"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestPrometheusMetrics_TaskCountError(t *testing.T) {
+	m := NewPrometheusMetrics()
+	m.CollectTaskCounts(&countingTaskRepository{err: errors.New("connection refused")})
+
+	// The other metrics are still served, without stale or zero task counts
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "task_manager_tasks{")
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
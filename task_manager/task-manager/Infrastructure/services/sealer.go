@@ -0,0 +1,46 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+// sealer encrypts data the service stores but must not keep readable, such as
+// queued email, with AES-GCM under a key derived from the server secret. Each
+// use takes its own label, so one kind of sealed data cannot be opened as
+// another.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(secret []byte, label string) sealer {
+	// Neither call fails for a 32-byte key
+	block, err := aes.NewCipher(deriveKey(secret, label))
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return sealer{aead: aead}
+}
+
+// seal encrypts plaintext, returning the nonce followed by the ciphertext
+func (s sealer) seal(plaintext []byte) []byte {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(plaintext)+s.aead.Overhead())
+	rand.Read(nonce)
+	return s.aead.Seal(nonce, nonce, plaintext, nil)
+}
+
+// open decrypts data from seal, failing if it was changed or sealed under
+// another key
+func (s sealer) open(sealed []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, errors.New("sealed data is too short")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	return s.aead.Open(nil, nonce, ciphertext, nil)
+}
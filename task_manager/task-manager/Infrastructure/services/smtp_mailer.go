@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"task_manager/Domain/interfaces"
+	"task_manager/config"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a mailer that delivers through an SMTP relay
+func NewSMTPMailer(mailConfig *config.MailConfig) interfaces.Mailer {
+	var auth smtp.Auth
+	if mailConfig.Username != "" {
+		auth = smtp.PlainAuth("", mailConfig.Username, mailConfig.Password, mailConfig.Host)
+	}
+
+	return &smtpMailer{
+		addr: net.JoinHostPort(mailConfig.Host, mailConfig.Port),
+		auth: auth,
+		from: mailConfig.From,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("sending mail to %s: %w", to, err)
+	}
+	return nil
+}
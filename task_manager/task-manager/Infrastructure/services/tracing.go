@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"task_manager/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// SetupTracing installs the W3C trace context propagator and, when an exporter is
+// configured, a tracer provider exporting to it. Both are global, so it must run
+// before the instrumented clients and middleware are created. The returned
+// function flushes buffered spans and should be called on shutdown.
+//
+// The stdout exporter writes spans to out, for local use.
+func SetupTracing(ctx context.Context, tracingConfig *config.TracingConfig, out io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !tracingConfig.Enabled() {
+		// Spans are not recorded, but incoming trace context is still passed on
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch tracingConfig.Exporter {
+	case config.TraceExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+	case config.TraceExporterOTLP:
+		var options []otlptracehttp.Option
+		if tracingConfig.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(tracingConfig.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		err = fmt.Errorf("unsupported trace exporter %q", tracingConfig.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(tracingConfig.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(tracingConfig.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
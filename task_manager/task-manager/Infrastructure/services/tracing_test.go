@@ -0,0 +1,73 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"task_manager/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// restoreTracingGlobals puts back the global provider and propagator a test replaces
+func restoreTracingGlobals(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+}
+
+func TestSetupTracing_Stdout(t *testing.T) {
+	restoreTracingGlobals(t)
+
+	var out bytes.Buffer
+	shutdown, err := SetupTracing(context.Background(), &config.TracingConfig{
+		Exporter:    config.TraceExporterStdout,
+		ServiceName: "task-manager-test",
+		SampleRatio: 1,
+	}, &out)
+	require.NoError(t, err)
+
+	// An incoming traceparent is continued
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	_, span := otel.Tracer("test").Start(ctx, "test-span")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	span.End()
+
+	// Spans are flushed on shutdown
+	require.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, out.String(), `"Name":"test-span"`)
+	assert.Contains(t, out.String(), "task-manager-test")
+}
+
+func TestSetupTracing_Disabled(t *testing.T) {
+	restoreTracingGlobals(t)
+
+	shutdown, err := SetupTracing(context.Background(), &config.TracingConfig{Exporter: config.TraceExporterNone}, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// Nothing is recorded, but the caller's trace is still passed on
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	_, span := otel.Tracer("test").Start(ctx, "test-span")
+	assert.False(t, span.IsRecording())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+}
+
+func TestSetupTracing_UnknownExporter(t *testing.T) {
+	restoreTracingGlobals(t)
+
+	_, err := SetupTracing(context.Background(), &config.TracingConfig{Exporter: "zipkin"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+	"time"
+)
+
+// API key limits
+const (
+	DefaultAPIKeyTTL  = 90 * 24 * time.Hour
+	MaxAPIKeyTTL      = 365 * 24 * time.Hour
+	MaxAPIKeysPerUser = 25
+
+	// lastUsedResolution bounds how often a busy key's last-used time is written
+	lastUsedResolution = time.Minute
+)
+
+type APIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, userID string, request NewAPIKey) (entities.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]entities.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, id string) error
+	Authenticate(ctx context.Context, secret string) (entities.User, entities.APIKey, error)
+}
+
+// NewAPIKey describes a key a user asks for. A zero TTL means DefaultAPIKeyTTL.
+type NewAPIKey struct {
+	Name   string
+	Scopes []string
+	TTL    time.Duration
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo interfaces.APIKeyRepository
+	userRepo   interfaces.UserRepository
+	logger     *slog.Logger
+}
+
+func NewAPIKeyUsecase(apiKeyRepo interfaces.APIKeyRepository, userRepo interfaces.UserRepository, logger *slog.Logger) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		logger:     logger,
+	}
+}
+
+// CreateAPIKey mints a key for the user. The returned secret is not stored and
+// cannot be shown again.
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, userID string, request NewAPIKey) (_ entities.APIKey, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.CreateAPIKey")
+	defer endSpan(span, &err)
+
+	if err := utils.ValidateName(request.Name); err != nil {
+		return entities.APIKey{}, "", err
+	}
+
+	if len(request.Scopes) == 0 {
+		return entities.APIKey{}, "", errors.InvalidScopeError{}
+	}
+	for _, scope := range request.Scopes {
+		if !entities.IsValidScope(scope) {
+			return entities.APIKey{}, "", errors.InvalidScopeError{Scope: scope}
+		}
+	}
+
+	ttl := request.TTL
+	if ttl <= 0 {
+		ttl = DefaultAPIKeyTTL
+	}
+	if ttl > MaxAPIKeyTTL {
+		ttl = MaxAPIKeyTTL
+	}
+
+	count, err := u.apiKeyRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return entities.APIKey{}, "", err
+	}
+	if count >= MaxAPIKeysPerUser {
+		return entities.APIKey{}, "", errors.APIKeyLimitError{}
+	}
+
+	secret, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return entities.APIKey{}, "", err
+	}
+
+	now := time.Now()
+	key, err := u.apiKeyRepo.InsertOne(ctx, entities.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(request.Name),
+		Prefix:    prefix,
+		KeyHash:   utils.HashAPIKey(secret),
+		Scopes:    request.Scopes,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return entities.APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+func (u *apiKeyUsecase) ListAPIKeys(ctx context.Context, userID string) (_ []entities.APIKey, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.ListAPIKeys")
+	defer endSpan(span, &err)
+
+	return u.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, userID, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.RevokeAPIKey")
+	defer endSpan(span, &err)
+
+	return u.apiKeyRepo.DeleteOne(ctx, id, userID)
+}
+
+// Authenticate resolves a presented key to its owner. Every failure is reported
+// the same way so callers cannot tell unknown keys from expired ones.
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, secret string) (_ entities.User, _ entities.APIKey, err error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.Authenticate")
+	defer endSpan(span, &err)
+
+	if !utils.IsAPIKey(secret) {
+		return entities.User{}, entities.APIKey{}, errors.InvalidAPIKeyError{}
+	}
+
+	key, err := u.apiKeyRepo.GetByHash(ctx, utils.HashAPIKey(secret))
+	if err != nil {
+		return entities.User{}, entities.APIKey{}, errors.InvalidAPIKeyError{}
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		return entities.User{}, entities.APIKey{}, errors.InvalidAPIKeyError{}
+	}
+
+	user, err := u.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return entities.User{}, entities.APIKey{}, errors.InvalidAPIKeyError{}
+	}
+
+	if now.Sub(key.LastUsedAt) >= lastUsedResolution {
+		if err := u.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			u.logger.ErrorContext(ctx, "Recording API key use failed", "key", key.Prefix, "error", err)
+		}
+		key.LastUsedAt = now
+	}
+
+	user.Password = ""
+	return user, key, nil
+}
@@ -0,0 +1,163 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecase "task_manager/Usecases"
+	"task_manager/mocks"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	userID := "507f1f77bcf86cd799439011"
+	var stored entities.APIKey
+
+	mockAPIKeyRepo.EXPECT().CountByUser(gomock.Any(), userID).Return(int64(0), nil)
+	mockAPIKeyRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key entities.APIKey) (entities.APIKey, error) {
+		stored = key
+		key.ID = "key123"
+		return key, nil
+	})
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	key, secret, err := apiKeyUsecase.CreateAPIKey(context.Background(), userID, usecase.NewAPIKey{Name: "CI deploy", Scopes: []string{entities.ScopeTasksRead}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key123", key.ID)
+	assert.True(t, strings.HasPrefix(secret, utils.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(secret, key.Prefix))
+
+	// Only the hash of the secret is stored
+	assert.Equal(t, utils.HashAPIKey(secret), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, secret)
+	assert.WithinDuration(t, time.Now().Add(usecase.DefaultAPIKeyTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestCreateAPIKeyInvalidScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	_, _, err := apiKeyUsecase.CreateAPIKey(context.Background(), "507f1f77bcf86cd799439011", usecase.NewAPIKey{Name: "CI deploy", Scopes: []string{"tasks:everything"}})
+
+	assert.Error(t, err)
+	assert.Equal(t, errors.InvalidScopeError{Scope: "tasks:everything"}, err)
+}
+
+func TestCreateAPIKeyLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	userID := "507f1f77bcf86cd799439011"
+	mockAPIKeyRepo.EXPECT().CountByUser(gomock.Any(), userID).Return(int64(usecase.MaxAPIKeysPerUser), nil)
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	_, _, err := apiKeyUsecase.CreateAPIKey(context.Background(), userID, usecase.NewAPIKey{Name: "CI deploy", Scopes: []string{entities.ScopeTasksRead}})
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.APIKeyLimitError{}, err)
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	secret, prefix, _ := utils.GenerateAPIKey()
+	userID := "507f1f77bcf86cd799439011"
+	key := entities.APIKey{ID: "key123", UserID: userID, Prefix: prefix, KeyHash: utils.HashAPIKey(secret),
+		Scopes: []string{entities.ScopeTasksRead}, ExpiresAt: time.Now().Add(time.Hour)}
+	user := entities.User{ID: userID, Name: "Test User", Email: "test@example.com", Password: "hash", Role: "user"}
+
+	mockAPIKeyRepo.EXPECT().GetByHash(gomock.Any(), utils.HashAPIKey(secret)).Return(key, nil)
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
+	mockAPIKeyRepo.EXPECT().TouchLastUsed(gomock.Any(), "key123", gomock.Any()).Return(nil)
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	gotUser, gotKey, err := apiKeyUsecase.Authenticate(context.Background(), secret)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", gotUser.Email)
+	assert.Empty(t, gotUser.Password)
+	assert.Equal(t, "key123", gotKey.ID)
+	assert.False(t, gotKey.LastUsedAt.IsZero())
+}
+
+func TestAuthenticateAPIKeyRecentlyUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	secret, _, _ := utils.GenerateAPIKey()
+	userID := "507f1f77bcf86cd799439011"
+	key := entities.APIKey{ID: "key123", UserID: userID, KeyHash: utils.HashAPIKey(secret),
+		ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: time.Now().Add(-10 * time.Second)}
+
+	mockAPIKeyRepo.EXPECT().GetByHash(gomock.Any(), utils.HashAPIKey(secret)).Return(key, nil)
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), userID).Return(entities.User{ID: userID, Email: "test@example.com"}, nil)
+	// No TouchLastUsed: the stored time is recent enough
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	_, _, err := apiKeyUsecase.Authenticate(context.Background(), secret)
+
+	assert.NoError(t, err)
+}
+
+func TestAuthenticateAPIKeyExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	secret, _, _ := utils.GenerateAPIKey()
+	key := entities.APIKey{ID: "key123", UserID: "507f1f77bcf86cd799439011", KeyHash: utils.HashAPIKey(secret),
+		ExpiresAt: time.Now().Add(-time.Minute)}
+
+	mockAPIKeyRepo.EXPECT().GetByHash(gomock.Any(), utils.HashAPIKey(secret)).Return(key, nil)
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	_, _, err := apiKeyUsecase.Authenticate(context.Background(), secret)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.InvalidAPIKeyError{}, err)
+}
+
+func TestAuthenticateAPIKeyUnknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	mockAPIKeyRepo.EXPECT().GetByHash(gomock.Any(), gomock.Any()).Return(entities.APIKey{}, errors.APIKeyNotFoundError{})
+
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(mockAPIKeyRepo, mockUserRepo, testLogger)
+	_, _, err := apiKeyUsecase.Authenticate(context.Background(), utils.APIKeyPrefix+"unknown")
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.InvalidAPIKeyError{}, err)
+}
@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+)
+
+type JobUsecase interface {
+	ListJobs(ctx context.Context, filter entities.JobFilter) ([]entities.JobRecord, int64, error)
+}
+
+type jobUsecase struct {
+	jobQueue interfaces.JobQueue
+}
+
+func NewJobUsecase(jobQueue interfaces.JobQueue) JobUsecase {
+	return &jobUsecase{jobQueue: jobQueue}
+}
+
+// ListJobs lists the deployment's jobs. They are shared by every
+// organization, so only callers in the default organization may see them.
+func (u *jobUsecase) ListJobs(ctx context.Context, filter entities.JobFilter) (_ []entities.JobRecord, _ int64, err error) {
+	ctx, span := tracer.Start(ctx, "JobUsecase.ListJobs")
+	defer endSpan(span, &err)
+
+	if organizationID, ok := utils.OrganizationFromContext(ctx); ok && organizationID != entities.DefaultOrganizationID {
+		return nil, 0, errors.ForbiddenError{Message: "jobs are only visible to the default organization"}
+	}
+
+	return u.jobQueue.List(ctx, filter.WithDefaults())
+}
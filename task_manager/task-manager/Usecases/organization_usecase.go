@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+	"time"
+)
+
+type OrganizationUsecase interface {
+	CreateOrganization(ctx context.Context, name string, admin entities.User) (entities.Organization, entities.User, error)
+	GetOrganization(ctx context.Context, id string) (entities.Organization, error)
+}
+
+type organizationUsecase struct {
+	organizationRepo interfaces.OrganizationRepository
+	userUsecase      UserUsecase
+	logger           *slog.Logger
+}
+
+func NewOrganizationUsecase(organizationRepo interfaces.OrganizationRepository, userUsecase UserUsecase, logger *slog.Logger) OrganizationUsecase {
+	return &organizationUsecase{
+		organizationRepo: organizationRepo,
+		userUsecase:      userUsecase,
+		logger:           logger,
+	}
+}
+
+// CreateOrganization starts a new organization with admin as its first user,
+// registered as with Register. The organization is removed again if the
+// account cannot be created.
+func (u *organizationUsecase) CreateOrganization(ctx context.Context, name string, admin entities.User) (_ entities.Organization, _ entities.User, err error) {
+	ctx, span := tracer.Start(ctx, "OrganizationUsecase.CreateOrganization")
+	defer endSpan(span, &err)
+
+	if err := utils.ValidateOrganizationName(name); err != nil {
+		return entities.Organization{}, entities.User{}, err
+	}
+
+	organization, err := u.organizationRepo.InsertOne(ctx, entities.Organization{Name: name, CreatedAt: time.Now()})
+	if err != nil {
+		return entities.Organization{}, entities.User{}, err
+	}
+
+	admin.OrganizationID = organization.ID
+	createdAdmin, err := u.userUsecase.Register(ctx, admin)
+	if err != nil {
+		if deleteErr := u.organizationRepo.DeleteOne(ctx, organization.ID); deleteErr != nil {
+			u.logger.ErrorContext(ctx, "Removing organization failed", "organization_id", organization.ID, "error", deleteErr)
+		}
+		return entities.Organization{}, entities.User{}, err
+	}
+
+	return organization, createdAdmin, nil
+}
+
+// GetOrganization returns the organization with the given ID. The default
+// organization is found even though it is never stored.
+func (u *organizationUsecase) GetOrganization(ctx context.Context, id string) (_ entities.Organization, err error) {
+	ctx, span := tracer.Start(ctx, "OrganizationUsecase.GetOrganization")
+	defer endSpan(span, &err)
+
+	organization, err := u.organizationRepo.GetByID(ctx, id)
+	if _, ok := err.(errors.OrganizationNotFoundError); ok && id == entities.DefaultOrganizationID {
+		return entities.DefaultOrganization(), nil
+	}
+	return organization, err
+}
@@ -0,0 +1,108 @@
+package usecases_test
+
+import (
+	"context"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	usecase "task_manager/Usecases"
+	"task_manager/mocks"
+	"task_manager/utils"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOrganization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrganizationRepo := mocks.NewMockOrganizationRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	admin := entities.NewUser("Ada", "ada@example.com", "password123")
+
+	mockOrganizationRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, organization entities.Organization) (entities.Organization, error) {
+		assert.Equal(t, "Acme", organization.Name)
+		assert.False(t, organization.CreatedAt.IsZero())
+		organization.ID = "org1"
+		return organization, nil
+	})
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), admin.Email).Return(false, nil)
+	mockUserRepo.EXPECT().CountUsers(gomock.Any()).DoAndReturn(func(ctx context.Context) (int64, error) {
+		// Counted in the new organization, not the default one
+		organizationID, _ := utils.OrganizationFromContext(ctx)
+		assert.Equal(t, "org1", organizationID)
+		return 0, nil
+	})
+	mockUserRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user entities.User) (entities.User, error) {
+		// The first account of the organization administers it
+		assert.Equal(t, "org1", user.OrganizationID)
+		assert.Equal(t, "admin", user.Role)
+		return user, nil
+	})
+	mockTokenService.EXPECT().GenerateActionToken(admin.Email, interfaces.TokenPurposeVerifyEmail, gomock.Any(), gomock.Any()).Return("verify-token", nil)
+	mockMailer.EXPECT().Send(gomock.Any(), admin.Email, gomock.Any(), gomock.Any()).Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mockTokenService, mockMailer, mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	organizationUsecase := usecase.NewOrganizationUsecase(mockOrganizationRepo, userUsecase, testLogger)
+	organization, createdAdmin, err := organizationUsecase.CreateOrganization(context.Background(), "Acme", admin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "org1", organization.ID)
+	assert.Equal(t, "org1", createdAdmin.OrganizationID)
+	assert.Empty(t, createdAdmin.Password)
+}
+
+func TestCreateOrganizationEmailAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrganizationRepo := mocks.NewMockOrganizationRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	admin := entities.NewUser("Ada", "ada@example.com", "password123")
+
+	// The organization is removed again when its admin cannot be registered
+	mockOrganizationRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).Return(entities.Organization{ID: "org1", Name: "Acme"}, nil)
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), admin.Email).Return(true, nil)
+	mockOrganizationRepo.EXPECT().DeleteOne(gomock.Any(), "org1").Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	organizationUsecase := usecase.NewOrganizationUsecase(mockOrganizationRepo, userUsecase, testLogger)
+	_, _, err := organizationUsecase.CreateOrganization(context.Background(), "Acme", admin)
+
+	assert.Equal(t, errors.EmailAlreadyExistsError{}, err)
+}
+
+func TestCreateOrganizationInvalidName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	organizationUsecase := usecase.NewOrganizationUsecase(mocks.NewMockOrganizationRepository(ctrl), nil, testLogger)
+	_, _, err := organizationUsecase.CreateOrganization(context.Background(), "", entities.NewUser("Ada", "ada@example.com", "password123"))
+
+	assert.Error(t, err)
+}
+
+func TestGetOrganizationDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrganizationRepo := mocks.NewMockOrganizationRepository(ctrl)
+	mockOrganizationRepo.EXPECT().GetByID(gomock.Any(), entities.DefaultOrganizationID).Return(entities.Organization{}, errors.OrganizationNotFoundError{})
+	mockOrganizationRepo.EXPECT().GetByID(gomock.Any(), "org2").Return(entities.Organization{}, errors.OrganizationNotFoundError{})
+
+	organizationUsecase := usecase.NewOrganizationUsecase(mockOrganizationRepo, nil, testLogger)
+
+	// The default organization exists without being stored
+	organization, err := organizationUsecase.GetOrganization(context.Background(), entities.DefaultOrganizationID)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.DefaultOrganization(), organization)
+
+	_, err = organizationUsecase.GetOrganization(context.Background(), "org2")
+	assert.Equal(t, errors.OrganizationNotFoundError{}, err)
+}
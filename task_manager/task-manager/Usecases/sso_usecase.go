@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/utils"
+)
+
+type SSOUsecase interface {
+	BeginLogin() (SSOLoginRequest, error)
+	CompleteLogin(ctx context.Context, code string, request SSOLoginRequest) (LoginResult, error)
+}
+
+// SSOLoginRequest is an authorization request in flight. The caller keeps State,
+// Nonce and CodeVerifier with the user agent until the provider redirects back.
+type SSOLoginRequest struct {
+	AuthURL      string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+type ssoUsecase struct {
+	identityProvider interfaces.IdentityProvider
+	userUsecase      UserUsecase
+	logger           *slog.Logger
+}
+
+func NewSSOUsecase(identityProvider interfaces.IdentityProvider, userUsecase UserUsecase, logger *slog.Logger) SSOUsecase {
+	return &ssoUsecase{
+		identityProvider: identityProvider,
+		userUsecase:      userUsecase,
+		logger:           logger,
+	}
+}
+
+// BeginLogin starts the authorization code flow with fresh state, nonce and PKCE verifier
+func (u *ssoUsecase) BeginLogin() (SSOLoginRequest, error) {
+	var request SSOLoginRequest
+	for _, value := range []*string{&request.State, &request.Nonce, &request.CodeVerifier} {
+		token, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return SSOLoginRequest{}, err
+		}
+		*value = token
+	}
+
+	request.AuthURL = u.identityProvider.AuthCodeURL(request.State, request.Nonce, request.CodeVerifier)
+	return request, nil
+}
+
+// CompleteLogin redeems the code the provider returned for the request and signs
+// in the identity it asserts
+func (u *ssoUsecase) CompleteLogin(ctx context.Context, code string, request SSOLoginRequest) (_ LoginResult, err error) {
+	ctx, span := tracer.Start(ctx, "SSOUsecase.CompleteLogin")
+	defer endSpan(span, &err)
+
+	if code == "" {
+		return LoginResult{}, errors.SSOLoginFailedError{}
+	}
+
+	identity, err := u.identityProvider.Exchange(code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		u.logger.WarnContext(ctx, "SSO code exchange failed", "error", err)
+		return LoginResult{}, errors.SSOLoginFailedError{}
+	}
+
+	return u.userUsecase.LoginWithIdentity(ctx, identity)
+}
@@ -0,0 +1,196 @@
+package usecases_test
+
+import (
+	"context"
+	goerrors "errors"
+	"task_manager/Domain/entities"
+	"task_manager/Domain/errors"
+	usecase "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func newSSOTestUsecase(ctrl *gomock.Controller, settings usecase.AuthSettings) (usecase.UserUsecase, *mocks.MockUserRepository, *mocks.MockTokenService) {
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mockTokenService, mocks.NewMockMailer(ctrl), mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, settings, testLogger)
+	return userUsecase, mockUserRepo, mockTokenService
+}
+
+func TestLoginWithIdentityLinkedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, mockTokenService := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123", Email: "new-address@example.com"}
+	user := entities.User{Email: "test@example.com", Role: "user", OIDCIssuer: testIssuer, OIDCSubject: "user-123", OrganizationID: "org1"}
+
+	// The link holds even though the email at the provider changed
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(user, nil)
+	mockTokenService.EXPECT().GenerateToken(user.Email, "user", "org1").Return("jwt-token", nil)
+
+	result, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", result.Token)
+}
+
+func TestLoginWithIdentityLinksVerifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, mockTokenService := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123", Email: "Test@Example.com", EmailVerified: true}
+	user := entities.User{Email: "test@example.com", Role: "admin"}
+
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(user, nil)
+	mockUserRepo.EXPECT().LinkIdentity(gomock.Any(), user.Email, testIssuer, "user-123").Return(true, nil)
+	mockTokenService.EXPECT().GenerateToken(user.Email, "admin", "").Return("jwt-token", nil)
+
+	result, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", result.Token)
+}
+
+func TestLoginWithIdentityRefusesUnverifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, _ := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123", Email: "test@example.com"}
+
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(entities.User{Email: "test@example.com"}, nil)
+
+	_, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+
+	assert.IsType(t, errors.SSOAccountNotAllowedError{}, err)
+}
+
+func TestLoginWithIdentityLinkRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, _ := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123", Email: "test@example.com", EmailVerified: true}
+
+	// Another sign-in linked a different identity after the account was read
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "test@example.com").Return(entities.User{Email: "test@example.com"}, nil)
+	mockUserRepo.EXPECT().LinkIdentity(gomock.Any(), "test@example.com", testIssuer, "user-123").Return(false, nil)
+
+	_, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+
+	assert.IsType(t, errors.SSOAccountNotAllowedError{}, err)
+}
+
+func TestLoginWithIdentityProvisionsUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, mockTokenService := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123", Email: "jane@example.com", EmailVerified: true, Name: "Jane Doe"}
+
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user entities.User) (entities.User, error) {
+		assert.Equal(t, "Jane Doe", user.Name)
+		assert.Equal(t, "user", user.Role)
+		assert.True(t, user.EmailVerified)
+		assert.Equal(t, "user-123", user.OIDCSubject)
+		assert.NotEmpty(t, user.Password)
+		assert.Equal(t, entities.DefaultOrganizationID, user.OrganizationID)
+		return user, nil
+	})
+	mockTokenService.EXPECT().GenerateToken("jane@example.com", "user", entities.DefaultOrganizationID).Return("jwt-token", nil)
+
+	result, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", result.Token)
+}
+
+func TestLoginWithIdentityProvisioningRestricted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	settings := usecase.DefaultAuthSettings()
+	settings.SSOAutoProvision = false
+	settings.SSOAllowedDomains = []string{"example.com"}
+	userUsecase, mockUserRepo, _ := newSSOTestUsecase(ctrl, settings)
+
+	// Outside the allowed domains
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-1", Email: "jane@elsewhere.com", EmailVerified: true}
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-1").Return(entities.User{}, errors.UserNotFoundError{})
+
+	_, err := userUsecase.LoginWithIdentity(context.Background(), identity)
+	assert.IsType(t, errors.SSOAccountNotAllowedError{}, err)
+
+	// No matching account and provisioning is off
+	identity = entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-2", Email: "jane@example.com", EmailVerified: true}
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-2").Return(entities.User{}, errors.UserNotFoundError{})
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(entities.User{}, errors.UserNotFoundError{})
+
+	_, err = userUsecase.LoginWithIdentity(context.Background(), identity)
+	assert.IsType(t, errors.SSOAccountNotAllowedError{}, err)
+}
+
+func TestLoginWithIdentityDisabledAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, _ := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+
+	user := entities.User{Email: "test@example.com", Disabled: true, OIDCIssuer: testIssuer, OIDCSubject: "user-123"}
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(user, nil)
+
+	_, err := userUsecase.LoginWithIdentity(context.Background(), entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123"})
+
+	assert.IsType(t, errors.AccountDisabledError{}, err)
+}
+
+func TestSSOLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userUsecase, mockUserRepo, mockTokenService := newSSOTestUsecase(ctrl, usecase.DefaultAuthSettings())
+	mockProvider := mocks.NewMockIdentityProvider(ctrl)
+	ssoUsecase := usecase.NewSSOUsecase(mockProvider, userUsecase, testLogger)
+
+	mockProvider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).Return(testIssuer + "/authorize")
+
+	request, err := ssoUsecase.BeginLogin()
+	assert.NoError(t, err)
+	assert.Equal(t, testIssuer+"/authorize", request.AuthURL)
+	assert.NotEmpty(t, request.State)
+	assert.NotEqual(t, request.State, request.Nonce)
+	assert.GreaterOrEqual(t, len(request.CodeVerifier), 43) // RFC 7636 minimum
+
+	identity := entities.ExternalIdentity{Issuer: testIssuer, Subject: "user-123"}
+	mockProvider.EXPECT().Exchange("auth-code", request.CodeVerifier, request.Nonce).Return(identity, nil)
+	mockUserRepo.EXPECT().GetUserByExternalID(gomock.Any(), testIssuer, "user-123").Return(entities.User{Email: "test@example.com", Role: "user"}, nil)
+	mockTokenService.EXPECT().GenerateToken("test@example.com", "user", "").Return("jwt-token", nil)
+
+	result, err := ssoUsecase.CompleteLogin(context.Background(), "auth-code", request)
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", result.Token)
+
+	// A code the provider will not redeem
+	mockProvider.EXPECT().Exchange("bad-code", request.CodeVerifier, request.Nonce).Return(entities.ExternalIdentity{}, goerrors.New("invalid_grant"))
+
+	_, err = ssoUsecase.CompleteLogin(context.Background(), "bad-code", request)
+	assert.IsType(t, errors.SSOLoginFailedError{}, err)
+}
@@ -1,16 +1,18 @@
 package usecases
 
 import (
+	"context"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/interfaces"
+	"time"
 )
 
 type TaskUsecase interface {
-	GetTasks() []entities.Task
-	GetTaskByID(id string) (entities.Task, error)
-	AddTask(task entities.Task) (entities.Task, error)
-	UpdateTask(id string, updatedTask entities.Task) (entities.Task, error)
-	DeleteTask(id string) error
+	GetTasks(ctx context.Context) ([]entities.Task, error)
+	GetTaskByID(ctx context.Context, id string) (entities.Task, error)
+	AddTask(ctx context.Context, task entities.Task) (entities.Task, error)
+	UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error)
+	DeleteTask(ctx context.Context, id string) error
 }
 
 type taskUsecase struct {
@@ -21,22 +23,39 @@ func NewTaskUsecase(taskRepo interfaces.TaskRepository) TaskUsecase {
 	return &taskUsecase{taskRepo: taskRepo}
 }
 
-func (u *taskUsecase) GetTasks() []entities.Task {
-	return u.taskRepo.GetTasks()
+func (u *taskUsecase) GetTasks(ctx context.Context) (_ []entities.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskUsecase.GetTasks")
+	defer endSpan(span, &err)
+
+	return u.taskRepo.GetTasks(ctx)
 }
 
-func (u *taskUsecase) GetTaskByID(id string) (entities.Task, error) {
-	return u.taskRepo.GetTaskByID(id)
+func (u *taskUsecase) GetTaskByID(ctx context.Context, id string) (_ entities.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskUsecase.GetTaskByID")
+	defer endSpan(span, &err)
+
+	return u.taskRepo.GetTaskByID(ctx, id)
 }
 
-func (u *taskUsecase) AddTask(task entities.Task) (entities.Task, error) {
-	return u.taskRepo.AddTask(task)
+func (u *taskUsecase) AddTask(ctx context.Context, task entities.Task) (_ entities.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskUsecase.AddTask")
+	defer endSpan(span, &err)
+
+	task.UpdatedAt = time.Now()
+	return u.taskRepo.AddTask(ctx, task)
 }
 
-func (u *taskUsecase) UpdateTask(id string, updatedTask entities.Task) (entities.Task, error) {
-	return u.taskRepo.UpdateTask(id, updatedTask)
+func (u *taskUsecase) UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (_ entities.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskUsecase.UpdateTask")
+	defer endSpan(span, &err)
+
+	updatedTask.UpdatedAt = time.Now()
+	return u.taskRepo.UpdateTask(ctx, id, updatedTask)
 }
 
-func (u *taskUsecase) DeleteTask(id string) error {
-	return u.taskRepo.DeleteTask(id)
-} 
\ No newline at end of file
+func (u *taskUsecase) DeleteTask(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "TaskUsecase.DeleteTask")
+	defer endSpan(span, &err)
+
+	return u.taskRepo.DeleteTask(ctx, id)
+}
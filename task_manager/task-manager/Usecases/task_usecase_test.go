@@ -1,12 +1,13 @@
 package usecases_test
 
 import (
-	"testing"
-	"time"
+	"context"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
-	"task_manager/mocks"
 	usecase "task_manager/Usecases"
+	"task_manager/mocks"
+	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -28,10 +29,10 @@ func TestGetTaskByID(t *testing.T) {
 		DueDate:     time.Now(),
 	}
 
-	mockTaskRepo.EXPECT().GetTaskByID(taskID).Return(expected, nil)
+	mockTaskRepo.EXPECT().GetTaskByID(gomock.Any(), taskID).Return(expected, nil)
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	task, err := taskUsecase.GetTaskByID(taskID)
+	task, err := taskUsecase.GetTaskByID(context.Background(), taskID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, task)
@@ -44,10 +45,10 @@ func TestGetTaskByIDNotFound(t *testing.T) {
 	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
 
 	taskID := "507f1f77bcf86cd799439011"
-	mockTaskRepo.EXPECT().GetTaskByID(taskID).Return(entities.Task{}, errors.TaskNotFoundError{})
+	mockTaskRepo.EXPECT().GetTaskByID(gomock.Any(), taskID).Return(entities.Task{}, errors.TaskNotFoundError{})
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	task, err := taskUsecase.GetTaskByID(taskID)
+	task, err := taskUsecase.GetTaskByID(context.Background(), taskID)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.TaskNotFoundError{}, err)
@@ -61,10 +62,10 @@ func TestAddTask(t *testing.T) {
 	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
 
 	task := entities.NewTask("Test Task", "Test Description", time.Now())
-	mockTaskRepo.EXPECT().AddTask(gomock.Any()).Return(task, nil)
-	
+	mockTaskRepo.EXPECT().AddTask(gomock.Any(), gomock.Any()).Return(task, nil)
+
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	result, err := taskUsecase.AddTask(task)
+	result, err := taskUsecase.AddTask(context.Background(), task)
 
 	assert.NoError(t, err)
 	assert.Equal(t, task.Title, result.Title)
@@ -96,11 +97,12 @@ func TestGetTasks(t *testing.T) {
 		},
 	}
 
-	mockTaskRepo.EXPECT().GetTasks().Return(expected)
+	mockTaskRepo.EXPECT().GetTasks(gomock.Any()).Return(expected, nil)
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	tasks := taskUsecase.GetTasks()
+	tasks, err := taskUsecase.GetTasks(context.Background())
 
+	assert.NoError(t, err)
 	assert.Equal(t, expected, tasks)
 }
 
@@ -118,10 +120,16 @@ func TestUpdateTask(t *testing.T) {
 		Status:      "In Progress",
 		DueDate:     time.Now(),
 	}
-	mockTaskRepo.EXPECT().UpdateTask(taskID, task).Return(task, nil)
+	mockTaskRepo.EXPECT().UpdateTask(gomock.Any(), taskID, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, updated entities.Task) (entities.Task, error) {
+		// The change is timestamped
+		assert.WithinDuration(t, time.Now(), updated.UpdatedAt, time.Minute)
+		updated.UpdatedAt = time.Time{}
+		assert.Equal(t, task, updated)
+		return task, nil
+	})
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	result, err := taskUsecase.UpdateTask(taskID, task)
+	result, err := taskUsecase.UpdateTask(context.Background(), taskID, task)
 
 	assert.NoError(t, err)
 	assert.Equal(t, task, result)
@@ -141,10 +149,10 @@ func TestUpdateTaskNotFound(t *testing.T) {
 		Status:      "In Progress",
 		DueDate:     time.Now(),
 	}
-	mockTaskRepo.EXPECT().UpdateTask(taskID, task).Return(entities.Task{}, errors.TaskNotFoundError{})
+	mockTaskRepo.EXPECT().UpdateTask(gomock.Any(), taskID, gomock.Any()).Return(entities.Task{}, errors.TaskNotFoundError{})
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	result, err := taskUsecase.UpdateTask(taskID, task)
+	result, err := taskUsecase.UpdateTask(context.Background(), taskID, task)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.TaskNotFoundError{}, err)
@@ -155,13 +163,13 @@ func TestDeleteTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)				
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
 
 	taskID := "507f1f77bcf86cd799439011"
-	mockTaskRepo.EXPECT().DeleteTask(taskID).Return(nil)
+	mockTaskRepo.EXPECT().DeleteTask(gomock.Any(), taskID).Return(nil)
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	err := taskUsecase.DeleteTask(taskID)
+	err := taskUsecase.DeleteTask(context.Background(), taskID)
 
 	assert.NoError(t, err)
 }
@@ -170,14 +178,14 @@ func TestDeleteTaskNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)				
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
 
 	taskID := "507f1f77bcf86cd799439011"
-	mockTaskRepo.EXPECT().DeleteTask(taskID).Return(errors.TaskNotFoundError{})
+	mockTaskRepo.EXPECT().DeleteTask(gomock.Any(), taskID).Return(errors.TaskNotFoundError{})
 
 	taskUsecase := usecase.NewTaskUsecase(mockTaskRepo)
-	err := taskUsecase.DeleteTask(taskID)
+	err := taskUsecase.DeleteTask(context.Background(), taskID)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.TaskNotFoundError{}, err)
-} 
\ No newline at end of file
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+	"task_manager/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates a span for each use case operation, under the request's span
+var tracer = otel.Tracer("task_manager/Usecases")
+
+// endSpan ends a use case span, marking it failed with the error the operation
+// returns, if any. It is deferred with a pointer to the named error result.
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// hashPassword runs bcrypt in a span of its own, as it is deliberately slow and
+// often dominates the request
+func hashPassword(ctx context.Context, password string) (_ string, err error) {
+	_, span := tracer.Start(ctx, "bcrypt.Hash")
+	defer endSpan(span, &err)
+
+	return utils.HashPassword(password)
+}
+
+// checkPassword compares a password with its bcrypt hash in a span of its own
+func checkPassword(ctx context.Context, password, hash string) bool {
+	_, span := tracer.Start(ctx, "bcrypt.Compare")
+	defer span.End()
+
+	return utils.CheckPassword(password, hash)
+}
@@ -1,33 +1,172 @@
 package usecases
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"strings"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
 	"task_manager/Domain/interfaces"
 	"task_manager/utils"
+	"time"
 )
 
 type UserUsecase interface {
-	Register(user entities.User) (entities.User, error)
-	Login(email, password string) (string, error)
-	PromoteToAdmin(email string) error
-	GetUserByEmail(email string) (entities.User, error)
+	Register(ctx context.Context, user entities.User) (entities.User, error)
+	Login(ctx context.Context, email, password, clientIP string) (LoginResult, error)
+	VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (string, error)
+	LoginWithIdentity(ctx context.Context, identity entities.ExternalIdentity) (LoginResult, error)
+	PromoteToAdmin(ctx context.Context, email string) error
+	GetUserByEmail(ctx context.Context, email string) (entities.User, error)
+	UpdateProfile(ctx context.Context, email string, update ProfileUpdate) (entities.User, string, error)
+	ChangePassword(ctx context.Context, email, currentPassword, newPassword string) error
+	DeleteAccount(ctx context.Context, email, password string) error
+	ListUsers(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error)
+	InviteUser(ctx context.Context, invite Invitation) (entities.User, error)
+	GetUserByID(ctx context.Context, id string) (entities.User, error)
+	DemoteToUser(ctx context.Context, id string) error
+	SetUserDisabled(ctx context.Context, id string, disabled bool) error
+	DeleteUser(ctx context.Context, id string) error
+	UnlockUser(ctx context.Context, id string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	EnrollMFA(ctx context.Context, email string) (MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, email, code string) ([]string, error)
+	DisableMFA(ctx context.Context, email, password string) error
+}
+
+// MFARecoveryCodeCount is how many recovery codes are issued when two-factor
+// authentication is enabled
+const MFARecoveryCodeCount = 10
+
+// LoginResult is the outcome of a successful password check. Accounts with
+// two-factor authentication get a short-lived MFA token to exchange, together
+// with a code, for the access token.
+type LoginResult struct {
+	Token       string
+	MFARequired bool
+	MFAToken    string
+}
+
+// MFAEnrollment holds what an authenticator app needs to add the account
+type MFAEnrollment struct {
+	Secret string
+	URI    string // otpauth:// URI, usually shown as a QR code
+}
+
+// AuthSettings holds the account security policy applied by the user use case
+type AuthSettings struct {
+	RequireEmailVerification bool
+	VerificationTokenTTL     time.Duration
+	PasswordResetTokenTTL    time.Duration
+	AppBaseURL               string // used to build links in account emails
+
+	// PasswordResetURL is the client's page for choosing a new password; reset
+	// emails link to it with the token added as ?token=. The API has no such
+	// page, so without one the email holds the token to send to POST
+	// /api/v1/reset-password instead.
+	PasswordResetURL string
+
+	// Accounts lock for LockoutDuration after MaxFailedLogins consecutive failures;
+	// each further failure doubles the lock, up to MaxLockoutDuration
+	MaxFailedLogins    int
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+
+	MFAIssuer       string // shown next to the account in authenticator apps
+	MFAChallengeTTL time.Duration
+
+	// Single sign-on. SSOAutoProvision creates accounts for unknown identities;
+	// SSOAllowedDomains, when set, limits SSO to those email domains.
+	SSOAutoProvision  bool
+	SSOAllowedDomains []string
+}
+
+// DefaultAuthSettings returns the settings used when nothing else is configured
+func DefaultAuthSettings() AuthSettings {
+	return AuthSettings{
+		VerificationTokenTTL:  48 * time.Hour,
+		PasswordResetTokenTTL: time.Hour,
+		AppBaseURL:            "http://localhost:8080",
+		MaxFailedLogins:       5,
+		LockoutDuration:       time.Minute,
+		MaxLockoutDuration:    time.Hour,
+		MFAIssuer:             "Task Manager",
+		MFAChallengeTTL:       5 * time.Minute,
+		SSOAutoProvision:      true,
+	}
+}
+
+// lockoutFor returns how long an account stays locked after the given number of
+// consecutive failures, or zero if it should not be locked yet
+func (s AuthSettings) lockoutFor(failures int) time.Duration {
+	if s.MaxFailedLogins <= 0 || failures < s.MaxFailedLogins {
+		return 0
+	}
+
+	lockout := s.LockoutDuration
+	for i := s.MaxFailedLogins; i < failures && lockout < s.MaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+	if lockout > s.MaxLockoutDuration {
+		lockout = s.MaxLockoutDuration
+	}
+	return lockout
+}
+
+// ProfileUpdate holds the self-service changes a user may make to their account.
+// Nil fields are left untouched. Changing the email requires CurrentPassword.
+type ProfileUpdate struct {
+	Name            *string
+	Email           *string
+	CurrentPassword string
+}
+
+// Invitation describes an account an admin adds to their organization. Role is
+// "user" unless set to "admin".
+type Invitation struct {
+	Name  string
+	Email string
+	Role  string
 }
 
 type userUsecase struct {
 	userRepo      interfaces.UserRepository
+	taskRepo      interfaces.TaskRepository
+	apiKeyRepo    interfaces.APIKeyRepository
 	tokenService  interfaces.TokenService
+	mailer        interfaces.Mailer
+	loginThrottle interfaces.LoginThrottle
+	metrics       interfaces.Metrics
+	settings      AuthSettings
+	logger        *slog.Logger
 }
 
-func NewUserUsecase(userRepo interfaces.UserRepository, tokenService interfaces.TokenService) UserUsecase {
+func NewUserUsecase(userRepo interfaces.UserRepository, taskRepo interfaces.TaskRepository, apiKeyRepo interfaces.APIKeyRepository, tokenService interfaces.TokenService, mailer interfaces.Mailer, loginThrottle interfaces.LoginThrottle, metrics interfaces.Metrics, settings AuthSettings, logger *slog.Logger) UserUsecase {
 	return &userUsecase{
-		userRepo:     userRepo,
-		tokenService: tokenService,
+		userRepo:      userRepo,
+		taskRepo:      taskRepo,
+		apiKeyRepo:    apiKeyRepo,
+		tokenService:  tokenService,
+		mailer:        mailer,
+		loginThrottle: loginThrottle,
+		metrics:       metrics,
+		settings:      settings,
+		logger:        logger,
 	}
 }
 
-func (u *userUsecase) Register(user entities.User) (entities.User, error) {
+func (u *userUsecase) Register(ctx context.Context, user entities.User) (_ entities.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.Register")
+	defer endSpan(span, &err)
+
 	// Validate input
 	if err := utils.ValidateEmail(user.Email); err != nil {
 		return entities.User{}, err
@@ -40,57 +179,328 @@ func (u *userUsecase) Register(user entities.User) (entities.User, error) {
 	}
 
 	user.Email = strings.ToLower(user.Email)
+	if user.OrganizationID == "" {
+		user.OrganizationID = entities.DefaultOrganizationID
+	}
+	ctx = utils.ContextWithOrganization(ctx, user.OrganizationID)
 
-	count, err := u.userRepo.CountDocuments(user.Email)
+	inUse, err := u.userRepo.EmailInUse(ctx, user.Email)
 	if err != nil {
 		return entities.User{}, err
 	}
-
-	if count > 0 {
+	if inUse {
 		return entities.User{}, errors.EmailAlreadyExistsError{}
 	}
 
-	// First user becomes admin
-	if count == 0 {
+	// The first account of an organization administers it; everyone who
+	// registers after that is a plain user
+	members, err := u.userRepo.CountUsers(ctx)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if members == 0 {
 		user.SetRole("admin")
 	} else {
 		user.SetRole("user")
 	}
 
 	// Hash password using utils
-	hashedPassword, err := utils.HashPassword(user.Password)
+	hashedPassword, err := hashPassword(ctx, user.Password)
 	if err != nil {
 		return entities.User{}, err
 	}
 	user.Password = hashedPassword
 
-	createdUser, err := u.userRepo.InsertOne(user)
+	createdUser, err := u.userRepo.InsertOne(ctx, user)
 	if err != nil {
 		return entities.User{}, err
 	}
 
+	// The account exists even if the mail fails; the user can ask for a new link
+	if err := u.sendVerificationEmail(ctx, createdUser); err != nil {
+		u.logger.ErrorContext(ctx, "Sending verification email failed", "email", createdUser.Email, "error", err)
+	}
+
 	// Never return password
 	createdUser.Password = ""
 	return createdUser, nil
 }
 
-func (u *userUsecase) Login(email, password string) (string, error) {
+// Login authenticates a user. Failures are counted both per client and per
+// account, and throttled or locked attempts are refused before the comparatively
+// expensive bcrypt check runs.
+func (u *userUsecase) Login(ctx context.Context, email, password, clientIP string) (_ LoginResult, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.Login")
+	defer endSpan(span, &err)
+
+	result, err := u.login(ctx, email, password, clientIP)
+	u.metrics.ObserveLogin(interfaces.LoginMethodPassword, loginOutcome(result.MFARequired, err))
+	return result, err
+}
+
+func (u *userUsecase) login(ctx context.Context, email, password, clientIP string) (LoginResult, error) {
+	if wait := u.loginThrottle.RetryAfter(clientIP); wait > 0 {
+		return LoginResult{}, errors.TooManyAttemptsError{RetryAfter: wait}
+	}
+
 	// Validate email
 	if err := utils.ValidateEmail(email); err != nil {
-		return "", errors.InvalidCredentialsError{}
+		u.loginThrottle.RecordFailure(clientIP)
+		return LoginResult{}, errors.InvalidCredentialsError{}
 	}
 
-	user, err := u.userRepo.GetUserByEmail(email)
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", errors.InvalidCredentialsError{}
+		u.loginThrottle.RecordFailure(clientIP)
+		return LoginResult{}, errors.InvalidCredentialsError{}
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return LoginResult{}, errors.AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
 	}
 
 	// Check password using utils
-	if !utils.CheckPassword(password, user.Password) {
-		return "", errors.InvalidCredentialsError{}
+	if !checkPassword(ctx, password, user.Password) {
+		u.loginThrottle.RecordFailure(clientIP)
+		u.recordAccountFailure(ctx, user.Email, now)
+		return LoginResult{}, errors.InvalidCredentialsError{}
+	}
+
+	if !user.IsActive() {
+		return LoginResult{}, errors.AccountDisabledError{}
+	}
+
+	if u.settings.RequireEmailVerification && !user.EmailVerified {
+		return LoginResult{}, errors.EmailNotVerifiedError{}
+	}
+
+	return u.startSession(ctx, user)
+}
+
+// VerifyMFALogin exchanges the MFA token from Login and a TOTP or recovery code
+// for an access token. Wrong codes count as failed logins.
+func (u *userUsecase) VerifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.VerifyMFALogin")
+	defer endSpan(span, &err)
+
+	token, err := u.verifyMFALogin(ctx, mfaToken, code, clientIP)
+	u.metrics.ObserveLogin(interfaces.LoginMethodMFA, loginOutcome(false, err))
+	return token, err
+}
+
+func (u *userUsecase) verifyMFALogin(ctx context.Context, mfaToken, code, clientIP string) (string, error) {
+	if wait := u.loginThrottle.RetryAfter(clientIP); wait > 0 {
+		return "", errors.TooManyAttemptsError{RetryAfter: wait}
+	}
+
+	user, err := u.consumeActionToken(ctx, mfaToken, interfaces.TokenPurposeMFALogin)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return "", errors.AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	if !user.IsActive() {
+		return "", errors.AccountDisabledError{}
+	}
+
+	// Two-factor authentication was turned off since the password was checked
+	if !user.MFAEnabled {
+		return "", errors.InvalidActionTokenError{}
 	}
 
-	token, err := u.tokenService.GenerateToken(user.Email, user.Role)
+	ok, err := u.checkMFACode(ctx, user, code, now)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		u.loginThrottle.RecordFailure(clientIP)
+		u.recordAccountFailure(ctx, user.Email, now)
+		return "", errors.InvalidMFACodeError{}
+	}
+
+	return u.completeLogin(ctx, user)
+}
+
+// LoginWithIdentity signs in a user authenticated by the external identity
+// provider. The identity is matched on its issuer and subject, then on a verified
+// email, which links the existing account; otherwise a new account is provisioned
+// if allowed. Accounts with two-factor authentication still need their code.
+func (u *userUsecase) LoginWithIdentity(ctx context.Context, identity entities.ExternalIdentity) (_ LoginResult, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.LoginWithIdentity")
+	defer endSpan(span, &err)
+
+	result, err := u.loginWithIdentity(ctx, identity)
+	u.metrics.ObserveLogin(interfaces.LoginMethodSSO, loginOutcome(result.MFARequired, err))
+	return result, err
+}
+
+func (u *userUsecase) loginWithIdentity(ctx context.Context, identity entities.ExternalIdentity) (LoginResult, error) {
+	if identity.Issuer == "" || identity.Subject == "" {
+		return LoginResult{}, errors.SSOLoginFailedError{}
+	}
+
+	user, err := u.userRepo.GetUserByExternalID(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		if _, ok := err.(errors.UserNotFoundError); !ok {
+			return LoginResult{}, err
+		}
+		user, err = u.linkIdentity(ctx, identity)
+		if err != nil {
+			return LoginResult{}, err
+		}
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return LoginResult{}, errors.AccountLockedError{RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	if !user.IsActive() {
+		return LoginResult{}, errors.AccountDisabledError{}
+	}
+
+	if u.settings.RequireEmailVerification && !user.EmailVerified {
+		return LoginResult{}, errors.EmailNotVerifiedError{}
+	}
+
+	return u.startSession(ctx, user)
+}
+
+// linkIdentity attaches a new external identity to the account with its email,
+// or provisions one. Only an email the provider has verified is trusted to link,
+// since otherwise anyone able to register it at the provider could take over the account.
+func (u *userUsecase) linkIdentity(ctx context.Context, identity entities.ExternalIdentity) (entities.User, error) {
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	if err := utils.ValidateEmail(email); err != nil {
+		return entities.User{}, errors.SSOLoginFailedError{}
+	}
+	if !u.ssoDomainAllowed(email) {
+		return entities.User{}, errors.SSOAccountNotAllowedError{}
+	}
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	switch err.(type) {
+	case nil:
+		if !identity.EmailVerified || user.OIDCSubject != "" {
+			return entities.User{}, errors.SSOAccountNotAllowedError{}
+		}
+		// Another sign-in may have linked an identity meanwhile
+		linked, err := u.userRepo.LinkIdentity(ctx, user.Email, identity.Issuer, identity.Subject)
+		if err != nil {
+			return entities.User{}, err
+		}
+		if !linked {
+			return entities.User{}, errors.SSOAccountNotAllowedError{}
+		}
+		user.OIDCIssuer = identity.Issuer
+		user.OIDCSubject = identity.Subject
+		user.EmailVerified = true
+		u.logger.InfoContext(ctx, "Linked SSO identity to account", "email", user.Email, "issuer", identity.Issuer, "subject", identity.Subject)
+		return user, nil
+	case errors.UserNotFoundError:
+	default:
+		return entities.User{}, err
+	}
+
+	if !u.settings.SSOAutoProvision {
+		return entities.User{}, errors.SSOAccountNotAllowedError{}
+	}
+
+	// The random password is never shared: the account signs in through SSO, or
+	// sets a password of its own through the reset flow
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return entities.User{}, err
+	}
+	password, err := hashPassword(ctx, secret)
+	if err != nil {
+		return entities.User{}, err
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if utils.ValidateName(name) != nil {
+		name = email[:strings.Index(email, "@")]
+	}
+
+	user = entities.NewUser(name, email, password)
+	user.EmailVerified = identity.EmailVerified
+	user.OIDCIssuer = identity.Issuer
+	user.OIDCSubject = identity.Subject
+
+	created, err := u.userRepo.InsertOne(ctx, user)
+	if err != nil {
+		return entities.User{}, err
+	}
+	u.logger.InfoContext(ctx, "Provisioned account from SSO identity", "email", created.Email, "issuer", identity.Issuer, "subject", identity.Subject)
+	return created, nil
+}
+
+// ssoDomainAllowed checks the email against SSOAllowedDomains
+func (u *userUsecase) ssoDomainAllowed(email string) bool {
+	if len(u.settings.SSOAllowedDomains) == 0 {
+		return true
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for _, allowed := range u.settings.SSOAllowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// startSession follows a successful first factor: accounts with two-factor
+// authentication get an MFA challenge, everyone else their access token. The
+// failure count is kept until the second factor is also passed, so the password
+// cannot be used to reset the lockout while guessing codes.
+func (u *userUsecase) startSession(ctx context.Context, user entities.User) (LoginResult, error) {
+	if user.MFAEnabled {
+		mfaToken, err := u.tokenService.GenerateActionToken(user.Email, interfaces.TokenPurposeMFALogin, accountFingerprint(user), u.settings.MFAChallengeTTL)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	token, err := u.completeLogin(ctx, user)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{Token: token}, nil
+}
+
+// loginOutcome classifies the result of a login attempt for metrics
+func loginOutcome(mfaRequired bool, err error) string {
+	switch err.(type) {
+	case nil:
+		if mfaRequired {
+			return interfaces.LoginOutcomeMFARequired
+		}
+		return interfaces.LoginOutcomeSuccess
+	case errors.TooManyAttemptsError, errors.AccountLockedError:
+		return interfaces.LoginOutcomeThrottled
+	case errors.AccountDisabledError, errors.EmailNotVerifiedError, errors.SSOAccountNotAllowedError:
+		return interfaces.LoginOutcomeRefused
+	default:
+		return interfaces.LoginOutcomeFailure
+	}
+}
+
+// completeLogin clears the failure count of a fully authenticated user and issues their access token
+func (u *userUsecase) completeLogin(ctx context.Context, user entities.User) (string, error) {
+	if user.FailedLoginAttempts > 0 {
+		if err := u.userRepo.ResetFailedLogins(ctx, user.Email); err != nil {
+			u.logger.ErrorContext(ctx, "Resetting failed logins failed", "email", user.Email, "error", err)
+		}
+	}
+
+	token, err := u.tokenService.GenerateToken(user.Email, user.Role, user.OrganizationID)
 	if err != nil {
 		return "", errors.InvalidCredentialsError{}
 	}
@@ -98,10 +508,585 @@ func (u *userUsecase) Login(email, password string) (string, error) {
 	return token, nil
 }
 
-func (u *userUsecase) PromoteToAdmin(email string) error {
-	return u.userRepo.UpdateRole(email, "admin")
+// checkMFACode accepts a current TOTP code or an unused recovery code. Each is
+// only accepted once.
+func (u *userUsecase) checkMFACode(ctx context.Context, user entities.User, code string, now time.Time) (bool, error) {
+	if step, ok := utils.ValidateTOTP(user.MFASecret, code, now); ok {
+		return u.userRepo.MarkMFAStepUsed(ctx, user.Email, step)
+	}
+
+	return u.userRepo.ConsumeRecoveryCode(ctx, user.Email, utils.HashRecoveryCode(code))
+}
+
+// recordAccountFailure counts a failed password for the account and locks it
+// once the configured threshold is reached
+func (u *userUsecase) recordAccountFailure(ctx context.Context, email string, now time.Time) {
+	failures, err := u.userRepo.RecordFailedLogin(ctx, email)
+	if err != nil {
+		u.logger.ErrorContext(ctx, "Recording failed login failed", "email", email, "error", err)
+		return
+	}
+
+	if lockout := u.settings.lockoutFor(failures); lockout > 0 {
+		if err := u.userRepo.LockUntil(ctx, email, now.Add(lockout)); err != nil {
+			u.logger.ErrorContext(ctx, "Locking account failed", "email", email, "error", err)
+		}
+	}
+}
+
+func (u *userUsecase) PromoteToAdmin(ctx context.Context, email string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.PromoteToAdmin")
+	defer endSpan(span, &err)
+
+	return u.userRepo.UpdateRole(ctx, email, "admin")
+}
+
+func (u *userUsecase) GetUserByEmail(ctx context.Context, email string) (_ entities.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetUserByEmail")
+	defer endSpan(span, &err)
+
+	return u.userRepo.GetUserByEmail(ctx, email)
+}
+
+// UpdateProfile applies a self-service profile change. When the email changes the
+// caller's token no longer identifies them, so a fresh token is returned as well.
+func (u *userUsecase) UpdateProfile(ctx context.Context, email string, update ProfileUpdate) (_ entities.User, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.UpdateProfile")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return entities.User{}, "", err
+	}
+
+	if update.Name != nil {
+		if err := utils.ValidateName(*update.Name); err != nil {
+			return entities.User{}, "", err
+		}
+		user.Name = strings.TrimSpace(*update.Name)
+	}
+
+	emailChanged := false
+	if update.Email != nil && strings.ToLower(*update.Email) != user.Email {
+		newEmail := strings.ToLower(*update.Email)
+		if err := utils.ValidateEmail(newEmail); err != nil {
+			return entities.User{}, "", err
+		}
+
+		// Re-verify the account owner before moving it to another address
+		if !checkPassword(ctx, update.CurrentPassword, user.Password) {
+			return entities.User{}, "", errors.IncorrectPasswordError{}
+		}
+
+		// Emails are unique across organizations, as sign-in looks them up in all
+		inUse, err := u.userRepo.EmailInUse(ctx, newEmail)
+		if err != nil {
+			return entities.User{}, "", err
+		}
+		if inUse {
+			return entities.User{}, "", errors.EmailAlreadyExistsError{}
+		}
+
+		user.Email = newEmail
+		user.EmailVerified = false
+		emailChanged = true
+	}
+
+	updatedUser, err := u.userRepo.UpdateProfile(ctx, email, user.Name, user.Email)
+	if err != nil {
+		return entities.User{}, "", err
+	}
+	updatedUser.Password = ""
+
+	if !emailChanged {
+		return updatedUser, "", nil
+	}
+
+	// Keep tasks pointing at the account under its new address
+	if _, err := u.taskRepo.ReassignTasks(ctx, email, updatedUser.Email); err != nil {
+		return entities.User{}, "", err
+	}
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		u.logger.ErrorContext(ctx, "Sending verification email failed", "email", user.Email, "error", err)
+	}
+
+	token, err := u.tokenService.GenerateToken(updatedUser.Email, updatedUser.Role, updatedUser.OrganizationID)
+	if err != nil {
+		return entities.User{}, "", err
+	}
+
+	return updatedUser, token, nil
+}
+
+func (u *userUsecase) ChangePassword(ctx context.Context, email, currentPassword, newPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ChangePassword")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if !checkPassword(ctx, currentPassword, user.Password) {
+		return errors.IncorrectPasswordError{}
+	}
+
+	if err := utils.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// The password checked may have been changed by another request meanwhile
+	changed, err := u.userRepo.SetPassword(ctx, email, user.Password, hashedPassword)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return errors.IncorrectPasswordError{}
+	}
+	return nil
+}
+
+// DeleteAccount removes the caller's account after confirming their password.
+// Tasks they created are released rather than deleted.
+func (u *userUsecase) DeleteAccount(ctx context.Context, email, password string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.DeleteAccount")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if !checkPassword(ctx, password, user.Password) {
+		return errors.IncorrectPasswordError{}
+	}
+
+	return u.removeUser(ctx, user)
+}
+
+func (u *userUsecase) ListUsers(ctx context.Context, filter entities.UserFilter) (_ []entities.User, _ int64, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ListUsers")
+	defer endSpan(span, &err)
+
+	users, total, err := u.userRepo.ListUsers(ctx, filter.WithDefaults())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Never return passwords
+	for i := range users {
+		users[i].Password = ""
+	}
+	return users, total, nil
+}
+
+// InviteUser adds an account to the organization ctx is scoped to. The account
+// has no password until the new member chooses one with the password reset
+// token they are mailed, which also verifies their address. Should the mail
+// fail, they can ask for another through the forgotten password route.
+func (u *userUsecase) InviteUser(ctx context.Context, invite Invitation) (_ entities.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.InviteUser")
+	defer endSpan(span, &err)
+
+	if err := utils.ValidateEmail(invite.Email); err != nil {
+		return entities.User{}, err
+	}
+	if err := utils.ValidateName(invite.Name); err != nil {
+		return entities.User{}, err
+	}
+	user := entities.NewUser(strings.TrimSpace(invite.Name), strings.ToLower(invite.Email), "")
+	switch invite.Role {
+	case "", "user":
+	case "admin":
+		user.SetRole("admin")
+	default:
+		return entities.User{}, &errors.ValidationError{Field: "role", Message: "role must be admin or user"}
+	}
+	if organizationID, ok := utils.OrganizationFromContext(ctx); ok {
+		user.OrganizationID = organizationID
+	}
+
+	inUse, err := u.userRepo.EmailInUse(ctx, user.Email)
+	if err != nil {
+		return entities.User{}, err
+	}
+	if inUse {
+		return entities.User{}, errors.EmailAlreadyExistsError{}
+	}
+
+	createdUser, err := u.userRepo.InsertOne(ctx, user)
+	if err != nil {
+		return entities.User{}, err
+	}
+
+	if err := u.sendInvitation(ctx, createdUser); err != nil {
+		u.logger.ErrorContext(ctx, "Sending invitation failed", "email", createdUser.Email, "error", err)
+	}
+	return createdUser, nil
+}
+
+func (u *userUsecase) GetUserByID(ctx context.Context, id string) (_ entities.User, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetUserByID")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return entities.User{}, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+func (u *userUsecase) DemoteToUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.DemoteToUser")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.ensureNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	return u.userRepo.UpdateRole(ctx, user.Email, "user")
+}
+
+func (u *userUsecase) SetUserDisabled(ctx context.Context, id string, disabled bool) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.SetUserDisabled")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if disabled {
+		if err := u.ensureNotLastAdmin(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	return u.userRepo.SetDisabled(ctx, user.Email, disabled)
+}
+
+func (u *userUsecase) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.DeleteUser")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return u.removeUser(ctx, user)
 }
 
-func (u *userUsecase) GetUserByEmail(email string) (entities.User, error) {
-	return u.userRepo.GetUserByEmail(email)
-} 
\ No newline at end of file
+// UnlockUser clears an account's failed login count and any lockout
+func (u *userUsecase) UnlockUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.UnlockUser")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return u.userRepo.ResetFailedLogins(ctx, user.Email)
+}
+
+// removeUser deletes an account and its API keys, releasing the tasks it created
+func (u *userUsecase) removeUser(ctx context.Context, user entities.User) error {
+	if err := u.ensureNotLastAdmin(ctx, user); err != nil {
+		return err
+	}
+
+	if _, err := u.taskRepo.ReassignTasks(ctx, user.Email, ""); err != nil {
+		return err
+	}
+
+	if _, err := u.apiKeyRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return u.userRepo.DeleteOne(ctx, user.Email)
+}
+
+// ensureNotLastAdmin refuses to take away the only remaining active admin
+func (u *userUsecase) ensureNotLastAdmin(ctx context.Context, user entities.User) error {
+	if !user.IsAdmin() || !user.IsActive() {
+		return nil
+	}
+
+	count, err := u.userRepo.CountActiveAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errors.LastAdminError{}
+	}
+
+	return nil
+}
+
+func (u *userUsecase) VerifyEmail(ctx context.Context, token string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.VerifyEmail")
+	defer endSpan(span, &err)
+
+	user, err := u.consumeActionToken(ctx, token, interfaces.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	return u.userRepo.MarkEmailVerified(ctx, user.Email)
+}
+
+// ResendVerification mails a new verification link. It reports success for
+// unknown or already verified addresses so it cannot be used to probe accounts.
+func (u *userUsecase) ResendVerification(ctx context.Context, email string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ResendVerification")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil || user.EmailVerified {
+		return nil
+	}
+
+	return u.sendVerificationEmail(ctx, user)
+}
+
+// RequestPasswordReset mails a reset link. Like ResendVerification it does not
+// reveal whether the address belongs to an account.
+func (u *userUsecase) RequestPasswordReset(ctx context.Context, email string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.RequestPasswordReset")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil || !user.IsActive() {
+		return nil
+	}
+
+	token, err := u.tokenService.GenerateActionToken(user.Email, interfaces.TokenPurposePasswordReset, accountFingerprint(user), u.settings.PasswordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Someone asked to reset the password for this account. If it was you, %s\n\n"+
+		"It expires in %s and can only be used once. If you did not ask for this, you can ignore this email.\n",
+		user.Name, u.passwordInstructions(token), u.settings.PasswordResetTokenTTL)
+
+	return u.mailer.Send(ctx, user.Email, "Reset your password", body)
+}
+
+// passwordInstructions tells the reader how to choose a password with a reset
+// token: through the client's page if there is one, or else the API
+func (u *userUsecase) passwordInstructions(token string) string {
+	if link, err := url.Parse(u.settings.PasswordResetURL); err == nil && u.settings.PasswordResetURL != "" {
+		query := link.Query()
+		query.Set("token", token)
+		link.RawQuery = query.Encode()
+		return "use the link below to choose a password:\n\n" + link.String()
+	}
+	return "send this token with the password you choose to " + u.settings.AppBaseURL + "/api/v1/reset-password:\n\n" + token
+}
+
+func (u *userUsecase) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ResetPassword")
+	defer endSpan(span, &err)
+
+	if err := utils.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	user, err := u.consumeActionToken(ctx, token, interfaces.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Only one request can change the password the token is bound to
+	reset, err := u.userRepo.SetPassword(ctx, user.Email, user.Password, hashedPassword)
+	if err != nil {
+		return err
+	}
+	if !reset {
+		return errors.InvalidActionTokenError{}
+	}
+
+	// Receiving the reset mail proves the user controls the address
+	if err := u.userRepo.MarkEmailVerified(ctx, user.Email); err != nil {
+		return err
+	}
+	return u.userRepo.ResetFailedLogins(ctx, user.Email)
+}
+
+// EnrollMFA starts two-factor enrollment with a new secret. It is not enforced
+// until confirmed with ConfirmMFA; enrolling again replaces an unconfirmed secret.
+func (u *userUsecase) EnrollMFA(ctx context.Context, email string) (_ MFAEnrollment, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.EnrollMFA")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return MFAEnrollment{}, err
+	}
+
+	if user.MFAEnabled {
+		return MFAEnrollment{}, errors.MFAAlreadyEnabledError{}
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return MFAEnrollment{}, err
+	}
+	// MFA may have been confirmed by another request meanwhile
+	set, err := u.userRepo.SetMFASecret(ctx, user.Email, secret)
+	if err != nil {
+		return MFAEnrollment{}, err
+	}
+	if !set {
+		return MFAEnrollment{}, errors.MFAAlreadyEnabledError{}
+	}
+
+	return MFAEnrollment{
+		Secret: secret,
+		URI:    utils.TOTPURI(u.settings.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables two-factor authentication once the user proves their
+// authenticator works, and returns recovery codes. They are only shown this once.
+func (u *userUsecase) ConfirmMFA(ctx context.Context, email, code string) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ConfirmMFA")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled {
+		return nil, errors.MFAAlreadyEnabledError{}
+	}
+	if user.MFASecret == "" {
+		return nil, errors.MFANotEnrolledError{}
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now())
+	if !ok {
+		return nil, errors.InvalidMFACodeError{}
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(MFARecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, utils.HashRecoveryCode(code))
+	}
+
+	// The code only proves the secret it was checked against, which enrolling
+	// again meanwhile replaces
+	enabled, err := u.userRepo.EnableMFA(ctx, user.Email, user.MFASecret, hashes, step)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, errors.InvalidMFACodeError{}
+	}
+
+	return codes, nil
+}
+
+// DisableMFA turns off two-factor authentication after confirming the password
+func (u *userUsecase) DisableMFA(ctx context.Context, email, password string) (err error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.DisableMFA")
+	defer endSpan(span, &err)
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if !checkPassword(ctx, password, user.Password) {
+		return errors.IncorrectPasswordError{}
+	}
+
+	if !user.MFAEnabled && user.MFASecret == "" {
+		return errors.MFANotEnrolledError{}
+	}
+
+	return u.userRepo.ClearMFA(ctx, user.Email)
+}
+
+func (u *userUsecase) sendVerificationEmail(ctx context.Context, user entities.User) error {
+	token, err := u.tokenService.GenerateActionToken(user.Email, interfaces.TokenPurposeVerifyEmail, accountFingerprint(user), u.settings.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Please confirm your email address by visiting:\n\n"+
+		"%s/api/v1/verify-email?token=%s\n\n"+
+		"The link expires in %s.\n",
+		user.Name, u.settings.AppBaseURL, url.QueryEscape(token), u.settings.VerificationTokenTTL)
+
+	return u.mailer.Send(ctx, user.Email, "Confirm your email address", body)
+}
+
+func (u *userUsecase) sendInvitation(ctx context.Context, user entities.User) error {
+	// Invitations wait for their reader like verification links do
+	token, err := u.tokenService.GenerateActionToken(user.Email, interfaces.TokenPurposePasswordReset, accountFingerprint(user), u.settings.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"An account has been created for you. To sign in, %s\n\n"+
+		"It expires in %s and can only be used once.\n",
+		user.Name, u.passwordInstructions(token), u.settings.VerificationTokenTTL)
+
+	return u.mailer.Send(ctx, user.Email, "You have been invited", body)
+}
+
+// consumeActionToken resolves an action token to the account it was issued for,
+// rejecting it if the account has changed since (which is what makes it single-use)
+func (u *userUsecase) consumeActionToken(ctx context.Context, token, purpose string) (entities.User, error) {
+	email, fingerprint, err := u.tokenService.ValidateActionToken(token, purpose)
+	if err != nil {
+		return entities.User{}, errors.InvalidActionTokenError{}
+	}
+
+	user, err := u.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return entities.User{}, errors.InvalidActionTokenError{}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(fingerprint), []byte(accountFingerprint(user))) != 1 {
+		return entities.User{}, errors.InvalidActionTokenError{}
+	}
+
+	return user, nil
+}
+
+// accountFingerprint summarises the account state an action token is bound to.
+// Verifying the email or changing the password changes it.
+func accountFingerprint(user entities.User) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%t", user.Email, user.Password, user.EmailVerified)))
+	return hex.EncodeToString(sum[:16])
+}
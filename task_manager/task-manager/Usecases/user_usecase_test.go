@@ -1,36 +1,73 @@
 package usecases_test
 
 import (
-	"testing"
+	"context"
+	"log/slog"
 	"task_manager/Domain/entities"
 	"task_manager/Domain/errors"
-	"task_manager/mocks"
+	"task_manager/Domain/interfaces"
 	usecase "task_manager/Usecases"
+	"task_manager/mocks"
+	"task_manager/utils"
+	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
+// testLogger discards what the use cases log
+var testLogger = slog.New(slog.DiscardHandler)
+
+// recordingMetrics keeps the logins the use cases report, as "method:outcome"
+type recordingMetrics struct {
+	logins []string
+}
+
+func (m *recordingMetrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+}
+
+func (m *recordingMetrics) ObserveLogin(method, outcome string) {
+	m.logins = append(m.logins, method+":"+outcome)
+}
+
 func TestRegister(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 
-	// Mock CountDocuments to return 0 (first user becomes admin)
-	mockUserRepo.EXPECT().CountDocuments(user.Email).Return(int64(0), nil)
-	
+	// The organization has no accounts yet, so its first becomes admin
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), user.Email).Return(false, nil)
+	mockUserRepo.EXPECT().CountUsers(gomock.Any()).DoAndReturn(func(ctx context.Context) (int64, error) {
+		organizationID, _ := utils.OrganizationFromContext(ctx)
+		assert.Equal(t, entities.DefaultOrganizationID, organizationID)
+		return 0, nil
+	})
+
 	// Mock InsertOne to return the created user
 	expectedUser := user
 	expectedUser.SetRole("admin")
 	expectedUser.Password = "" // Password should be cleared in response
-	mockUserRepo.EXPECT().InsertOne(gomock.Any()).Return(expectedUser, nil)
+	mockUserRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).Return(expectedUser, nil)
+
+	// A verification email is sent for the new account
+	mockTokenService.EXPECT().GenerateActionToken(user.Email, interfaces.TokenPurposeVerifyEmail, gomock.Any(), gomock.Any()).Return("verify-token", nil)
+	mockMailer.EXPECT().Send(gomock.Any(), user.Email, gomock.Any(), gomock.Any()).Return(nil)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	result, err := userUsecase.Register(user)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	result, err := userUsecase.Register(context.Background(), user)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser.Email, result.Email)
@@ -44,31 +81,67 @@ func TestRegisterEmailAlreadyExists(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	user := entities.NewUser("Test User", "test@example.com", "password123")
 
-	// Mock CountDocuments to return 1 (user already exists)
-	mockUserRepo.EXPECT().CountDocuments(user.Email).Return(int64(1), nil)
+	// The email is taken, in this organization or another
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), user.Email).Return(true, nil)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	result, err := userUsecase.Register(user)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	result, err := userUsecase.Register(context.Background(), user)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.EmailAlreadyExistsError{}, err)
 	assert.Equal(t, entities.User{}, result)
 }
 
+func TestRegisterLaterUsersAreNotAdmins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	user := entities.NewUser("Second User", "second@example.com", "password123")
+
+	// Someone already registered, so the new account gets no admin powers
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), user.Email).Return(false, nil)
+	mockUserRepo.EXPECT().CountUsers(gomock.Any()).Return(int64(1), nil)
+	mockUserRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, created entities.User) (entities.User, error) {
+		assert.Equal(t, "user", created.Role)
+		return created, nil
+	})
+	mockTokenService.EXPECT().GenerateActionToken(user.Email, interfaces.TokenPurposeVerifyEmail, gomock.Any(), gomock.Any()).Return("verify-token", nil)
+	mockMailer.EXPECT().Send(gomock.Any(), user.Email, gomock.Any(), gomock.Any()).Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mockTokenService, mockMailer, mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	result, err := userUsecase.Register(context.Background(), user)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user", result.Role)
+	assert.False(t, result.IsAdmin())
+}
+
 func TestLogin(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	email := "test@example.com"
 	password := "password123"
-	
+
 	// Create a user with hashed password (this would be the actual hash)
 	user := entities.User{
 		ID:       "123",
@@ -78,15 +151,68 @@ func TestLogin(t *testing.T) {
 		Role:     "user",
 	}
 
-	mockUserRepo.EXPECT().GetUserByEmail(email).Return(user, nil)
+	clientIP := "192.0.2.1"
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
 	// Note: We don't expect GenerateToken to be called because bcrypt will fail
+	mockThrottle.EXPECT().RecordFailure(clientIP)
+	mockUserRepo.EXPECT().RecordFailedLogin(gomock.Any(), email).Return(1, nil)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	_, err := userUsecase.Login(email, password)
+	metrics := &recordingMetrics{}
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, metrics, usecase.DefaultAuthSettings(), testLogger)
+	_, err := userUsecase.Login(context.Background(), email, password, clientIP)
 
 	// Note: This test will fail because we can't easily mock bcrypt
 	// In a real scenario, you'd want to mock the bcrypt functions
 	assert.Error(t, err) // This will fail due to bcrypt comparison
+	assert.Equal(t, []string{"password:failure"}, metrics.logins)
+}
+
+func TestLoginTracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	clientIP := "192.0.2.1"
+	user := entities.User{ID: "123", Email: email, Password: "$2a$14$abcdefghijklmnopqrstuvwxyz1234567890", Role: "user"}
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockThrottle.EXPECT().RecordFailure(clientIP)
+	mockUserRepo.EXPECT().RecordFailedLogin(gomock.Any(), email).Return(1, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	// The request's span is the parent of the use case span, which is the parent of bcrypt's
+	ctx, requestSpan := otel.Tracer("test").Start(context.Background(), "POST /login")
+	userUsecase.Login(ctx, email, "password123", clientIP)
+	requestSpan.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	if assert.Contains(t, spans, "UserUsecase.Login") && assert.Contains(t, spans, "bcrypt.Compare") {
+		assert.Equal(t, requestSpan.SpanContext().SpanID(), spans["UserUsecase.Login"].Parent().SpanID())
+		assert.Equal(t, spans["UserUsecase.Login"].SpanContext().SpanID(), spans["bcrypt.Compare"].Parent().SpanID())
+
+		// The use case span carries the error it returned
+		login := spans["UserUsecase.Login"]
+		assert.Equal(t, codes.Error, login.Status().Code)
+		assert.Equal(t, errors.InvalidCredentialsError{}.Error(), login.Status().Description)
+		if assert.Len(t, login.Events(), 1) {
+			assert.Equal(t, "exception", login.Events()[0].Name)
+		}
+	}
 }
 
 func TestLoginInvalidCredentials(t *testing.T) {
@@ -94,15 +220,23 @@ func TestLoginInvalidCredentials(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	email := "test@example.com"
 	password := "wrongpassword"
 
-	mockUserRepo.EXPECT().GetUserByEmail(email).Return(entities.User{}, assert.AnError)
+	clientIP := "192.0.2.1"
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	_, err := userUsecase.Login(email, password)
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(entities.User{}, assert.AnError)
+	mockThrottle.EXPECT().RecordFailure(clientIP)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, err := userUsecase.Login(context.Background(), email, password, clientIP)
 
 	assert.Error(t, err)
 	assert.IsType(t, errors.InvalidCredentialsError{}, err)
@@ -113,13 +247,17 @@ func TestPromoteToAdmin(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	email := "test@example.com"
-	mockUserRepo.EXPECT().UpdateRole(email, "admin").Return(nil)
+	mockUserRepo.EXPECT().UpdateRole(gomock.Any(), email, "admin").Return(nil)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	err := userUsecase.PromoteToAdmin(email)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.PromoteToAdmin(context.Background(), email)
 
 	assert.NoError(t, err)
 }
@@ -129,7 +267,11 @@ func TestGetUserByEmail(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	email := "test@example.com"
 	expectedUser := entities.User{
@@ -139,10 +281,10 @@ func TestGetUserByEmail(t *testing.T) {
 		Role:  "user",
 	}
 
-	mockUserRepo.EXPECT().GetUserByEmail(email).Return(expectedUser, nil)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(expectedUser, nil)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	user, err := userUsecase.GetUserByEmail(email)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	user, err := userUsecase.GetUserByEmail(context.Background(), email)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
@@ -153,14 +295,916 @@ func TestGetUserByEmailNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
 	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
 
 	email := "nonexistent@example.com"
-	mockUserRepo.EXPECT().GetUserByEmail(email).Return(entities.User{}, assert.AnError)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(entities.User{}, assert.AnError)
 
-	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTokenService)
-	user, err := userUsecase.GetUserByEmail(email)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	user, err := userUsecase.GetUserByEmail(context.Background(), email)
 
 	assert.Error(t, err)
 	assert.Equal(t, entities.User{}, user)
-} 
\ No newline at end of file
+}
+
+func TestUpdateProfileName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	existing := entities.User{ID: "123", Name: "Old Name", Email: email, Password: "hash", Role: "user"}
+	newName := "New Name"
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	// Only the name is written, leaving the rest of the account as it is now
+	mockUserRepo.EXPECT().UpdateProfile(gomock.Any(), email, "New Name", email).Return(entities.User{ID: "123", Name: "New Name", Email: email, Password: "hash", Role: "user"}, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	user, token, err := userUsecase.UpdateProfile(context.Background(), email, usecase.ProfileUpdate{Name: &newName})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", user.Name)
+	assert.Equal(t, "", user.Password)
+	assert.Equal(t, "", token) // email unchanged, old token stays valid
+}
+
+func TestUpdateProfileEmailRequiresPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	newEmail := "new@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, _, err := userUsecase.UpdateProfile(context.Background(), email, usecase.ProfileUpdate{Email: &newEmail, CurrentPassword: "wrongpassword"})
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.IncorrectPasswordError{}, err)
+}
+
+func TestUpdateProfileEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	newEmail := "New@Example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), "new@example.com").Return(false, nil)
+	mockUserRepo.EXPECT().UpdateProfile(gomock.Any(), email, "Test User", "new@example.com").Return(entities.User{ID: "123", Name: "Test User", Email: "new@example.com", Password: hash, Role: "user"}, nil)
+	mockTaskRepo.EXPECT().ReassignTasks(gomock.Any(), email, "new@example.com").Return(int64(2), nil)
+	mockTokenService.EXPECT().GenerateToken("new@example.com", "user", "").Return("new-token", nil)
+	mockTokenService.EXPECT().GenerateActionToken("new@example.com", interfaces.TokenPurposeVerifyEmail, gomock.Any(), gomock.Any()).Return("verify-token", nil)
+	mockMailer.EXPECT().Send(gomock.Any(), "new@example.com", gomock.Any(), gomock.Any()).Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	user, token, err := userUsecase.UpdateProfile(context.Background(), email, usecase.ProfileUpdate{Email: &newEmail, CurrentPassword: "password123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.False(t, user.EmailVerified) // new address must be verified again
+	assert.Equal(t, "new-token", token)
+}
+
+func TestUpdateProfileEmailTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	newEmail := "taken@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), newEmail).Return(true, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, _, err := userUsecase.UpdateProfile(context.Background(), email, usecase.ProfileUpdate{Email: &newEmail, CurrentPassword: "password123"})
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.EmailAlreadyExistsError{}, err)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	mockUserRepo.EXPECT().SetPassword(gomock.Any(), email, hash, gomock.Any()).DoAndReturn(func(_ context.Context, _, _, newHash string) (bool, error) {
+		assert.True(t, utils.CheckPassword("newpassword456", newHash))
+		return true, nil
+	})
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.ChangePassword(context.Background(), email, "password123", "newpassword456")
+
+	assert.NoError(t, err)
+}
+
+func TestChangePasswordChangedMeanwhile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	// Another request changed the password after it was checked
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	mockUserRepo.EXPECT().SetPassword(gomock.Any(), email, hash, gomock.Any()).Return(false, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.ChangePassword(context.Background(), email, "password123", "newpassword456")
+
+	assert.IsType(t, errors.IncorrectPasswordError{}, err)
+}
+
+func TestChangePasswordWrongCurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.ChangePassword(context.Background(), email, "wrongpassword", "newpassword456")
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.IncorrectPasswordError{}, err)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	existing := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(existing, nil)
+	mockTaskRepo.EXPECT().ReassignTasks(gomock.Any(), email, "").Return(int64(3), nil)
+	mockAPIKeyRepo.EXPECT().DeleteByUser(gomock.Any(), "123").Return(int64(1), nil)
+	mockUserRepo.EXPECT().DeleteOne(gomock.Any(), email).Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.DeleteAccount(context.Background(), email, "password123")
+
+	assert.NoError(t, err)
+}
+
+func TestLoginDisabledAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user", Disabled: true}
+	clientIP := "192.0.2.1"
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, err := userUsecase.Login(context.Background(), email, "password123", clientIP)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.AccountDisabledError{}, err)
+}
+
+func TestListUsersAppliesDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	stored := []entities.User{
+		{ID: "1", Name: "Alice", Email: "alice@example.com", Password: "hash", Role: "admin"},
+		{ID: "2", Name: "Bob", Email: "bob@example.com", Password: "hash", Role: "user"},
+	}
+	expectedFilter := entities.UserFilter{Query: "example", Page: 1, Limit: entities.MaxPageSize}
+	mockUserRepo.EXPECT().ListUsers(gomock.Any(), expectedFilter).Return(stored, int64(2), nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	users, total, err := userUsecase.ListUsers(context.Background(), entities.UserFilter{Query: "example", Limit: 1000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, users, 2)
+	for _, user := range users {
+		assert.Equal(t, "", user.Password)
+	}
+}
+
+func TestDemoteLastAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	id := "507f1f77bcf86cd799439011"
+	admin := entities.User{ID: id, Name: "Admin", Email: "admin@example.com", Role: "admin"}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), id).Return(admin, nil)
+	mockUserRepo.EXPECT().CountActiveAdmins(gomock.Any()).Return(int64(1), nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.DemoteToUser(context.Background(), id)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.LastAdminError{}, err)
+}
+
+func TestDemoteToUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	id := "507f1f77bcf86cd799439011"
+	admin := entities.User{ID: id, Name: "Admin", Email: "admin@example.com", Role: "admin"}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), id).Return(admin, nil)
+	mockUserRepo.EXPECT().CountActiveAdmins(gomock.Any()).Return(int64(2), nil)
+	mockUserRepo.EXPECT().UpdateRole(gomock.Any(), "admin@example.com", "user").Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.DemoteToUser(context.Background(), id)
+
+	assert.NoError(t, err)
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	id := "507f1f77bcf86cd799439011"
+	user := entities.User{ID: id, Name: "Test User", Email: "test@example.com", Role: "user"}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), id).Return(user, nil)
+	mockUserRepo.EXPECT().SetDisabled(gomock.Any(), "test@example.com", true).Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.SetUserDisabled(context.Background(), id, true)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteUserLastAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	id := "507f1f77bcf86cd799439011"
+	admin := entities.User{ID: id, Name: "Admin", Email: "admin@example.com", Role: "admin"}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), id).Return(admin, nil)
+	mockUserRepo.EXPECT().CountActiveAdmins(gomock.Any()).Return(int64(1), nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.DeleteUser(context.Background(), id)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.LastAdminError{}, err)
+}
+
+func TestLoginEmailNotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user"}
+	clientIP := "192.0.2.1"
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+
+	settings := usecase.DefaultAuthSettings()
+	settings.RequireEmailVerification = true
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, settings, testLogger)
+	_, err := userUsecase.Login(context.Background(), email, "password123", clientIP)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.EmailNotVerifiedError{}, err)
+}
+
+func TestVerifyEmailIsSingleUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: "hash", Role: "user"}
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	// Issue a token and capture the fingerprint it is bound to
+	var fingerprint string
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockTokenService.EXPECT().GenerateActionToken(email, interfaces.TokenPurposeVerifyEmail, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_, _, fp string, _ time.Duration) (string, error) {
+			fingerprint = fp
+			return "verify-token", nil
+		})
+	mockMailer.EXPECT().Send(gomock.Any(), email, gomock.Any(), gomock.Any()).Return(nil)
+	assert.NoError(t, userUsecase.ResendVerification(context.Background(), email))
+
+	// First use verifies the account
+	mockTokenService.EXPECT().ValidateActionToken("verify-token", interfaces.TokenPurposeVerifyEmail).Return(email, fingerprint, nil).Times(2)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockUserRepo.EXPECT().MarkEmailVerified(gomock.Any(), email).Return(nil)
+	assert.NoError(t, userUsecase.VerifyEmail(context.Background(), "verify-token"))
+
+	// Once verified, the same token no longer matches the account
+	verified := user
+	verified.EmailVerified = true
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(verified, nil)
+	err := userUsecase.VerifyEmail(context.Background(), "verify-token")
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.InvalidActionTokenError{}, err)
+}
+
+func TestRequestPasswordResetUnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "nobody@example.com"
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(entities.User{}, errors.UserNotFoundError{})
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.RequestPasswordReset(context.Background(), email)
+
+	// No error and no mail: the caller cannot tell whether the account exists
+	assert.NoError(t, err)
+}
+
+func TestResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: "old-hash", Role: "user"}
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	var fingerprint string
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockTokenService.EXPECT().GenerateActionToken(email, interfaces.TokenPurposePasswordReset, gomock.Any(), time.Hour).
+		DoAndReturn(func(_, _, fp string, _ time.Duration) (string, error) {
+			fingerprint = fp
+			return "reset-token", nil
+		})
+	// Without a reset page the email holds the token for the API
+	mockMailer.EXPECT().Send(gomock.Any(), email, "Reset your password", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, body string) error {
+		assert.Contains(t, body, "http://localhost:8080/api/v1/reset-password:\n\nreset-token\n")
+		return nil
+	})
+	assert.NoError(t, userUsecase.RequestPasswordReset(context.Background(), email))
+
+	mockTokenService.EXPECT().ValidateActionToken("reset-token", interfaces.TokenPurposePasswordReset).Return(email, fingerprint, nil).Times(2)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil).Times(2)
+	mockUserRepo.EXPECT().SetPassword(gomock.Any(), email, "old-hash", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, newHash string) (bool, error) {
+		assert.True(t, utils.CheckPassword("newpassword456", newHash))
+		return true, nil
+	})
+	mockUserRepo.EXPECT().MarkEmailVerified(gomock.Any(), email).Return(nil)
+	mockUserRepo.EXPECT().ResetFailedLogins(gomock.Any(), email).Return(nil)
+
+	err := userUsecase.ResetPassword(context.Background(), "reset-token", "newpassword456")
+	assert.NoError(t, err)
+
+	// A second request with the token, racing the first, finds the password changed
+	mockUserRepo.EXPECT().SetPassword(gomock.Any(), email, "old-hash", gomock.Any()).Return(false, nil)
+	err = userUsecase.ResetPassword(context.Background(), "reset-token", "otherpassword789")
+	assert.IsType(t, errors.InvalidActionTokenError{}, err)
+}
+
+func TestRequestPasswordResetLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	email := "test@example.com"
+	settings := usecase.DefaultAuthSettings()
+	settings.PasswordResetURL = "https://app.example.com/account/reset?lang=en"
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mockTokenService, mockMailer, mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, settings, testLogger)
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(entities.User{ID: "123", Name: "Test User", Email: email}, nil)
+	mockTokenService.EXPECT().GenerateActionToken(email, interfaces.TokenPurposePasswordReset, gomock.Any(), time.Hour).Return("a+b", nil)
+	mockMailer.EXPECT().Send(gomock.Any(), email, "Reset your password", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, body string) error {
+		assert.Contains(t, body, "https://app.example.com/account/reset?lang=en&token=a%2Bb\n")
+		return nil
+	})
+
+	assert.NoError(t, userUsecase.RequestPasswordReset(context.Background(), email))
+}
+
+func TestInviteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mockTokenService, mockMailer, mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	// An admin of org1 adds a member, who joins org1 without a password
+	ctx := utils.ContextWithOrganization(context.Background(), "org1")
+	var invited entities.User
+	var fingerprint string
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), "carol@example.com").Return(false, nil)
+	mockUserRepo.EXPECT().InsertOne(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user entities.User) (entities.User, error) {
+		assert.Equal(t, "org1", user.OrganizationID)
+		assert.Equal(t, "user", user.Role)
+		assert.Empty(t, user.Password)
+		user.ID = "user3"
+		invited = user
+		return user, nil
+	})
+	mockTokenService.EXPECT().GenerateActionToken("carol@example.com", interfaces.TokenPurposePasswordReset, gomock.Any(), 48*time.Hour).
+		DoAndReturn(func(_, _, fp string, _ time.Duration) (string, error) {
+			fingerprint = fp
+			return "invite-token", nil
+		})
+	mockMailer.EXPECT().Send(gomock.Any(), "carol@example.com", "You have been invited", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, body string) error {
+		assert.Contains(t, body, "invite-token")
+		return nil
+	})
+
+	user, err := userUsecase.InviteUser(ctx, usecase.Invitation{Name: "Carol", Email: "Carol@Example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user3", user.ID)
+	assert.False(t, user.IsAdmin())
+
+	// Nobody can sign in to the account until the invitation is used
+	assert.False(t, utils.CheckPassword("", invited.Password))
+
+	// The mailed token sets the password and verifies the address
+	mockTokenService.EXPECT().ValidateActionToken("invite-token", interfaces.TokenPurposePasswordReset).Return(invited.Email, fingerprint, nil)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), invited.Email).Return(invited, nil)
+	mockUserRepo.EXPECT().SetPassword(gomock.Any(), invited.Email, "", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, newHash string) (bool, error) {
+		assert.True(t, utils.CheckPassword("carolspassword", newHash))
+		return true, nil
+	})
+	mockUserRepo.EXPECT().MarkEmailVerified(gomock.Any(), invited.Email).Return(nil)
+	mockUserRepo.EXPECT().ResetFailedLogins(gomock.Any(), invited.Email).Return(nil)
+	assert.NoError(t, userUsecase.ResetPassword(ctx, "invite-token", "carolspassword"))
+}
+
+func TestInviteUserRefused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	ctx := utils.ContextWithOrganization(context.Background(), "org1")
+
+	_, err := userUsecase.InviteUser(ctx, usecase.Invitation{Name: "Carol", Email: "carol@example.com", Role: "owner"})
+	var validation *errors.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	// Addresses are unique across organizations
+	mockUserRepo.EXPECT().EmailInUse(gomock.Any(), "carol@example.com").Return(true, nil)
+	_, err = userUsecase.InviteUser(ctx, usecase.Invitation{Name: "Carol", Email: "carol@example.com", Role: "admin"})
+	assert.Equal(t, errors.EmailAlreadyExistsError{}, err)
+}
+
+func TestResetPasswordInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	mockTokenService.EXPECT().ValidateActionToken("bad-token", interfaces.TokenPurposePasswordReset).Return("", "", assert.AnError)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.ResetPassword(context.Background(), "bad-token", "newpassword456")
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.InvalidActionTokenError{}, err)
+}
+
+func TestLoginThrottledClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	clientIP := "192.0.2.1"
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(30 * time.Second)
+	// The account is never looked up and no password is hashed
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, err := userUsecase.Login(context.Background(), "test@example.com", "password123", clientIP)
+
+	assert.Error(t, err)
+	assert.Equal(t, errors.TooManyAttemptsError{RetryAfter: 30 * time.Second}, err)
+}
+
+func TestLoginLockedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	clientIP := "192.0.2.1"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: "hash", Role: "user",
+		FailedLoginAttempts: 5, LockedUntil: time.Now().Add(time.Minute)}
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	_, err := userUsecase.Login(context.Background(), email, "password123", clientIP)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.AccountLockedError{}, err)
+	assert.True(t, err.(errors.AccountLockedError).RetryAfter > 0)
+}
+
+func TestLoginLocksAccountAfterMaxFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	clientIP := "192.0.2.1"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user", FailedLoginAttempts: 6}
+
+	settings := usecase.DefaultAuthSettings()
+	start := time.Now()
+
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockThrottle.EXPECT().RecordFailure(clientIP)
+	mockUserRepo.EXPECT().RecordFailedLogin(gomock.Any(), email).Return(7, nil)
+	// Seventh failure with a threshold of five: base lockout doubled twice
+	mockUserRepo.EXPECT().LockUntil(gomock.Any(), email, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+		assert.WithinDuration(t, start.Add(4*settings.LockoutDuration), until, 5*time.Second)
+		return nil
+	})
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, settings, testLogger)
+	_, err := userUsecase.Login(context.Background(), email, "wrongpassword", clientIP)
+
+	assert.Error(t, err)
+	assert.IsType(t, errors.InvalidCredentialsError{}, err)
+}
+
+func TestUnlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	id := "507f1f77bcf86cd799439011"
+	user := entities.User{ID: id, Name: "Test User", Email: "test@example.com", Role: "user", FailedLoginAttempts: 5}
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), id).Return(user, nil)
+	mockUserRepo.EXPECT().ResetFailedLogins(gomock.Any(), "test@example.com").Return(nil)
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+	err := userUsecase.UnlockUser(context.Background(), id)
+
+	assert.NoError(t, err)
+}
+
+func TestLoginWithMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	hash, _ := utils.HashPassword("password123")
+	secret, _ := utils.GenerateTOTPSecret()
+	email := "test@example.com"
+	clientIP := "192.0.2.1"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: hash, Role: "user",
+		FailedLoginAttempts: 2, MFAEnabled: true, MFASecret: secret}
+
+	settings := usecase.DefaultAuthSettings()
+	metrics := &recordingMetrics{}
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, metrics, settings, testLogger)
+
+	// The password step only returns a challenge and leaves the failure count alone
+	var fingerprint string
+	mockThrottle.EXPECT().RetryAfter(clientIP).Return(time.Duration(0)).Times(4)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil).Times(4)
+	mockTokenService.EXPECT().GenerateActionToken(email, interfaces.TokenPurposeMFALogin, gomock.Any(), settings.MFAChallengeTTL).
+		DoAndReturn(func(_, _, fp string, _ time.Duration) (string, error) {
+			fingerprint = fp
+			return "mfa-token", nil
+		})
+
+	result, err := userUsecase.Login(context.Background(), email, "password123", clientIP)
+	assert.NoError(t, err)
+	assert.True(t, result.MFARequired)
+	assert.Equal(t, "mfa-token", result.MFAToken)
+	assert.Empty(t, result.Token)
+
+	// A wrong code counts as a failed login
+	mockTokenService.EXPECT().ValidateActionToken("mfa-token", interfaces.TokenPurposeMFALogin).Return(email, fingerprint, nil).Times(3)
+	mockUserRepo.EXPECT().ConsumeRecoveryCode(gomock.Any(), email, utils.HashRecoveryCode("wrong")).Return(false, nil)
+	mockThrottle.EXPECT().RecordFailure(clientIP)
+	mockUserRepo.EXPECT().RecordFailedLogin(gomock.Any(), email).Return(3, nil)
+
+	_, err = userUsecase.VerifyMFALogin(context.Background(), "mfa-token", "wrong", clientIP)
+	assert.IsType(t, errors.InvalidMFACodeError{}, err)
+
+	// A current code completes the login
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	mockUserRepo.EXPECT().MarkMFAStepUsed(gomock.Any(), email, gomock.Any()).Return(true, nil)
+	mockUserRepo.EXPECT().ResetFailedLogins(gomock.Any(), email).Return(nil).Times(2)
+	mockTokenService.EXPECT().GenerateToken(email, "user", "").Return("jwt-token", nil)
+
+	token, err := userUsecase.VerifyMFALogin(context.Background(), "mfa-token", code, clientIP)
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", token)
+
+	// So does an unused recovery code
+	mockUserRepo.EXPECT().ConsumeRecoveryCode(gomock.Any(), email, utils.HashRecoveryCode("abcde-12345")).Return(true, nil)
+	mockTokenService.EXPECT().GenerateToken(email, "user", "").Return("jwt-token", nil)
+
+	_, err = userUsecase.VerifyMFALogin(context.Background(), "mfa-token", "ABCDE12345", clientIP)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"password:mfa_required", "mfa:failure", "mfa:success", "mfa:success"}, metrics.logins)
+}
+
+func TestEnrollAndConfirmMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockTaskRepo := mocks.NewMockTaskRepository(ctrl)
+	mockAPIKeyRepo := mocks.NewMockAPIKeyRepository(ctrl)
+	mockTokenService := mocks.NewMockTokenService(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	mockThrottle := mocks.NewMockLoginThrottle(ctrl)
+
+	email := "test@example.com"
+	user := entities.User{ID: "123", Name: "Test User", Email: email, Password: "hash", Role: "user"}
+
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mockTaskRepo, mockAPIKeyRepo, mockTokenService, mockMailer, mockThrottle, &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	// Confirming before enrolling fails
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	_, err := userUsecase.ConfirmMFA(context.Background(), email, "123456")
+	assert.IsType(t, errors.MFANotEnrolledError{}, err)
+
+	// Enrolling stores a pending secret
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockUserRepo.EXPECT().SetMFASecret(gomock.Any(), email, gomock.Any()).DoAndReturn(func(_ context.Context, _, secret string) (bool, error) {
+		assert.NotEmpty(t, secret)
+		user.MFASecret = secret
+		return true, nil
+	})
+
+	enrollment, err := userUsecase.EnrollMFA(context.Background(), email)
+	assert.NoError(t, err)
+	assert.Equal(t, user.MFASecret, enrollment.Secret)
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+
+	// A wrong code does not enable it
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	_, err = userUsecase.ConfirmMFA(context.Background(), email, "abcdef")
+	assert.IsType(t, errors.InvalidMFACodeError{}, err)
+
+	// A current code enables it and returns recovery codes, stored hashed
+	code, _ := utils.TOTPCode(enrollment.Secret, utils.TOTPStep(time.Now()))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockUserRepo.EXPECT().EnableMFA(gomock.Any(), email, enrollment.Secret, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _, _ string, hashes []string, step int64) (bool, error) {
+		user.MFAEnabled = true
+		user.MFARecoveryCodes = hashes
+		user.MFALastUsedStep = step
+		return true, nil
+	})
+
+	codes, err := userUsecase.ConfirmMFA(context.Background(), email, code)
+	assert.NoError(t, err)
+	assert.Len(t, codes, usecase.MFARecoveryCodeCount)
+	assert.True(t, user.MFAEnabled)
+	assert.Equal(t, utils.HashRecoveryCode(codes[0]), user.MFARecoveryCodes[0])
+	assert.NotZero(t, user.MFALastUsedStep)
+
+	// Enrolling again is refused until it is disabled
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	_, err = userUsecase.EnrollMFA(context.Background(), email)
+	assert.IsType(t, errors.MFAAlreadyEnabledError{}, err)
+}
+
+func TestDisableMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	hash, _ := utils.HashPassword("password123")
+	email := "test@example.com"
+	user := entities.User{ID: "123", Email: email, Password: hash, Role: "user", MFAEnabled: true, MFASecret: "secret", MFARecoveryCodes: []string{"hash"}}
+
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil).Times(2)
+	err := userUsecase.DisableMFA(context.Background(), email, "wrongpassword")
+	assert.IsType(t, errors.IncorrectPasswordError{}, err)
+
+	// Only the second-factor fields are cleared
+	mockUserRepo.EXPECT().ClearMFA(gomock.Any(), email).Return(nil)
+	assert.NoError(t, userUsecase.DisableMFA(context.Background(), email, "password123"))
+}
+
+func TestMFASetupRaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	userUsecase := usecase.NewUserUsecase(mockUserRepo, mocks.NewMockTaskRepository(ctrl), mocks.NewMockAPIKeyRepository(ctrl), mocks.NewMockTokenService(ctrl), mocks.NewMockMailer(ctrl), mocks.NewMockLoginThrottle(ctrl), &recordingMetrics{}, usecase.DefaultAuthSettings(), testLogger)
+
+	email := "test@example.com"
+	secret, _ := utils.GenerateTOTPSecret()
+	user := entities.User{ID: "123", Email: email, Password: "hash", Role: "user", MFASecret: secret}
+
+	// Another request confirmed MFA after this one read the account
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockUserRepo.EXPECT().SetMFASecret(gomock.Any(), email, gomock.Any()).Return(false, nil)
+	_, err := userUsecase.EnrollMFA(context.Background(), email)
+	assert.IsType(t, errors.MFAAlreadyEnabledError{}, err)
+
+	// Another request enrolled again, replacing the secret the code was checked against
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), email).Return(user, nil)
+	mockUserRepo.EXPECT().EnableMFA(gomock.Any(), email, secret, gomock.Any(), gomock.Any()).Return(false, nil)
+	_, err = userUsecase.ConfirmMFA(context.Background(), email, code)
+	assert.IsType(t, errors.InvalidMFACodeError{}, err)
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"time"
+)
+
+// renewMargin is how long before it expires a token is renewed
+const renewMargin = time.Minute
+
+// Login logs in with an email and password. The client keeps them to log in
+// again when the token is about to expire or is rejected, so long-running
+// services stay logged in. Accounts with two-factor authentication get a
+// *MFARequiredError; their tokens cannot be renewed.
+func (c *Client) Login(ctx context.Context, email, password string) error {
+	credentials := request.LoginInput{Email: email, Password: password}
+	token, err := c.login(ctx, credentials)
+	if err != nil {
+		return err
+	}
+	c.setToken(token, &credentials)
+	return nil
+}
+
+// LoginMFA finishes a login with the token from *MFARequiredError and a TOTP or
+// recovery code
+func (c *Client) LoginMFA(ctx context.Context, mfaToken, code string) error {
+	var out response.LoginResponse
+	input := request.MFALoginInput{MFAToken: mfaToken, Code: code}
+	if err := c.do(ctx, http.MethodPost, "/login/mfa", input, &out, false); err != nil {
+		return err
+	}
+	c.setToken(out.Token, nil)
+	return nil
+}
+
+// Token returns the current access token, e.g. to hand to another client
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// Promote makes the user with email an admin. The caller must be an admin.
+func (c *Client) Promote(ctx context.Context, email string) error {
+	return c.do(ctx, http.MethodPost, "/users/promote", request.PromoteInput{Email: email}, nil, true)
+}
+
+func (c *Client) login(ctx context.Context, credentials request.LoginInput) (string, error) {
+	var out response.LoginResponse
+	if err := c.do(ctx, http.MethodPost, "/login", credentials, &out, false); err != nil {
+		return "", err
+	}
+	if out.MFARequired {
+		return "", &MFARequiredError{MFAToken: out.MFAToken}
+	}
+	return out.Token, nil
+}
+
+func (c *Client) setToken(token string, credentials *request.LoginInput) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiresAt = tokenExpiry(token)
+	c.credentials = credentials
+}
+
+// authorize returns the headers that authenticate a request, renewing a token
+// that is about to expire
+func (c *Client) authorize(ctx context.Context) (http.Header, error) {
+	c.mu.Lock()
+	apiKey, token, expiresAt := c.apiKey, c.token, c.expiresAt
+	c.mu.Unlock()
+
+	if apiKey != "" {
+		return http.Header{"X-Api-Key": {apiKey}}, nil
+	}
+	if token == "" {
+		return nil, ErrNotAuthenticated
+	}
+	if !expiresAt.IsZero() && time.Until(expiresAt) < renewMargin && c.canRenew() {
+		if err := c.renew(ctx); err != nil {
+			return nil, err
+		}
+		token = c.Token()
+	}
+	return http.Header{"Authorization": {"Bearer " + token}}, nil
+}
+
+// canRenew checks if the client knows the password the token was issued for
+func (c *Client) canRenew() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.apiKey == "" && c.credentials != nil
+}
+
+// renew logs in again with the stored credentials
+func (c *Client) renew(ctx context.Context) error {
+	c.mu.Lock()
+	stored := c.credentials
+	c.mu.Unlock()
+	if stored == nil {
+		return ErrNotAuthenticated
+	}
+
+	credentials := *stored
+	token, err := c.login(ctx, credentials)
+	if err != nil {
+		return err
+	}
+	c.setToken(token, &credentials)
+	return nil
+}
+
+// tokenExpiry reads the expiry of a JWT without verifying it; the server does
+// that. It is zero when the token cannot be read.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.ExpiresAt, 0)
+}
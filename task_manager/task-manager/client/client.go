@@ -0,0 +1,192 @@
+// Package client is a Go client for the task_manager HTTP API. It speaks API v1
+// and uses the server's own request and response types.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"task_manager/Delivery/http/request"
+	"time"
+)
+
+// apiPrefix is the version of the API the client speaks
+const apiPrefix = "/api/v1"
+
+// Client calls the task_manager API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+
+	mu          sync.Mutex
+	token       string
+	expiresAt   time.Time           // zero when unknown
+	credentials *request.LoginInput // for renewing the token; nil when unknown
+	apiKey      string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with httpClient instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times idempotent requests are retried after a
+// network error or a 429, 502, 503 or 504 response, and the delay before the
+// first retry. The delay doubles with each retry unless the server sends
+// Retry-After. Zero retries turns retrying off.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryWait = wait
+	}
+}
+
+// WithToken authenticates with an access token obtained elsewhere. The client
+// cannot renew it.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+		c.expiresAt = tokenExpiry(token)
+	}
+}
+
+// WithAPIKey authenticates with an API key instead of logging in
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// New creates a client for the server at baseURL, e.g. https://tasks.example.com
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: 3,
+		retryWait:  200 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// do sends a request to path, relative to the API prefix, with in as the JSON
+// body and decodes the JSON response into out. Either may be nil.
+// Authenticated requests whose login token is rejected are sent once more after
+// logging in again, when the client knows the password.
+func (c *Client) do(ctx context.Context, method, path string, in, out any, authenticated bool) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	if !authenticated {
+		return c.send(ctx, method, path, body, nil, out)
+	}
+
+	auth, err := c.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.send(ctx, method, path, body, auth, out)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Code == "invalid_token" && c.canRenew() {
+		if err := c.renew(ctx); err != nil {
+			return err
+		}
+		if auth, err = c.authorize(ctx); err != nil {
+			return err
+		}
+		err = c.send(ctx, method, path, body, auth, out)
+	}
+	return err
+}
+
+// send sends a request, retrying idempotent ones on transient failures
+func (c *Client) send(ctx context.Context, method, path string, body []byte, auth http.Header, out any) error {
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+	wait := c.retryWait
+
+	for attempt := 0; ; attempt++ {
+		err := c.sendOnce(ctx, method, path, body, auth, out)
+		retryAfter, retry := retryable(ctx, err)
+		if !idempotent || !retry || attempt >= c.maxRetries {
+			return err
+		}
+
+		delay := wait
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, method, path string, body []byte, auth http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+apiPrefix+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range auth {
+		req.Header[name] = values
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// retryable says whether a failed request may succeed if sent again, and how
+// long the server asked to wait
+func retryable(ctx context.Context, err error) (time.Duration, bool) {
+	if err == nil || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		// The request may not have reached the server
+		return 0, true
+	}
+	switch apiErr.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
+}
@@ -0,0 +1,298 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task_manager/Delivery/http/controllers"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/routers"
+	"task_manager/Domain/entities"
+	domainErrors "task_manager/Domain/errors"
+	"task_manager/Domain/interfaces"
+	"task_manager/Infrastructure/services"
+	usecases "task_manager/Usecases"
+	"task_manager/config"
+	"task_manager/mocks"
+	"task_manager/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	adminEmail    = "admin@example.com"
+	adminPassword = "correct horse"
+	taskID        = "507f1f77bcf86cd799439011"
+)
+
+// testAPI is the real router and use cases over mocked repositories
+type testAPI struct {
+	users         *mocks.MockUserRepository
+	tasks         *mocks.MockTaskRepository
+	organizations *mocks.MockOrganizationRepository
+	tokens        interfaces.TokenService
+	http.Handler
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	api := &testAPI{
+		users:         mocks.NewMockUserRepository(ctrl),
+		tasks:         mocks.NewMockTaskRepository(ctrl),
+		organizations: mocks.NewMockOrganizationRepository(ctrl),
+	}
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+
+	tokenService, err := services.NewJWTService(nil, &config.JWTConfig{Algorithm: config.JWTAlgorithmHS256, TokenTTL: time.Hour}, "client-test-secret", logger)
+	require.NoError(t, err)
+	api.tokens = tokenService
+	throttle := services.NewMemoryLoginThrottle(100, time.Second, time.Minute, time.Minute)
+	userUsecase := usecases.NewUserUsecase(api.users, api.tasks, apiKeys, tokenService, mocks.NewMockMailer(ctrl), throttle, services.NewPrometheusMetrics(), usecases.DefaultAuthSettings(), logger)
+
+	taskUsecase := usecases.NewTaskUsecase(api.tasks)
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	routers.SetupRoutes(r,
+		controllers.NewUserController(userUsecase),
+		controllers.NewTaskController(taskUsecase),
+		controllers.NewAPIKeyController(usecases.NewAPIKeyUsecase(apiKeys, api.users, logger)),
+		nil,
+		controllers.NewGraphQLController(graphql.NewTaskManagerSchema(taskUsecase, userUsecase, graphql.Limits{})),
+		controllers.NewJobController(usecases.NewJobUsecase(services.NewMemoryJobQueue())),
+		controllers.NewOrganizationController(usecases.NewOrganizationUsecase(api.organizations, userUsecase, logger)),
+		tokenService,
+		func(c *gin.Context) { c.Next() },
+		func(c *gin.Context) { c.Next() },
+		routers.Versioning{},
+	)
+	api.Handler = r
+
+	// A cheap hash keeps logins fast
+	hash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+	admin := entities.User{ID: "user1", Name: "Admin", Email: adminEmail, Password: string(hash), Role: "admin", OrganizationID: entities.DefaultOrganizationID}
+	api.users.EXPECT().GetUserByEmail(gomock.Any(), adminEmail).Return(admin, nil).AnyTimes()
+	return api
+}
+
+func newTestClient(t *testing.T, handler http.Handler, options ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return New(server.URL, append([]Option{WithHTTPClient(server.Client())}, options...)...)
+}
+
+func TestClient_Tasks(t *testing.T) {
+	api := newTestAPI(t)
+	client := newTestClient(t, api)
+	ctx := context.Background()
+
+	require.NoError(t, client.Login(ctx, adminEmail, adminPassword))
+	assert.NotEmpty(t, client.Token())
+
+	due := time.Date(2027, time.March, 1, 9, 0, 0, 0, time.UTC)
+	task := entities.Task{ID: taskID, Title: "Write the client", DueDate: due, Status: "Pending", CreatedBy: adminEmail}
+	api.tasks.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, added entities.Task) (entities.Task, error) {
+		added.ID = taskID
+		return added, nil
+	})
+	api.tasks.EXPECT().GetTasks(gomock.Any()).Return([]entities.Task{task}, nil)
+	api.tasks.EXPECT().GetTaskByID(gomock.Any(), taskID).Return(task, nil)
+	api.tasks.EXPECT().UpdateTask(gomock.Any(), taskID, gomock.Any()).DoAndReturn(func(_ context.Context, id string, updated entities.Task) (entities.Task, error) {
+		updated.ID = id
+		return updated, nil
+	})
+	api.tasks.EXPECT().DeleteTask(gomock.Any(), taskID).Return(nil)
+
+	created, err := client.CreateTask(ctx, request.CreateTaskInput{Title: "Write the client", DueDate: due})
+	require.NoError(t, err)
+	assert.Equal(t, taskID, created.ID)
+	assert.Equal(t, adminEmail, created.CreatedBy)
+
+	tasks, err := client.ListTasks(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.True(t, due.Equal(tasks[0].DueDate))
+
+	got, err := client.GetTask(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, "Write the client", got.Title)
+
+	updated, err := client.UpdateTask(ctx, taskID, request.UpdateTaskInput{Title: "Ship the client", Status: "Completed"})
+	require.NoError(t, err)
+	assert.Equal(t, "Completed", updated.Status)
+
+	assert.NoError(t, client.DeleteTask(ctx, taskID))
+}
+
+func TestClient_Errors(t *testing.T) {
+	api := newTestAPI(t)
+	client := newTestClient(t, api)
+	ctx := context.Background()
+
+	_, err := client.ListTasks(ctx)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+
+	api.users.EXPECT().RecordFailedLogin(gomock.Any(), adminEmail).Return(1, nil)
+	err = client.Login(ctx, adminEmail, "wrong password")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.Equal(t, "invalid_credentials", apiErr.Code)
+
+	require.NoError(t, client.Login(ctx, adminEmail, adminPassword))
+	api.users.EXPECT().UpdateRole(gomock.Any(), "nobody@example.com", "admin").Return(domainErrors.UserNotFoundError{})
+	err = client.Promote(ctx, "nobody@example.com")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "user_not_found", apiErr.Code)
+
+	api.users.EXPECT().UpdateRole(gomock.Any(), "bob@example.com", "admin").Return(nil)
+	assert.NoError(t, client.Promote(ctx, "bob@example.com"))
+}
+
+func TestClient_MFA(t *testing.T) {
+	api := newTestAPI(t)
+	client := newTestClient(t, api)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	api.users.EXPECT().GetUserByEmail(gomock.Any(), "mfa@example.com").
+		Return(entities.User{Email: "mfa@example.com", Password: string(hash), Role: "user", MFAEnabled: true}, nil)
+
+	err = client.Login(context.Background(), "mfa@example.com", "secret")
+	var mfaErr *MFARequiredError
+	require.ErrorAs(t, err, &mfaErr)
+	assert.NotEmpty(t, mfaErr.MFAToken)
+	assert.Empty(t, client.Token())
+}
+
+func TestClient_RenewsToken(t *testing.T) {
+	api := newTestAPI(t)
+	client := newTestClient(t, api)
+	ctx := context.Background()
+	require.NoError(t, client.Login(ctx, adminEmail, adminPassword))
+	api.tasks.EXPECT().GetTasks(gomock.Any()).Return([]entities.Task{}, nil).Times(2)
+
+	// A rejected token is replaced by logging in again
+	client.mu.Lock()
+	client.token = "revoked"
+	client.mu.Unlock()
+	_, err := client.ListTasks(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, "revoked", client.Token())
+
+	// A token about to expire is replaced before it is sent
+	client.mu.Lock()
+	client.token = "expiring"
+	client.expiresAt = time.Now().Add(time.Second)
+	client.mu.Unlock()
+	_, err = client.ListTasks(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, "expiring", client.Token())
+
+	// Tokens from elsewhere cannot be renewed
+	other := newTestClient(t, api, WithToken("revoked"))
+	_, err = other.ListTasks(ctx)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "invalid_token", apiErr.Code)
+}
+
+func TestClient_OrganizationIsolation(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	ann := entities.User{ID: "user2", Name: "Ann", Email: "ann@acme.example", Password: string(hash), Role: "admin", OrganizationID: "org1"}
+	api.users.EXPECT().GetUserByEmail(gomock.Any(), ann.Email).Return(ann, nil).AnyTimes()
+
+	// Each account's requests only reach its own organization's tasks
+	for _, user := range []entities.User{ann, {Email: adminEmail, OrganizationID: entities.DefaultOrganizationID}} {
+		password := adminPassword
+		if user.Email == ann.Email {
+			password = "secret"
+		}
+		client := newTestClient(t, api)
+		require.NoError(t, client.Login(ctx, user.Email, password))
+
+		api.tasks.EXPECT().GetTasks(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]entities.Task, error) {
+			organizationID, ok := utils.OrganizationFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, user.OrganizationID, organizationID)
+			return []entities.Task{}, nil
+		})
+		_, err := client.ListTasks(ctx)
+		require.NoError(t, err)
+	}
+
+	// A token naming another organization is refused
+	token, err := api.tokens.GenerateToken(ann.Email, ann.Role, "org2")
+	require.NoError(t, err)
+	_, err = newTestClient(t, api, WithToken(token)).ListTasks(ctx)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "invalid_token", apiErr.Code)
+
+	// Jobs are shared by every organization, so only the default one sees them
+	client := newTestClient(t, api)
+	require.NoError(t, client.Login(ctx, ann.Email, "secret"))
+	err = client.do(ctx, http.MethodGet, "/jobs", nil, nil, true)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+}
+
+func TestClient_Retries(t *testing.T) {
+	api := newTestAPI(t)
+
+	// The first two attempts of every request fail
+	var attempts atomic.Int32
+	flaky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= 2 {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "upstream unavailable")
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+	client := newTestClient(t, flaky, WithToken("token"), WithRetries(2, time.Millisecond))
+	ctx := context.Background()
+
+	// GET is retried; the last attempt reaches the API
+	_, err := client.ListTasks(ctx)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "invalid_token", apiErr.Code)
+	assert.EqualValues(t, 3, attempts.Load())
+
+	// POST is not
+	attempts.Store(0)
+	_, err = client.CreateTask(ctx, request.CreateTaskInput{Title: "Once"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+	assert.Equal(t, "upstream unavailable", apiErr.Detail)
+	assert.EqualValues(t, 1, attempts.Load())
+
+	// Retries stop when the context ends
+	attempts.Store(0)
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.GetTask(ctx, taskID)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/Delivery/http/response"
+	"time"
+)
+
+// ErrNotAuthenticated is returned by calls that need credentials when the
+// client has none; log in first or use WithToken or WithAPIKey
+var ErrNotAuthenticated = errors.New("client: not logged in")
+
+// Error is an error response from the API. Branch on Code, which is stable; see
+// the error codes in the API documentation.
+type Error struct {
+	response.ProblemResponse
+	RetryAfter time.Duration // how long the server asked to wait, if it did
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("task_manager: %d %s: %s", e.Status, e.Title, e.Detail)
+	}
+	return fmt.Sprintf("task_manager: %s: %s", e.Code, e.Detail)
+}
+
+// MFARequiredError is returned by Login for accounts with two-factor
+// authentication. Pass MFAToken and a code to LoginMFA to finish logging in.
+type MFARequiredError struct {
+	MFAToken string
+}
+
+func (e *MFARequiredError) Error() string {
+	return "task_manager: a two-factor code is required"
+}
+
+// decodeError reads an error response. Responses that are not problem
+// documents, e.g. from a proxy, keep the start of their body as the detail.
+func decodeError(resp *http.Response) error {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+
+	apiErr := &Error{}
+	if json.Unmarshal(data, &apiErr.ProblemResponse) != nil || apiErr.Status == 0 {
+		detail := strings.TrimSpace(string(data))
+		if len(detail) > 200 {
+			detail = detail[:200]
+		}
+		apiErr.ProblemResponse = response.ToProblemResponse(resp.StatusCode, "", detail)
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+)
+
+// ListTasks returns every task
+func (c *Client) ListTasks(ctx context.Context) ([]response.TaskResponse, error) {
+	var out response.TaskListResponse
+	if err := c.do(ctx, http.MethodGet, "/tasks/", nil, &out, true); err != nil {
+		return nil, err
+	}
+	return out.Tasks, nil
+}
+
+// GetTask returns the task with id
+func (c *Client) GetTask(ctx context.Context, id string) (response.TaskResponse, error) {
+	var out response.TaskResponse
+	err := c.do(ctx, http.MethodGet, "/tasks/"+url.PathEscape(id), nil, &out, true)
+	return out, err
+}
+
+// CreateTask creates a task. The caller must be an admin.
+func (c *Client) CreateTask(ctx context.Context, input request.CreateTaskInput) (response.TaskResponse, error) {
+	var out response.TaskResponse
+	err := c.do(ctx, http.MethodPost, "/tasks/", input, &out, true)
+	return out, err
+}
+
+// UpdateTask replaces the task with id. The caller must be an admin.
+func (c *Client) UpdateTask(ctx context.Context, id string, input request.UpdateTaskInput) (response.TaskResponse, error) {
+	var out response.TaskResponse
+	err := c.do(ctx, http.MethodPut, "/tasks/"+url.PathEscape(id), input, &out, true)
+	return out, err
+}
+
+// DeleteTask deletes the task with id. The caller must be an admin.
+func (c *Client) DeleteTask(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/tasks/"+url.PathEscape(id), nil, nil, true)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"task_manager/client"
+)
+
+func (a *app) login(args []string, getenv func(string) string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	email := flags.String("email", a.settings.Email, "account email")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from standard input (or set TASKCTL_PASSWORD)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	server := a.serverURL()
+	if server == "" {
+		return errors.New("no server; pass -server")
+	}
+	if *email == "" {
+		return errors.New("no email; pass -email")
+	}
+
+	password := getenv("TASKCTL_PASSWORD")
+	if password == "" {
+		var err error
+		if password, err = a.readPassword(!*passwordStdin); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	c := client.New(server)
+	err := c.Login(ctx, *email, password)
+
+	var mfaErr *client.MFARequiredError
+	if errors.As(err, &mfaErr) {
+		fmt.Fprint(a.stderr, "Two-factor code: ")
+		code, readErr := a.readLine()
+		if readErr != nil {
+			return readErr
+		}
+		err = c.LoginMFA(ctx, mfaErr.MFAToken, code)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.settings = settings{Server: server, Email: *email, Token: c.Token()}
+	if err := saveSettings(a.configPath, a.settings); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Logged in to %s as %s\n", server, *email)
+	return nil
+}
+
+func (a *app) logout() error {
+	a.settings.Token = ""
+	if err := saveSettings(a.configPath, a.settings); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stdout, "Logged out")
+	return nil
+}
+
+// readPassword reads a line from standard input. When prompt is set it asks
+// for the password and, on a terminal, turns echo off while it is typed.
+func (a *app) readPassword(prompt bool) (string, error) {
+	if !prompt {
+		return a.readLine()
+	}
+
+	fmt.Fprint(a.stderr, "Password: ")
+	if a.interactive && setEcho(false) == nil {
+		defer func() {
+			setEcho(true)
+			fmt.Fprintln(a.stderr)
+		}()
+	}
+	return a.readLine()
+}
+
+// setEcho turns terminal echo on or off with stty, which fails harmlessly when
+// standard input is not a terminal
+func setEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+func (a *app) readLine() (string, error) {
+	line, err := a.stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no input")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
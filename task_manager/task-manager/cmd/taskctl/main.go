@@ -0,0 +1,147 @@
+// Command taskctl manages tasks from the terminal through the task_manager API.
+//
+//	taskctl login -server https://tasks.example.com -email you@example.com
+//	taskctl tasks list -status Pending
+//	taskctl tasks complete 507f1f77bcf86cd799439011
+//
+// The server and token are kept in a configuration file; see -config.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"task_manager/client"
+)
+
+const usage = `Usage: taskctl [flags] <command> [arguments]
+
+Commands:
+  login                     log in and store the token
+  logout                    forget the stored token
+  tasks list                list tasks, optionally filtered
+  tasks show <id>           show a task
+  tasks create              create a task (admin)
+  tasks update <id>         change a task (admin)
+  tasks complete <id>       mark a task completed (admin)
+  tasks delete <id>         delete a task (admin)
+  users promote <email>     make a user an admin (admin)
+
+Run "taskctl <command> -h" for the flags of a command.
+
+Flags:
+`
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "taskctl:", describe(err))
+		os.Exit(1)
+	}
+}
+
+// app is the state shared by the commands
+type app struct {
+	stdin          *bufio.Reader
+	stdout, stderr io.Writer
+	interactive    bool // stdin is the process's, maybe a terminal
+
+	configPath string
+	settings   settings
+	server     string // -server, overriding the stored server
+	output     string // "table" or "json"
+}
+
+// run parses the global flags and runs the command named in args
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) error {
+	a := &app{stdin: bufio.NewReader(stdin), stdout: stdout, stderr: stderr, interactive: stdin == os.Stdin}
+
+	flags := flag.NewFlagSet("taskctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	defaultConfig, _ := defaultConfigPath(getenv)
+	flags.StringVar(&a.configPath, "config", defaultConfig, "configuration file (env TASKCTL_CONFIG)")
+	flags.StringVar(&a.server, "server", getenv("TASKCTL_SERVER"), "API server URL (env TASKCTL_SERVER)")
+	flags.StringVar(&a.output, "o", "table", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if a.output != "table" && a.output != "json" {
+		return fmt.Errorf("unknown output format %q", a.output)
+	}
+	if a.configPath == "" {
+		return errors.New("no configuration file; set -config or TASKCTL_CONFIG")
+	}
+
+	settings, err := loadSettings(a.configPath)
+	if err != nil {
+		return err
+	}
+	a.settings = settings
+
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	switch args[0] {
+	case "login":
+		return a.login(args[1:], getenv)
+	case "logout":
+		return a.logout()
+	case "tasks":
+		return a.tasks(args[1:])
+	case "users":
+		return a.users(args[1:])
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// client returns an API client authenticated with the stored token
+func (a *app) client() (*client.Client, error) {
+	server := a.serverURL()
+	if server == "" || a.settings.Token == "" {
+		return nil, errors.New(`not logged in; run "taskctl login"`)
+	}
+	return client.New(server, client.WithToken(a.settings.Token)), nil
+}
+
+func (a *app) serverURL() string {
+	if a.server != "" {
+		return a.server
+	}
+	return a.settings.Server
+}
+
+// describe explains an error in terms of what the user can do about it
+func describe(err error) string {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case "authentication_required", "invalid_token":
+			return `the session has expired; run "taskctl login" again`
+		case "":
+			return fmt.Sprintf("server answered %d %s", apiErr.Status, apiErr.Title)
+		}
+		if len(apiErr.Errors) > 0 {
+			message := apiErr.Detail
+			for _, field := range apiErr.Errors {
+				message += fmt.Sprintf("\n  %s: %s", field.Field, field.Message)
+			}
+			return message
+		}
+		return apiErr.Detail
+	}
+	return err.Error()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"task_manager/Delivery/http/response"
+	"text/tabwriter"
+	"time"
+)
+
+func (a *app) printTasks(tasks []response.TaskResponse) error {
+	if a.output == "json" {
+		return a.printJSON(tasks)
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tDUE")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", task.ID, task.Title, task.Status, formatDue(task, now))
+	}
+	return w.Flush()
+}
+
+func (a *app) printTask(task response.TaskResponse) error {
+	if a.output == "json" {
+		return a.printJSON(task)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", task.ID)
+	fmt.Fprintf(w, "Title:\t%s\n", task.Title)
+	fmt.Fprintf(w, "Description:\t%s\n", task.Description)
+	fmt.Fprintf(w, "Status:\t%s\n", task.Status)
+	fmt.Fprintf(w, "Due:\t%s\n", formatDue(task, time.Now()))
+	fmt.Fprintf(w, "Created by:\t%s\n", task.CreatedBy)
+	return w.Flush()
+}
+
+func (a *app) printJSON(v any) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func formatDue(task response.TaskResponse, now time.Time) string {
+	if task.DueDate.IsZero() {
+		return "-"
+	}
+	due := task.DueDate.Format("2006-01-02 15:04")
+	if isOverdue(task, now) {
+		due += " (overdue)"
+	}
+	return due
+}
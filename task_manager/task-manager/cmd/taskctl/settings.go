@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// settings are what taskctl remembers between runs
+type settings struct {
+	Server string `json:"server,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// defaultConfigPath is TASKCTL_CONFIG, or taskctl/config.json in the user's
+// configuration directory
+func defaultConfigPath(getenv func(string) string) (string, error) {
+	if path := getenv("TASKCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskctl", "config.json"), nil
+}
+
+// loadSettings reads the configuration file; a missing file is empty
+func loadSettings(path string) (settings, error) {
+	var s settings
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// saveSettings writes the configuration file. It holds a token, so only the
+// user may read it.
+func saveSettings(path string, s settings) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
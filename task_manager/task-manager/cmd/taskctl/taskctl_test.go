@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "header.eyJleHAiOjQxMDI0NDQ4MDB9.signature"
+
+// fakeAPI answers the task_manager routes taskctl uses, in memory
+type fakeAPI struct {
+	tasks   map[string]response.TaskResponse
+	updated request.UpdateTaskInput
+}
+
+func (f *fakeAPI) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		var input request.LoginInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		if input.Password != "secret" {
+			w.Header().Set("Content-Type", response.ProblemContentType)
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(response.ToProblemResponse(http.StatusUnauthorized, "invalid_credentials", "Invalid email or password"))
+			return
+		}
+		json.NewEncoder(w).Encode(response.ToLoginResponse(testToken))
+	})
+	authenticated := func(handler http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.Header().Set("Content-Type", response.ProblemContentType)
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(response.ToProblemResponse(http.StatusUnauthorized, "invalid_token", "Invalid or expired token"))
+				return
+			}
+			handler(w, r)
+		}
+	}
+	mux.HandleFunc("GET /api/v1/tasks/", authenticated(func(w http.ResponseWriter, r *http.Request) {
+		list := response.TaskListResponse{}
+		for _, task := range f.tasks {
+			list.Tasks = append(list.Tasks, task)
+		}
+		json.NewEncoder(w).Encode(list)
+	}))
+	mux.HandleFunc("GET /api/v1/tasks/{id}", authenticated(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(f.tasks[r.PathValue("id")])
+	}))
+	mux.HandleFunc("PUT /api/v1/tasks/{id}", authenticated(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&f.updated))
+		task := response.TaskResponse{ID: r.PathValue("id"), Title: f.updated.Title, Description: f.updated.Description, DueDate: f.updated.DueDate, Status: f.updated.Status}
+		f.tasks[task.ID] = task
+		json.NewEncoder(w).Encode(task)
+	}))
+	mux.HandleFunc("POST /api/v1/users/promote", authenticated(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(response.ToMessageResponse("User promoted to admin"))
+	}))
+	return mux
+}
+
+// taskctl runs a command and returns its standard output
+func taskctl(t *testing.T, configPath string, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	env := map[string]string{"TASKCTL_CONFIG": configPath}
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr, func(key string) string { return env[key] })
+	return stdout.String(), err
+}
+
+func setup(t *testing.T) (*fakeAPI, string, string) {
+	api := &fakeAPI{tasks: map[string]response.TaskResponse{
+		"t1": {ID: "t1", Title: "Write docs", Status: "Pending", DueDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), CreatedBy: "admin@example.com"},
+		"t2": {ID: "t2", Title: "Ship release", Description: "v2 docs", Status: "Completed", CreatedBy: "ops@example.com"},
+	}}
+	server := httptest.NewServer(api.handler(t))
+	t.Cleanup(server.Close)
+	return api, server.URL, filepath.Join(t.TempDir(), "taskctl", "config.json")
+}
+
+func TestLogin(t *testing.T) {
+	_, server, config := setup(t)
+
+	_, err := taskctl(t, config, "", "tasks", "list")
+	assert.ErrorContains(t, err, "not logged in")
+
+	_, err = taskctl(t, config, "wrong\n", "-server", server, "login", "-email", "admin@example.com", "-password-stdin")
+	assert.Equal(t, "Invalid email or password", describe(err))
+
+	out, err := taskctl(t, config, "secret\n", "-server", server, "login", "-email", "admin@example.com", "-password-stdin")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Logged in")
+
+	// The server and token are remembered
+	stored, err := loadSettings(config)
+	require.NoError(t, err)
+	assert.Equal(t, settings{Server: server, Email: "admin@example.com", Token: testToken}, stored)
+
+	_, err = taskctl(t, config, "", "logout")
+	require.NoError(t, err)
+	_, err = taskctl(t, config, "", "tasks", "list")
+	assert.ErrorContains(t, err, "not logged in")
+}
+
+func TestTasks(t *testing.T) {
+	api, server, config := setup(t)
+	require.NoError(t, saveSettings(config, settings{Server: server, Token: testToken}))
+
+	out, err := taskctl(t, config, "", "tasks", "list", "-overdue")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Write docs")
+	assert.Contains(t, out, "(overdue)")
+	assert.NotContains(t, out, "Ship release")
+
+	out, err = taskctl(t, config, "", "-o", "json", "tasks", "list", "-search", "DOCS", "-created-by", "ops@example.com")
+	require.NoError(t, err)
+	var tasks []response.TaskResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "t2", tasks[0].ID)
+
+	// Completing keeps the other fields
+	out, err = taskctl(t, config, "", "tasks", "complete", "t1")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Completed")
+	assert.Equal(t, request.UpdateTaskInput{Title: "Write docs", DueDate: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Status: "Completed"}, api.updated)
+
+	_, err = taskctl(t, config, "", "tasks", "update", "t2", "-due", "2027-02-30")
+	assert.ErrorContains(t, err, "invalid due date")
+
+	out, err = taskctl(t, config, "", "users", "promote", "bob@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com is now an admin\n", out)
+}
+
+func TestExpiredSession(t *testing.T) {
+	_, server, config := setup(t)
+	require.NoError(t, saveSettings(config, settings{Server: server, Token: "expired"}))
+
+	_, err := taskctl(t, config, "", "tasks", "show", "t1")
+	assert.Contains(t, describe(err), `run "taskctl login" again`)
+}
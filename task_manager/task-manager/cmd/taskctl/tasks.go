@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"task_manager/Delivery/http/request"
+	"task_manager/Delivery/http/response"
+	"task_manager/Domain/entities"
+	"time"
+)
+
+func (a *app) tasks(args []string) error {
+	if len(args) == 0 {
+		return errors.New("tasks needs a subcommand: list, show, create, update, complete or delete")
+	}
+	switch args[0] {
+	case "list":
+		return a.listTasks(args[1:])
+	case "show":
+		return a.showTask(args[1:])
+	case "create":
+		return a.createTask(args[1:])
+	case "update":
+		return a.updateTask("tasks update", args[1:], "")
+	case "complete":
+		return a.updateTask("tasks complete", args[1:], "Completed")
+	case "delete":
+		return a.deleteTask(args[1:])
+	default:
+		return fmt.Errorf("unknown tasks subcommand %q", args[0])
+	}
+}
+
+// taskFilter selects tasks in list. The API returns every task, so filtering
+// happens here.
+type taskFilter struct {
+	status    string
+	search    string
+	createdBy string
+	overdue   bool
+}
+
+func (f taskFilter) matches(task response.TaskResponse, now time.Time) bool {
+	if f.status != "" && !strings.EqualFold(task.Status, f.status) {
+		return false
+	}
+	if f.createdBy != "" && !strings.EqualFold(task.CreatedBy, f.createdBy) {
+		return false
+	}
+	if f.overdue && !isOverdue(task, now) {
+		return false
+	}
+	if f.search != "" {
+		search := strings.ToLower(f.search)
+		return strings.Contains(strings.ToLower(task.Title), search) || strings.Contains(strings.ToLower(task.Description), search)
+	}
+	return true
+}
+
+func (a *app) listTasks(args []string) error {
+	var filter taskFilter
+	flags := flag.NewFlagSet("tasks list", flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.StringVar(&filter.status, "status", "", "only tasks with this status")
+	flags.StringVar(&filter.search, "search", "", "only tasks whose title or description contains this text")
+	flags.StringVar(&filter.createdBy, "created-by", "", "only tasks created by this email")
+	flags.BoolVar(&filter.overdue, "overdue", false, "only overdue tasks")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	tasks, err := c.ListTasks(context.Background())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	matching := []response.TaskResponse{}
+	for _, task := range tasks {
+		if filter.matches(task, now) {
+			matching = append(matching, task)
+		}
+	}
+	return a.printTasks(matching)
+}
+
+func (a *app) showTask(args []string) error {
+	id, err := taskID(args)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	task, err := c.GetTask(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	return a.printTask(task)
+}
+
+// taskFlags are the fields of a task that create and update accept
+type taskFlags struct {
+	flags       *flag.FlagSet
+	title       *string
+	description *string
+	due         *string
+	status      *string
+}
+
+func (a *app) newTaskFlags(name string) taskFlags {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return taskFlags{
+		flags:       flags,
+		title:       flags.String("title", "", "title"),
+		description: flags.String("description", "", "description"),
+		due:         flags.String("due", "", "due date, e.g. 2027-01-31 or 2027-01-31T17:00:00Z"),
+		status:      flags.String("status", "", "status: "+strings.Join(entities.ValidStatuses(), ", ")),
+	}
+}
+
+func (a *app) createTask(args []string) error {
+	f := a.newTaskFlags("tasks create")
+	if err := f.flags.Parse(args); err != nil {
+		return err
+	}
+	if *f.title == "" {
+		return errors.New("a task needs a -title")
+	}
+	due, err := parseDue(*f.due)
+	if err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	task, err := c.CreateTask(context.Background(), request.CreateTaskInput{
+		Title:       *f.title,
+		Description: *f.description,
+		DueDate:     due,
+		Status:      *f.status,
+	})
+	if err != nil {
+		return err
+	}
+	return a.printTask(task)
+}
+
+// updateTask changes the fields given as flags, or sets status when it is not
+// empty. The API replaces whole tasks, so the others are sent unchanged.
+func (a *app) updateTask(name string, args []string, status string) error {
+	// The ID may come before or after the flags
+	f := a.newTaskFlags(name)
+	var ids []string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ids, args = args[:1], args[1:]
+	}
+	if err := f.flags.Parse(args); err != nil {
+		return err
+	}
+	id, err := taskID(append(ids, f.flags.Args()...))
+	if err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	task, err := c.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	input := request.UpdateTaskInput{
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+		Status:      task.Status,
+	}
+	var parseErr error
+	f.flags.Visit(func(set *flag.Flag) {
+		switch set.Name {
+		case "title":
+			input.Title = *f.title
+		case "description":
+			input.Description = *f.description
+		case "due":
+			input.DueDate, parseErr = parseDue(*f.due)
+		case "status":
+			input.Status = *f.status
+		}
+	})
+	if parseErr != nil {
+		return parseErr
+	}
+	if status != "" {
+		input.Status = status
+	}
+
+	updated, err := c.UpdateTask(ctx, id, input)
+	if err != nil {
+		return err
+	}
+	return a.printTask(updated)
+}
+
+func (a *app) deleteTask(args []string) error {
+	id, err := taskID(args)
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteTask(context.Background(), id); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "Deleted task %s\n", id)
+	return nil
+}
+
+func taskID(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("expected one task ID")
+	}
+	return args[0], nil
+}
+
+// parseDue reads a due date given as a day or an RFC 3339 time. Days are due at
+// midnight UTC; empty means no due date.
+func parseDue(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if due, err := time.Parse("2006-01-02", value); err == nil {
+		return due, nil
+	}
+	due, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date %q; use e.g. 2027-01-31 or 2027-01-31T17:00:00Z", value)
+	}
+	return due, nil
+}
+
+// isOverdue mirrors entities.Task.IsOverdue for tasks read from the API
+func isOverdue(task response.TaskResponse, now time.Time) bool {
+	return !task.DueDate.IsZero() && now.After(task.DueDate) && task.Status != "Completed"
+}
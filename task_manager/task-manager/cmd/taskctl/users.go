@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (a *app) users(args []string) error {
+	if len(args) != 2 || args[0] != "promote" {
+		return errors.New("usage: taskctl users promote <email>")
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if err := c.Promote(context.Background(), args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "%s is now an admin\n", args[1])
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateLayout is the short form accepted for dates; RFC 3339 timestamps also work
+const dateLayout = "2006-01-02"
+
+// APIConfig holds API versioning and request replay configuration. Dates are
+// written as 2006-01-02 or as RFC 3339 timestamps; an empty date is unset.
+type APIConfig struct {
+	// The unversioned paths, e.g. /tasks, redirect to /api/v1 while clients
+	// move over. LegacySunset announces when the redirects will be removed.
+	LegacyRedirects bool   `yaml:"legacy_redirects" env:"API_LEGACY_REDIRECTS"`
+	LegacySunset    string `yaml:"legacy_sunset" env:"API_LEGACY_SUNSET"`
+
+	// Set V1Deprecated once clients should move to v2, and V1Sunset to the
+	// date v1 will be removed
+	V1Deprecated string `yaml:"v1_deprecated" env:"API_V1_DEPRECATED"`
+	V1Sunset     string `yaml:"v1_sunset" env:"API_V1_SUNSET"`
+
+	// How long the response to a request sent with an Idempotency-Key is
+	// replayed to retries
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+// ParseDate parses a date setting. An empty value is the zero time.
+func ParseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use e.g. 2027-01-31)", value)
+	}
+	return date, nil
+}
@@ -1,38 +1,31 @@
 package config
 
-import "strconv"
+// DefaultJWTSecret is the placeholder secret used when JWT_SECRET is unset.
+// It is public, so production refuses to start with it.
+const DefaultJWTSecret = "your_jwt_secret_key"
+
+// Deployment environments
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
 
 // AppConfig holds application configuration
 type AppConfig struct {
-	Port      string
-	JWTSecret string
-	Environment string
-}
-
-// NewAppConfig creates a new application configuration
-func NewAppConfig() *AppConfig {
-	return &AppConfig{
-		Port:        getEnv("PORT", "8080"),
-		JWTSecret:   getEnv("JWT_SECRET", "your_jwt_secret_key"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-	}
+	Port                     string `yaml:"port" env:"PORT"`
+	JWTSecret                string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	Environment              string `yaml:"environment" env:"ENVIRONMENT"`
+	BaseURL                  string `yaml:"base_url" env:"APP_BASE_URL"`
+	PasswordResetURL         string `yaml:"password_reset_url" env:"PASSWORD_RESET_URL"` // the client's page for choosing a new password
+	RequireEmailVerification bool   `yaml:"require_email_verification" env:"REQUIRE_EMAIL_VERIFICATION"`
 }
 
 // IsDevelopment checks if running in development mode
 func (c *AppConfig) IsDevelopment() bool {
-	return c.Environment == "development"
+	return c.Environment == EnvironmentDevelopment
 }
 
 // IsProduction checks if running in production mode
 func (c *AppConfig) IsProduction() bool {
-	return c.Environment == "production"
+	return c.Environment == EnvironmentProduction
 }
-
-// GetPort returns the port as integer
-func (c *AppConfig) GetPort() int {
-	port, err := strconv.Atoi(c.Port)
-	if err != nil {
-		return 8080
-	}
-	return port
-} 
\ No newline at end of file
@@ -0,0 +1,299 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the configuration file when the -config flag is not given
+const ConfigFileEnv = "CONFIG_FILE"
+
+// Config is the application's complete configuration. It is loaded once at
+// startup and the sections are handed to the components that need them.
+type Config struct {
+	App      AppConfig      `yaml:"app"`
+	Server   ServerConfig   `yaml:"server"`
+	API      APIConfig      `yaml:"api"`
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	GraphQL  GraphQLConfig  `yaml:"graphql"`
+	Database DatabaseConfig `yaml:"database"`
+	Jobs     JobsConfig     `yaml:"jobs"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Logging  LoggingConfig  `yaml:"logging"`
+	Mail     MailConfig     `yaml:"mail"`
+	Security SecurityConfig `yaml:"security"`
+	OIDC     OIDCConfig     `yaml:"oidc"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	return &Config{
+		App: AppConfig{
+			Port:        "8080",
+			JWTSecret:   DefaultJWTSecret,
+			Environment: EnvironmentDevelopment,
+			BaseURL:     "http://localhost:8080",
+		},
+		Server: ServerConfig{
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		API: APIConfig{
+			LegacyRedirects: true,
+			IdempotencyTTL:  24 * time.Hour,
+		},
+		GRPC: GRPCConfig{
+			Port: "9090",
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      8,
+			MaxComplexity: 2500,
+		},
+		Database: DatabaseConfig{
+			URI:              "mongodb://localhost:27017",
+			Database:         "task_management_system",
+			Timeout:          10 * time.Second,
+			OperationTimeout: 5 * time.Second,
+		},
+		Jobs: JobsConfig{
+			Concurrency:     2,
+			PollInterval:    time.Second,
+			Lease:           5 * time.Minute,
+			MaxAttempts:     5,
+			RetryBackoff:    30 * time.Second,
+			MaxRetryBackoff: time.Hour,
+			Retention:       7 * 24 * time.Hour,
+			PurgeSchedule:   "0 3 * * *",
+		},
+		JWT: JWTConfig{
+			Algorithm:   JWTAlgorithmEdDSA,
+			TokenTTL:    24 * time.Hour,
+			KeyRotation: 30 * 24 * time.Hour,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Mail: MailConfig{
+			Driver: MailDriverLog,
+			Host:   "localhost",
+			Port:   "587",
+			From:   "no-reply@task-manager.local",
+		},
+		Security: SecurityConfig{
+			MaxFailedLogins:    5,
+			LockoutDuration:    time.Minute,
+			MaxLockoutDuration: time.Hour,
+
+			ClientFailureThreshold: 10,
+			ClientBaseDelay:        time.Second,
+			ClientMaxDelay:         5 * time.Minute,
+			ClientFailureWindow:    15 * time.Minute,
+
+			AuthRateLimit:  20,
+			AuthRateWindow: time.Minute,
+
+			MFAIssuer:       "Task Manager",
+			MFAChallengeTTL: 5 * time.Minute,
+		},
+		OIDC: OIDCConfig{
+			Scopes:        []string{"openid", "email", "profile"},
+			AutoProvision: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TraceExporterNone,
+			ServiceName: "task-manager",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load builds the configuration in layers, each overriding the one before: the
+// defaults, a YAML file named by the -config flag or CONFIG_FILE, environment
+// variables read through lookupEnv, and command-line flags. Every setting has a
+// flag named after its path in the file, e.g. -server.read_timeout=20s.
+//
+// The result is not validated; call Validate before using it. Parsing -help
+// returns flag.ErrHelp.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	// Flags are parsed first to find the file, and applied last
+	var overrides []func() error
+	flags := flag.NewFlagSet("task-manager", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML configuration file (env "+ConfigFileEnv+")")
+	for _, s := range settings {
+		override := func(value string) error {
+			overrides = append(overrides, func() error {
+				if err := s.set(value); err != nil {
+					return fmt.Errorf("-%s: %w", s.path, err)
+				}
+				return nil
+			})
+			return nil
+		}
+		if s.value.Kind() == reflect.Bool {
+			flags.BoolFunc(s.path, s.usage(), override)
+		} else {
+			flags.Func(s.path, s.usage(), override)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if *configFile == "" {
+		*configFile, _ = lookupEnv(ConfigFileEnv)
+	}
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		// Empty variables are treated as unset, so a blank line in .env changes nothing
+		if value, ok := lookupEnv(s.env); ok && value != "" {
+			if err := s.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, override := range overrides {
+		if err := override(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if cfg.OIDC.RedirectURL == "" {
+		cfg.OIDC.RedirectURL = strings.TrimRight(cfg.App.BaseURL, "/") + "/auth/oidc/callback"
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the settings present in a YAML file. Unknown keys are
+// rejected so a misspelt setting is not silently ignored.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// setting is a single configurable value, found by walking the Config struct
+type setting struct {
+	path   string // dotted path in the file, also the flag name
+	env    string
+	secret string // "true" hides the value when printed, "url" only a URL's password
+	value  reflect.Value
+}
+
+func (s setting) usage() string {
+	return "env " + s.env
+}
+
+// settings lists every value in the configuration in declaration order
+func (c *Config) settings() []setting {
+	var settings []setting
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		sectionName := yamlName(sections.Type().Field(i))
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			settings = append(settings, setting{
+				path:   sectionName + "." + yamlName(field),
+				env:    field.Tag.Get("env"),
+				secret: field.Tag.Get("secret"),
+				value:  section.Field(j),
+			})
+		}
+	}
+	return settings
+}
+
+// lookup returns the setting that holds field, a pointer into c
+func (c *Config) lookup(field any) setting {
+	for _, s := range c.settings() {
+		if s.value.Addr().Interface() == field {
+			return s
+		}
+	}
+	panic("config: unknown field")
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set parses a value given as text in an environment variable or flag
+func (s setting) set(value string) error {
+	switch {
+	case s.value.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (use e.g. 30s, 5m or 1h)", value)
+		}
+		s.value.SetInt(int64(duration))
+	case s.value.Kind() == reflect.String:
+		s.value.SetString(value)
+	case s.value.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		s.value.SetBool(parsed)
+	case s.value.Kind() == reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		s.value.SetInt(int64(parsed))
+	case s.value.Kind() == reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		s.value.SetFloat(parsed)
+	case s.value.Kind() == reflect.Slice:
+		// Lists are separated by commas or spaces
+		items := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", s.value.Type())
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// envMap looks variables up in a map instead of the process environment
+func envMap(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil, envMap(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "8080", cfg.App.Port)
+	assert.Equal(t, 5*time.Second, cfg.Database.OperationTimeout)
+	assert.Equal(t, "http://localhost:8080/auth/oidc/callback", cfg.OIDC.RedirectURL)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoad_Layers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+app:
+  port: "9000"
+  base_url: https://tasks.example.com
+server:
+  read_timeout: 20s
+jwt:
+  ttl: 2h
+api:
+  legacy_sunset: 2027-01-31
+`), 0o600))
+
+	cfg, err := Load([]string{"-jwt.ttl=30m", "-oidc.auto_provision=false"}, envMap(map[string]string{
+		ConfigFileEnv:          file,
+		"PORT":                 "9100",
+		"LOG_LEVEL":            "",
+		"OIDC_ALLOWED_DOMAINS": "example.com, example.org",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "9100", cfg.App.Port, "env overrides the file")
+	assert.Equal(t, 30*time.Minute, cfg.JWT.TokenTTL, "flags override the file")
+	assert.Equal(t, 20*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, "info", cfg.Logging.Level, "empty variables are ignored")
+	assert.Equal(t, []string{"example.com", "example.org"}, cfg.OIDC.AllowedDomains)
+	assert.False(t, cfg.OIDC.AutoProvision)
+	assert.Equal(t, "https://tasks.example.com/auth/oidc/callback", cfg.OIDC.RedirectURL)
+	assert.Equal(t, "2027-01-31", cfg.API.LegacySunset)
+	assert.True(t, cfg.API.LegacyRedirects)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(nil, envMap(map[string]string{"JWT_TTL": "forever", "AUTH_RATE_LIMIT": "many"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_TTL")
+	assert.Contains(t, err.Error(), "AUTH_RATE_LIMIT")
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("server:\n  read_timout: 20s\n"), 0o600))
+	_, err = Load([]string{"-config", file}, envMap(nil))
+	assert.ErrorContains(t, err, "read_timout")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.App.Environment = EnvironmentProduction
+	cfg.Database.URI = "localhost:27017"
+	cfg.App.PasswordResetURL = "/reset-password"
+	cfg.Server.WriteTimeout = 0
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy.internal"}
+	cfg.API.V1Deprecated = "2027-03-01"
+	cfg.API.V1Sunset = "2027-01-01"
+	cfg.API.LegacySunset = "next year"
+	cfg.GRPC.Port = cfg.App.Port
+	cfg.GraphQL.MaxComplexity = 0
+	cfg.API.IdempotencyTTL = 0
+	cfg.Database.TaskCacheTTL = -time.Second
+	cfg.Jobs.MaxAttempts = 0
+	cfg.Jobs.PurgeSchedule = "daily"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "app.jwt_secret (JWT_SECRET)")
+	assert.Contains(t, err.Error(), "database.uri (MONGODB_URI)")
+	assert.Contains(t, err.Error(), "app.password_reset_url (PASSWORD_RESET_URL)")
+	assert.Contains(t, err.Error(), "server.write_timeout (HTTP_WRITE_TIMEOUT)")
+	assert.Contains(t, err.Error(), `server.trusted_proxies (TRUSTED_PROXIES): must be IP addresses or CIDR ranges, got "proxy.internal"`)
+	assert.Contains(t, err.Error(), "api.v1_sunset (API_V1_SUNSET): must not be before 2027-03-01")
+	assert.Contains(t, err.Error(), "api.legacy_sunset (API_LEGACY_SUNSET): invalid date")
+	assert.Contains(t, err.Error(), "grpc.port (GRPC_PORT): must differ from the HTTP port")
+	assert.Contains(t, err.Error(), "graphql.max_complexity (GRAPHQL_MAX_COMPLEXITY): must be at least 1")
+	assert.Contains(t, err.Error(), "api.idempotency_ttl (IDEMPOTENCY_TTL)")
+	assert.Contains(t, err.Error(), "database.task_cache_ttl (TASK_CACHE_TTL): must not be negative")
+	assert.Contains(t, err.Error(), "jobs.max_attempts (JOBS_MAX_ATTEMPTS): must be at least 1")
+	assert.Contains(t, err.Error(), "jobs.purge_schedule (JOBS_PURGE_SCHEDULE)")
+}
+
+func TestWriteRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.App.JWTSecret = "super-secret-value"
+	cfg.Database.URI = "mongodb://admin:hunter2@db:27017"
+	cfg.Mail.Password = "smtp-password"
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.WriteRedacted(&out))
+
+	printed := out.String()
+	assert.NotContains(t, printed, "super-secret-value")
+	assert.NotContains(t, printed, "hunter2")
+	assert.NotContains(t, printed, "smtp-password")
+	assert.Contains(t, printed, "read_timeout: 15s")
+
+	// The output is a valid configuration file
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, out.Bytes(), 0o600))
+	_, err := Load([]string{"-config", file}, envMap(nil))
+	assert.NoError(t, err)
+}
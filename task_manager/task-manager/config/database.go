@@ -2,38 +2,39 @@ package config
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var TaskCollection *mongo.Collection
 var UserCollection *mongo.Collection
+var APIKeyCollection *mongo.Collection
+var SigningKeyCollection *mongo.Collection
+var IdempotencyCollection *mongo.Collection
+var JobCollection *mongo.Collection
+var OrganizationCollection *mongo.Collection
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	URI      string
-	Database string
-	Timeout  time.Duration
+	URI              string        `yaml:"uri" env:"MONGODB_URI" secret:"url"` // may carry credentials
+	Database         string        `yaml:"name" env:"DATABASE_NAME"`
+	Timeout          time.Duration `yaml:"connect_timeout" env:"MONGODB_CONNECT_TIMEOUT"`     // connecting at startup
+	OperationTimeout time.Duration `yaml:"operation_timeout" env:"MONGODB_OPERATION_TIMEOUT"` // each query or write made by the repositories
+	TaskCacheTTL     time.Duration `yaml:"task_cache_ttl" env:"TASK_CACHE_TTL"`               // how long tasks read are kept in memory; 0 turns the cache off
 }
 
-// NewDatabaseConfig creates a new database configuration
-func NewDatabaseConfig() *DatabaseConfig {
-	return &DatabaseConfig{
-		URI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		Database: getEnv("DATABASE_NAME", "task_management_system"),
-		Timeout:  10 * time.Second,
-	}
-}
-
-// ConnectToMongo connects to MongoDB with configuration
-func ConnectToMongo() *mongo.Client {
-	config := NewDatabaseConfig()
-	
+// ConnectToMongo connects to MongoDB with configuration. The monitors are
+// notified of every command the client runs.
+func ConnectToMongo(config *DatabaseConfig, monitors ...*event.CommandMonitor) *mongo.Client {
 	clientOptions := options.Client().ApplyURI(config.URI)
+	if len(monitors) > 0 {
+		clientOptions.SetMonitor(combineMonitors(monitors))
+	}
 	client, err := mongo.NewClient(clientOptions)
 	if err != nil {
 		log.Fatal("MongoDB client creation error:", err)
@@ -52,11 +53,66 @@ func ConnectToMongo() *mongo.Client {
 		log.Fatal("MongoDB ping error:", err)
 	}
 
-	fmt.Println("✅ Connected to MongoDB!")
+	log.Printf("Connected to MongoDB database %s", config.Database)
 
 	db := client.Database(config.Database)
 	TaskCollection = db.Collection("tasks")
 	UserCollection = db.Collection("users")
+	APIKeyCollection = db.Collection("api_keys")
+	SigningKeyCollection = db.Collection("signing_keys")
+	IdempotencyCollection = db.Collection("idempotency_keys")
+	JobCollection = db.Collection("jobs")
+	OrganizationCollection = db.Collection("organizations")
+
+	ensureIndexes(ctx, db)
 
 	return client
-} 
\ No newline at end of file
+}
+
+// indexes are the indexes each collection needs, created at startup.
+// Creating an index that already exists does nothing.
+var indexes = map[string][]mongo.IndexModel{
+	// Sign-in finds accounts by email in every organization
+	"users": {{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)}},
+	// Each request authenticated with an API key looks it up by hash
+	"api_keys": {{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)}},
+	// Records are deleted once expires_at passes
+	"idempotency_keys": {{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)}},
+}
+
+// ensureIndexes creates indexes. A failure, e.g. a unique index over
+// duplicates, is logged rather than keeping the server from starting.
+func ensureIndexes(ctx context.Context, db *mongo.Database) {
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			log.Printf("Creating indexes on %s failed: %v", collection, err)
+		}
+	}
+}
+
+// combineMonitors fans command events out to several monitors
+func combineMonitors(monitors []*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Started != nil {
+					monitor.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, monitor := range monitors {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, monitor := range monitors {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, evt)
+				}
+			}
+		},
+	}
+}
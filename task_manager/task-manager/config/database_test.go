@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// createdIndexes runs ensureIndexes against a mock deployment and returns the
+// indexes it asked for, by collection
+func createdIndexes(t *testing.T) map[string][]bson.Raw {
+	created := map[string][]bson.Raw{}
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("ensureIndexes", func(mt *mtest.T) {
+		for range indexes {
+			mt.AddMockResponses(mtest.CreateSuccessResponse())
+		}
+		ensureIndexes(mt.Context(), mt.DB)
+
+		for _, event := range mt.GetAllStartedEvents() {
+			require.Equal(mt, "createIndexes", event.CommandName)
+			collection := event.Command.Lookup("createIndexes").StringValue()
+			values, err := event.Command.Lookup("indexes").Array().Values()
+			require.NoError(mt, err)
+			for _, value := range values {
+				created[collection] = append(created[collection], value.Document())
+			}
+		}
+	})
+	return created
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	created := createdIndexes(t)
+
+	// Expired idempotency records are removed by MongoDB
+	require.Len(t, created["idempotency_keys"], 1)
+	ttl := created["idempotency_keys"][0]
+	assert.Equal(t, int32(1), ttl.Lookup("key", "expires_at").Int32())
+	assert.Equal(t, int32(0), ttl.Lookup("expireAfterSeconds").Int32())
+
+	// Emails are unique across organizations
+	require.Len(t, created["users"], 1)
+	assert.Equal(t, int32(1), created["users"][0].Lookup("key", "email").Int32())
+	assert.True(t, created["users"][0].Lookup("unique").Boolean())
+
+	// API keys are found by the hash of the presented secret
+	require.Len(t, created["api_keys"], 1)
+	assert.Equal(t, int32(1), created["api_keys"][0].Lookup("key", "key_hash").Int32())
+	assert.True(t, created["api_keys"][0].Lookup("unique").Boolean())
+}
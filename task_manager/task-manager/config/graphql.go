@@ -0,0 +1,7 @@
+package config
+
+// GraphQLConfig holds the limits applied to GraphQL queries before they run
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth" env:"GRAPHQL_MAX_DEPTH"`           // how deeply selections may nest
+	MaxComplexity int `yaml:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY"` // fields a query may resolve, counting list items
+}
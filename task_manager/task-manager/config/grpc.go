@@ -0,0 +1,12 @@
+package config
+
+// GRPCConfig holds the gRPC server settings. The gRPC API serves the same
+// tasks and users as the HTTP one on a port of its own.
+type GRPCConfig struct {
+	Port string `yaml:"port" env:"GRPC_PORT"` // empty turns the gRPC server off
+}
+
+// Enabled reports whether the gRPC server should be started
+func (c GRPCConfig) Enabled() bool {
+	return c.Port != ""
+}
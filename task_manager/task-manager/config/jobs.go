@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// JobsConfig holds background job settings. Jobs are stored in MongoDB, so
+// any instance's workers may run them.
+type JobsConfig struct {
+	Concurrency  int           `yaml:"concurrency" env:"JOBS_CONCURRENCY"`     // workers in this instance; 0 runs none here
+	PollInterval time.Duration `yaml:"poll_interval" env:"JOBS_POLL_INTERVAL"` // how often idle workers look for due jobs
+	Lease        time.Duration `yaml:"lease" env:"JOBS_LEASE"`                 // longest an attempt may run before another worker takes it over
+
+	// Failed attempts are retried after RetryBackoff, doubling each time up to
+	// MaxRetryBackoff, until MaxAttempts have run
+	MaxAttempts     int           `yaml:"max_attempts" env:"JOBS_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"JOBS_RETRY_BACKOFF"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" env:"JOBS_MAX_RETRY_BACKOFF"`
+
+	// Finished jobs are deleted once older than Retention, on the cron
+	// schedule PurgeSchedule (UTC)
+	Retention     time.Duration `yaml:"retention" env:"JOBS_RETENTION"`
+	PurgeSchedule string        `yaml:"purge_schedule" env:"JOBS_PURGE_SCHEDULE"`
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// Supported access token signing algorithms
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+// JWTConfig holds access token signing configuration
+type JWTConfig struct {
+	// Algorithm is EdDSA or RS256 for rotated key pairs published at
+	// /.well-known/jwks.json, or HS256 to sign with JWT_SECRET
+	Algorithm string        `yaml:"algorithm" env:"JWT_ALGORITHM"`
+	TokenTTL  time.Duration `yaml:"ttl" env:"JWT_TTL"`
+	// KeyRotation is how long a key signs new tokens before it is replaced.
+	// Replaced keys keep validating until the tokens they signed expire.
+	KeyRotation time.Duration `yaml:"key_rotation" env:"JWT_KEY_ROTATION"`
+}
+
+// UsesKeyPairs checks if tokens are signed with rotated asymmetric keys
+func (c *JWTConfig) UsesKeyPairs() bool {
+	return c.Algorithm == JWTAlgorithmRS256 || c.Algorithm == JWTAlgorithmEdDSA
+}
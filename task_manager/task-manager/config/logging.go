@@ -0,0 +1,7 @@
+package config
+
+// LoggingConfig holds application log configuration
+type LoggingConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`   // "debug", "info", "warn" or "error"
+	Format string `yaml:"format" env:"LOG_FORMAT"` // "json" or "text"
+}
@@ -0,0 +1,23 @@
+package config
+
+// Mail drivers
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverLog  = "log"
+)
+
+// MailConfig holds outgoing email configuration
+type MailConfig struct {
+	Driver   string `yaml:"driver" env:"MAIL_DRIVER"` // "smtp" or "log"
+	Host     string `yaml:"smtp_host" env:"SMTP_HOST"`
+	Port     string `yaml:"smtp_port" env:"SMTP_PORT"`
+	Username string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	Password string `yaml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"MAIL_FROM"`
+	LogFile  string `yaml:"log_file" env:"MAIL_LOG_FILE"` // used by the log driver; empty writes to the application log
+}
+
+// UsesSMTP checks if mail should be delivered over SMTP
+func (c *MailConfig) UsesSMTP() bool {
+	return c.Driver == MailDriverSMTP
+}
@@ -0,0 +1,22 @@
+package config
+
+// OIDCConfig holds single sign-on configuration for an external OpenID Connect
+// identity provider. SSO is off unless an issuer is configured.
+type OIDCConfig struct {
+	IssuerURL    string `yaml:"issuer_url" env:"OIDC_ISSUER_URL"`
+	ClientID     string `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret string `yaml:"client_secret" env:"OIDC_CLIENT_SECRET" secret:"true"`
+	// RedirectURL defaults to the callback route under the application's base URL
+	RedirectURL string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes      []string `yaml:"scopes" env:"OIDC_SCOPES"`
+
+	// AutoProvision creates accounts for identities that match no existing user
+	AutoProvision bool `yaml:"auto_provision" env:"OIDC_AUTO_PROVISION"`
+	// AllowedDomains restricts SSO to these email domains; empty allows any
+	AllowedDomains []string `yaml:"allowed_domains" env:"OIDC_ALLOWED_DOMAINS"`
+}
+
+// Enabled checks if single sign-on is configured
+func (c *OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values when the configuration is printed
+const redacted = "[REDACTED]"
+
+// WriteRedacted writes the configuration as YAML in the layout Load reads, with
+// secrets replaced. Credentials embedded in a URL are hidden the same way.
+func (c *Config) WriteRedacted(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	var section *yaml.Node
+	var sectionName string
+
+	for _, s := range c.settings() {
+		name, key, _ := strings.Cut(s.path, ".")
+		if section == nil || name != sectionName {
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			sectionName = name
+			root.Content = append(root.Content, stringNode(name), section)
+		}
+		section.Content = append(section.Content, stringNode(key), s.node())
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// node renders the setting's value, hiding it if it is a secret
+func (s setting) node() *yaml.Node {
+	if s.secret == "true" && s.value.String() != "" {
+		return stringNode(redacted)
+	}
+	if s.secret == "url" {
+		if parsed, err := url.Parse(s.value.String()); err == nil {
+			return stringNode(parsed.Redacted())
+		}
+		return stringNode(redacted)
+	}
+
+	switch {
+	case s.value.Type() == durationType:
+		return stringNode(time.Duration(s.value.Int()).String())
+	case s.value.Kind() == reflect.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatBool(s.value.Bool())}
+	case s.value.Kind() == reflect.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatInt(s.value.Int(), 10)}
+	case s.value.Kind() == reflect.Float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(s.value.Float(), 'g', -1, 64)}
+	case s.value.Kind() == reflect.Slice:
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < s.value.Len(); i++ {
+			list.Content = append(list.Content, stringNode(s.value.Index(i).String()))
+		}
+		return list
+	default:
+		return stringNode(s.value.String())
+	}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// SecurityConfig holds login throttling and rate limiting configuration
+type SecurityConfig struct {
+	// Per-account lockout; zero failures disables it
+	MaxFailedLogins    int           `yaml:"max_failed_logins" env:"LOGIN_MAX_FAILURES"`
+	LockoutDuration    time.Duration `yaml:"lockout" env:"LOGIN_LOCKOUT"`
+	MaxLockoutDuration time.Duration `yaml:"max_lockout" env:"LOGIN_MAX_LOCKOUT"`
+
+	// Per-client (IP) progressive delays
+	ClientFailureThreshold int           `yaml:"client_failure_threshold" env:"LOGIN_CLIENT_FAILURE_THRESHOLD"`
+	ClientBaseDelay        time.Duration `yaml:"client_base_delay" env:"LOGIN_CLIENT_BASE_DELAY"`
+	ClientMaxDelay         time.Duration `yaml:"client_max_delay" env:"LOGIN_CLIENT_MAX_DELAY"`
+	ClientFailureWindow    time.Duration `yaml:"client_failure_window" env:"LOGIN_CLIENT_FAILURE_WINDOW"`
+
+	// Request rate limit applied to the public authentication routes
+	AuthRateLimit  int           `yaml:"auth_rate_limit" env:"AUTH_RATE_LIMIT"`
+	AuthRateWindow time.Duration `yaml:"auth_rate_window" env:"AUTH_RATE_WINDOW"`
+
+	// Two-factor authentication
+	MFAIssuer       string        `yaml:"mfa_issuer" env:"MFA_ISSUER"`
+	MFAChallengeTTL time.Duration `yaml:"mfa_challenge_ttl" env:"MFA_CHALLENGE_TTL"`
+}
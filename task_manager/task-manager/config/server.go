@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// ServerConfig holds HTTP server timeouts and proxy settings
+type ServerConfig struct {
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"` // whole request, including the body
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"` // from the end of the request headers to the end of the response
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`   // keep-alive connections between requests
+
+	// ShutdownTimeout bounds how long in-flight requests and background work are
+	// given to finish once the server is asked to stop
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	// TrustedProxies are the addresses or CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header gives the client's address; empty trusts none
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
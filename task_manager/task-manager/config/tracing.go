@@ -0,0 +1,22 @@
+package config
+
+// Trace exporters
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+	TraceExporterOTLP   = "otlp"
+)
+
+// TracingConfig holds OpenTelemetry tracing configuration. The variable names
+// follow the OpenTelemetry SDK conventions.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`        // "none", "stdout" or "otlp"
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"` // OTLP/HTTP collector URL; empty uses the exporter's default
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"` // share of new traces recorded; incoming sampled traces are always kept
+}
+
+// Enabled checks if traces are exported
+func (c *TracingConfig) Enabled() bool {
+	return c.Exporter != TraceExporterNone && c.Exporter != ""
+}
@@ -1,11 +0,0 @@
-package config
-
-import "os"
-
-// getEnv gets environment variable with fallback
-func getEnv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return fallback
-} 
\ No newline at end of file
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"task_manager/utils"
+	"time"
+)
+
+// Validate reports every setting that is missing, malformed or unsafe to run
+// with. Each problem names the setting's file key and environment variable.
+func (c *Config) Validate() error {
+	v := validator{config: c}
+
+	v.port(&c.App.Port)
+	v.oneOf(&c.App.Environment, EnvironmentDevelopment, EnvironmentProduction)
+	v.url(&c.App.BaseURL)
+	if c.App.PasswordResetURL != "" {
+		v.url(&c.App.PasswordResetURL)
+	}
+	if c.App.JWTSecret == "" {
+		v.fail(&c.App.JWTSecret, "must be set")
+	} else if c.App.IsProduction() && (c.App.JWTSecret == DefaultJWTSecret || len(c.App.JWTSecret) < 32) {
+		v.fail(&c.App.JWTSecret, "must be set to a random value of at least 32 characters in production")
+	}
+
+	v.positive(&c.Server.ReadTimeout)
+	v.positive(&c.Server.ReadHeaderTimeout)
+	v.positive(&c.Server.WriteTimeout)
+	v.positive(&c.Server.IdleTimeout)
+	v.positive(&c.Server.ShutdownTimeout)
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				v.fail(&c.Server.TrustedProxies, "must be IP addresses or CIDR ranges, got %q", proxy)
+			}
+		}
+	}
+
+	v.date(&c.API.LegacySunset)
+	v1Deprecated := v.date(&c.API.V1Deprecated)
+	v1Sunset := v.date(&c.API.V1Sunset)
+	if !v1Sunset.IsZero() && v1Sunset.Before(v1Deprecated) {
+		v.fail(&c.API.V1Sunset, "must not be before %s", c.API.V1Deprecated)
+	}
+	v.positive(&c.API.IdempotencyTTL)
+
+	if c.GRPC.Enabled() {
+		v.port(&c.GRPC.Port)
+		if c.GRPC.Port == c.App.Port {
+			v.fail(&c.GRPC.Port, "must differ from the HTTP port")
+		}
+	}
+
+	if c.GraphQL.MaxDepth < 1 {
+		v.fail(&c.GraphQL.MaxDepth, "must be at least 1")
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		v.fail(&c.GraphQL.MaxComplexity, "must be at least 1")
+	}
+
+	if !strings.HasPrefix(c.Database.URI, "mongodb://") && !strings.HasPrefix(c.Database.URI, "mongodb+srv://") {
+		v.fail(&c.Database.URI, "must be a mongodb:// or mongodb+srv:// connection string")
+	}
+	v.required(&c.Database.Database)
+	v.positive(&c.Database.Timeout)
+	v.positive(&c.Database.OperationTimeout)
+	if c.Database.TaskCacheTTL < 0 {
+		v.fail(&c.Database.TaskCacheTTL, "must not be negative")
+	}
+
+	v.notNegative(&c.Jobs.Concurrency)
+	v.positive(&c.Jobs.PollInterval)
+	v.positive(&c.Jobs.Lease)
+	if c.Jobs.MaxAttempts < 1 {
+		v.fail(&c.Jobs.MaxAttempts, "must be at least 1")
+	}
+	v.positive(&c.Jobs.RetryBackoff)
+	v.positive(&c.Jobs.MaxRetryBackoff)
+	v.positive(&c.Jobs.Retention)
+	if _, err := utils.ParseCron(c.Jobs.PurgeSchedule); err != nil {
+		v.fail(&c.Jobs.PurgeSchedule, "%v", err)
+	}
+
+	v.oneOf(&c.JWT.Algorithm, JWTAlgorithmEdDSA, JWTAlgorithmRS256, JWTAlgorithmHS256)
+	v.positive(&c.JWT.TokenTTL)
+	v.positive(&c.JWT.KeyRotation)
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		v.fail(&c.Logging.Level, "must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+	v.oneOf(&c.Logging.Format, "json", "text")
+
+	v.oneOf(&c.Mail.Driver, MailDriverSMTP, MailDriverLog)
+	if c.Mail.UsesSMTP() {
+		v.required(&c.Mail.Host)
+		v.port(&c.Mail.Port)
+	}
+	v.required(&c.Mail.From)
+
+	v.notNegative(&c.Security.MaxFailedLogins)
+	v.positive(&c.Security.LockoutDuration)
+	v.positive(&c.Security.MaxLockoutDuration)
+	v.notNegative(&c.Security.ClientFailureThreshold)
+	v.positive(&c.Security.ClientBaseDelay)
+	v.positive(&c.Security.ClientMaxDelay)
+	v.positive(&c.Security.ClientFailureWindow)
+	if c.Security.AuthRateLimit < 1 {
+		v.fail(&c.Security.AuthRateLimit, "must be at least 1")
+	}
+	v.positive(&c.Security.AuthRateWindow)
+	v.required(&c.Security.MFAIssuer)
+	v.positive(&c.Security.MFAChallengeTTL)
+
+	if c.OIDC.Enabled() {
+		v.url(&c.OIDC.IssuerURL)
+		v.required(&c.OIDC.ClientID)
+		v.url(&c.OIDC.RedirectURL)
+	}
+
+	v.oneOf(&c.Tracing.Exporter, TraceExporterNone, TraceExporterStdout, TraceExporterOTLP)
+	if c.Tracing.Endpoint != "" {
+		v.url(&c.Tracing.Endpoint)
+	}
+	v.required(&c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail(&c.Tracing.SampleRatio, "must be between 0 and 1")
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects the problems found in a configuration
+type validator struct {
+	config *Config
+	errs   []error
+}
+
+// fail records a problem with field, a pointer into the configuration
+func (v *validator) fail(field any, format string, args ...any) {
+	s := v.config.lookup(field)
+	v.errs = append(v.errs, fmt.Errorf("%s (%s): %s", s.path, s.env, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) required(field *string) {
+	if strings.TrimSpace(*field) == "" {
+		v.fail(field, "must be set")
+	}
+}
+
+func (v *validator) oneOf(field *string, allowed ...string) {
+	for _, value := range allowed {
+		if *field == value {
+			return
+		}
+	}
+	v.fail(field, "must be one of %s, got %q", strings.Join(allowed, ", "), *field)
+}
+
+func (v *validator) url(field *string) {
+	parsed, err := url.Parse(*field)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.fail(field, "must be an http or https URL, got %q", *field)
+	}
+}
+
+func (v *validator) port(field *string) {
+	port, err := strconv.Atoi(*field)
+	if err != nil || port < 1 || port > 65535 {
+		v.fail(field, "must be a port number, got %q", *field)
+	}
+}
+
+func (v *validator) positive(field *time.Duration) {
+	if *field <= 0 {
+		v.fail(field, "must be a positive duration, got %s", *field)
+	}
+}
+
+func (v *validator) date(field *string) time.Time {
+	date, err := ParseDate(*field)
+	if err != nil {
+		v.fail(field, "%v", err)
+	}
+	return date
+}
+
+func (v *validator) notNegative(field *int) {
+	if *field < 0 {
+		v.fail(field, "must not be negative")
+	}
+}
@@ -2,17 +2,32 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"task_manager/config"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	grpcdelivery "task_manager/Delivery/grpc"
 	"task_manager/Delivery/http/controllers"
+	"task_manager/Delivery/http/graphql"
+	"task_manager/Delivery/http/middleware"
+	"task_manager/Delivery/http/openapi"
 	"task_manager/Delivery/http/routers"
+	"task_manager/Delivery/http/server"
+	"task_manager/Domain/interfaces"
 	"task_manager/Infrastructure/database/repositories"
 	"task_manager/Infrastructure/services"
 	usecases "task_manager/Usecases"
+	"task_manager/config"
 
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 func main() {
@@ -22,42 +37,235 @@ func main() {
 		log.Println("No .env file found")
 	}
 
-	// Load application configuration
-	appConfig := config.NewAppConfig()
+	// "config print" shows the effective configuration; anything else serves
+	args := os.Args[1:]
+	printConfig := len(args) >= 2 && args[0] == "config" && args[1] == "print"
+	if printConfig {
+		args = args[2:]
+	}
 
-	// Connect to MongoDB using config
-	client := config.ConnectToMongo()
-	defer func() {
-		if err := client.Disconnect(context.Background()); err != nil {
-			log.Printf("Error disconnecting from MongoDB: %v", err)
+	cfg, err := config.Load(args, os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
+	invalid := cfg.Validate()
+
+	if printConfig {
+		if err := cfg.WriteRedacted(os.Stdout); err != nil {
+			log.Fatalf("Failed to print configuration: %v", err)
 		}
-	}()
+		if invalid != nil {
+			fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", invalid)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if invalid != nil {
+		fmt.Fprintf(os.Stderr, "Refusing to start with an invalid configuration:\n%v\n", invalid)
+		os.Exit(1)
+	}
+	serve(cfg)
+}
+
+// serve runs the API until the process is asked to stop
+func serve(cfg *config.Config) {
+	// Structured logging; the standard logger is routed through it as well
+	logger := services.NewLogger(&cfg.Logging, os.Stdout)
+	slog.SetDefault(logger)
+	appConfig := &cfg.App
+
+	// Tracing and metrics are set up first so MongoDB commands are covered from the start
+	tracingConfig := &cfg.Tracing
+	shutdownTracing, err := services.SetupTracing(context.Background(), tracingConfig, os.Stdout)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	metrics := services.NewPrometheusMetrics()
+
+	// Connect to MongoDB using config
+	dbConfig := &cfg.Database
+	client := config.ConnectToMongo(dbConfig, metrics.MongoMonitor(), otelmongo.NewMonitor())
 
 	// Initialize repositories with clean architecture
-	userRepo := repositories.NewUserRepository(config.UserCollection)
-	taskRepo := repositories.NewTaskRepository(config.TaskCollection)
+	userRepo := repositories.NewUserRepository(config.UserCollection, dbConfig.OperationTimeout)
+	taskRepo := repositories.NewTaskRepository(config.TaskCollection, dbConfig.OperationTimeout, logger)
+	if dbConfig.TaskCacheTTL > 0 {
+		taskRepo = repositories.NewCachedTaskRepository(taskRepo, dbConfig.TaskCacheTTL)
+	}
+	apiKeyRepo := repositories.NewAPIKeyRepository(config.APIKeyCollection, dbConfig.OperationTimeout)
+	signingKeyRepo := repositories.NewSigningKeyRepository(config.SigningKeyCollection, dbConfig.OperationTimeout)
+	idempotencyRepo := repositories.NewIdempotencyRepository(config.IdempotencyCollection, dbConfig.OperationTimeout)
+	jobRepo := repositories.NewJobRepository(config.JobCollection, dbConfig.OperationTimeout)
+	organizationRepo := repositories.NewOrganizationRepository(config.OrganizationCollection, dbConfig.OperationTimeout)
+	metrics.CollectTaskCounts(taskRepo)
 
 	// Initialize services
-	tokenService := services.NewJWTService()
+	tokenService, err := services.NewJWTService(signingKeyRepo, &cfg.JWT, appConfig.JWTSecret, logger)
+	if err != nil {
+		log.Fatalf("Failed to set up token signing: %v", err)
+	}
+
+	mailConfig := &cfg.Mail
+	var mailer interfaces.Mailer
+	if mailConfig.UsesSMTP() {
+		mailer = services.NewSMTPMailer(mailConfig)
+	} else {
+		mailer, err = services.NewLogMailer(mailConfig.LogFile)
+		if err != nil {
+			log.Fatalf("Failed to set up mailer: %v", err)
+		}
+	}
+
+	// Email is sent by background jobs, so it is retried if delivery fails
+	jobRunner := services.NewJobRunner(jobRepo, &cfg.Jobs, logger)
+	jobRunner.Register(services.NewSendEmailJob(mailer, []byte(appConfig.JWTSecret)))
+	jobRunner.Register(services.NewPurgeJobsJob(jobRepo, cfg.Jobs.Retention))
+	if err := jobRunner.Schedule(cfg.Jobs.PurgeSchedule, services.PurgeJobsJobType, nil); err != nil {
+		log.Fatalf("Failed to schedule the job purge: %v", err)
+	}
+	mailer = services.NewQueuedMailer(jobRunner, []byte(appConfig.JWTSecret))
+
+	securityConfig := &cfg.Security
+	loginThrottle := services.NewMemoryLoginThrottle(
+		securityConfig.ClientFailureThreshold,
+		securityConfig.ClientBaseDelay,
+		securityConfig.ClientMaxDelay,
+		securityConfig.ClientFailureWindow,
+	)
+
+	authSettings := usecases.DefaultAuthSettings()
+	authSettings.RequireEmailVerification = appConfig.RequireEmailVerification
+	authSettings.AppBaseURL = appConfig.BaseURL
+	authSettings.PasswordResetURL = appConfig.PasswordResetURL
+	authSettings.MaxFailedLogins = securityConfig.MaxFailedLogins
+	authSettings.LockoutDuration = securityConfig.LockoutDuration
+	authSettings.MaxLockoutDuration = securityConfig.MaxLockoutDuration
+	authSettings.MFAIssuer = securityConfig.MFAIssuer
+	authSettings.MFAChallengeTTL = securityConfig.MFAChallengeTTL
+
+	oidcConfig := &cfg.OIDC
+	authSettings.SSOAutoProvision = oidcConfig.AutoProvision
+	authSettings.SSOAllowedDomains = oidcConfig.AllowedDomains
 
 	// Initialize use cases with clean dependencies
-	userUsecase := usecases.NewUserUsecase(userRepo, tokenService)
+	userUsecase := usecases.NewUserUsecase(userRepo, taskRepo, apiKeyRepo, tokenService, mailer, loginThrottle, metrics, authSettings, logger)
 	taskUsecase := usecases.NewTaskUsecase(taskRepo)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(apiKeyRepo, userRepo, logger)
+	jobUsecase := usecases.NewJobUsecase(jobRepo)
+	organizationUsecase := usecases.NewOrganizationUsecase(organizationRepo, userUsecase, logger)
 
 	// Initialize controllers
 	userController := controllers.NewUserController(userUsecase)
 	taskController := controllers.NewTaskController(taskUsecase)
+	apiKeyController := controllers.NewAPIKeyController(apiKeyUsecase)
+	jobController := controllers.NewJobController(jobUsecase)
+	organizationController := controllers.NewOrganizationController(organizationUsecase)
+	graphqlController := controllers.NewGraphQLController(graphql.NewTaskManagerSchema(taskUsecase, userUsecase, graphql.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	}))
+
+	// Single sign-on is only offered when an identity provider is configured
+	var ssoController *controllers.SSOController
+	if oidcConfig.Enabled() {
+		identityProvider, err := services.NewOIDCProvider(oidcConfig)
+		if err != nil {
+			log.Fatalf("Failed to set up single sign-on: %v", err)
+		}
+		ssoUsecase := usecases.NewSSOUsecase(identityProvider, userUsecase, logger)
+		ssoController = controllers.NewSSOController(ssoUsecase, strings.HasPrefix(appConfig.BaseURL, "https://"))
+	}
+
+	// Setup Gin router with request IDs, tracing, access logs, panic recovery and
+	// problem+json error responses
+	r, err := server.NewEngine(&cfg.Server)
+	if err != nil {
+		log.Fatalf("Failed to set up trusted proxies: %v", err)
+	}
+	r.Use(
+		middleware.RequestIDMiddleware(),
+		middleware.TracingMiddleware(tracingConfig.ServiceName),
+		middleware.LoggingMiddleware(logger),
+		middleware.MetricsMiddleware(metrics),
+		middleware.RecoveryMiddleware(logger),
+		middleware.ErrorMiddleware(),
+	)
+
+	// Probes and metrics for the platform
+	healthController := controllers.NewHealthController(controllers.HealthCheck{
+		Name:  "mongodb",
+		Check: func(ctx context.Context) error { return client.Ping(ctx, readpref.Primary()) },
+	})
+	routers.SetupOperationalRoutes(r, healthController, metrics.Handler())
 
-	// Setup Gin router
-	r := gin.Default()
+	// API versions; the dates were checked by Validate
+	v1Deprecated, _ := config.ParseDate(cfg.API.V1Deprecated)
+	v1Sunset, _ := config.ParseDate(cfg.API.V1Sunset)
+	legacySunset, _ := config.ParseDate(cfg.API.LegacySunset)
+	versioning := routers.Versioning{
+		V1:              middleware.Deprecation{Since: v1Deprecated, Sunset: v1Sunset},
+		LegacyRedirects: cfg.API.LegacyRedirects,
+		LegacySunset:    legacySunset,
+	}
+
+	// OpenAPI document and documentation page
+	operations := openapi.Operations
+	if !v1Deprecated.IsZero() {
+		operations = openapi.Deprecate(operations, routers.V1Prefix)
+	}
+	docsController, err := controllers.NewDocsController(operations)
+	if err != nil {
+		log.Fatalf("Failed to build the OpenAPI document: %v", err)
+	}
+	routers.SetupDocsRoutes(r, docsController)
 
 	// Setup routes with clean middleware
-	routers.SetupRoutes(r, userController, taskController, tokenService)
+	authRateLimit := middleware.RateLimitMiddleware(securityConfig.AuthRateLimit, securityConfig.AuthRateWindow)
+	idempotency := middleware.IdempotencyMiddleware(idempotencyRepo, cfg.API.IdempotencyTTL, []byte(appConfig.JWTSecret))
+	routers.SetupRoutes(r, userController, taskController, apiKeyController, ssoController, graphqlController, jobController, organizationController, tokenService, authRateLimit, idempotency, versioning)
 
 	// Start server with config
 	port := fmt.Sprintf(":%s", appConfig.Port)
-	log.Printf("Starting server on port %s\n", appConfig.Port)
-	if err := r.Run(port); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+	srv := server.New(port, r, &cfg.Server, logger)
+
+	// Resources close after requests drain, in reverse: job workers, MongoDB,
+	// then buffered spans
+	srv.OnShutdown("tracing", shutdownTracing)
+	srv.OnShutdown("mongodb", client.Disconnect)
+	jobRunner.Start()
+	srv.OnShutdown("jobs", jobRunner.Shutdown)
+
+	// The gRPC API listens on its own port and stops before MongoDB closes
+	if cfg.GRPC.Enabled() {
+		grpcListener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		grpcServer := grpcdelivery.NewServer(taskUsecase, userUsecase, tokenService, logger)
+		go func() {
+			logger.Info("Starting gRPC server", "port", cfg.GRPC.Port)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				logger.Error("gRPC server stopped", "error", err)
+			}
+		}()
+		srv.OnShutdown("grpc", grpcdelivery.Shutdown(grpcServer))
+	}
+
+	// Stop on SIGINT or SIGTERM; a second signal exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	logger.Info("Starting server", "port", appConfig.Port, "environment", appConfig.Environment)
+	if err := srv.Run(ctx); err != nil {
+		log.Fatalf("Server stopped with an error: %v", err)
 	}
-} 
\ No newline at end of file
+}
@@ -4,6 +4,7 @@
 package mocks
 
 import (
+	"context"
 	"reflect"
 	"task_manager/Domain/entities"
 
@@ -34,74 +35,105 @@ func (m *MockTaskRepository) EXPECT() *MockTaskRepositoryMockRecorder {
 }
 
 // GetTasks mocks base method.
-func (m *MockTaskRepository) GetTasks() []entities.Task {
+func (m *MockTaskRepository) GetTasks(ctx context.Context) ([]entities.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasks")
+	ret := m.ctrl.Call(m, "GetTasks", ctx)
 	ret0, _ := ret[0].([]entities.Task)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetTasks indicates an expected call of GetTasks.
-func (mr *MockTaskRepositoryMockRecorder) GetTasks() *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) GetTasks(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskRepository)(nil).GetTasks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockTaskRepository)(nil).GetTasks), ctx)
 }
 
 // GetTaskByID mocks base method.
-func (m *MockTaskRepository) GetTaskByID(id string) (entities.Task, error) {
+func (m *MockTaskRepository) GetTaskByID(ctx context.Context, id string) (entities.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskByID", id)
+	ret := m.ctrl.Call(m, "GetTaskByID", ctx, id)
 	ret0, _ := ret[0].(entities.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTaskByID indicates an expected call of GetTaskByID.
-func (mr *MockTaskRepositoryMockRecorder) GetTaskByID(id interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) GetTaskByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskRepository)(nil).GetTaskByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByID", reflect.TypeOf((*MockTaskRepository)(nil).GetTaskByID), ctx, id)
 }
 
 // AddTask mocks base method.
-func (m *MockTaskRepository) AddTask(task entities.Task) (entities.Task, error) {
+func (m *MockTaskRepository) AddTask(ctx context.Context, task entities.Task) (entities.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddTask", task)
+	ret := m.ctrl.Call(m, "AddTask", ctx, task)
 	ret0, _ := ret[0].(entities.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddTask indicates an expected call of AddTask.
-func (mr *MockTaskRepositoryMockRecorder) AddTask(task interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) AddTask(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTask", reflect.TypeOf((*MockTaskRepository)(nil).AddTask), task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTask", reflect.TypeOf((*MockTaskRepository)(nil).AddTask), ctx, task)
 }
 
 // UpdateTask mocks base method.
-func (m *MockTaskRepository) UpdateTask(id string, updatedTask entities.Task) (entities.Task, error) {
+func (m *MockTaskRepository) UpdateTask(ctx context.Context, id string, updatedTask entities.Task) (entities.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTask", id, updatedTask)
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, id, updatedTask)
 	ret0, _ := ret[0].(entities.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTask indicates an expected call of UpdateTask.
-func (mr *MockTaskRepositoryMockRecorder) UpdateTask(id, updatedTask interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) UpdateTask(ctx, id, updatedTask interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskRepository)(nil).UpdateTask), id, updatedTask)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskRepository)(nil).UpdateTask), ctx, id, updatedTask)
 }
 
 // DeleteTask mocks base method.
-func (m *MockTaskRepository) DeleteTask(id string) error {
+func (m *MockTaskRepository) DeleteTask(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", id)
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockTaskRepositoryMockRecorder) DeleteTask(id interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskRepository)(nil).DeleteTask), ctx, id)
+}
+
+// ReassignTasks mocks base method.
+func (m *MockTaskRepository) ReassignTasks(ctx context.Context, fromEmail, toEmail string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignTasks", ctx, fromEmail, toEmail)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignTasks indicates an expected call of ReassignTasks.
+func (mr *MockTaskRepositoryMockRecorder) ReassignTasks(ctx, fromEmail, toEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignTasks", reflect.TypeOf((*MockTaskRepository)(nil).ReassignTasks), ctx, fromEmail, toEmail)
+}
+
+// CountByStatus mocks base method.
+func (m *MockTaskRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatus", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockTaskRepositoryMockRecorder) CountByStatus(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskRepository)(nil).DeleteTask), id)
-} 
\ No newline at end of file
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockTaskRepository)(nil).CountByStatus), ctx)
+}
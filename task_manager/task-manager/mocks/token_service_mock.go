@@ -5,6 +5,8 @@ package mocks
 
 import (
 	"reflect"
+	"task_manager/Domain/interfaces"
+	"time"
 
 	"github.com/golang/mock/gomock"
 )
@@ -33,28 +35,29 @@ func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
 }
 
 // GenerateToken mocks base method.
-func (m *MockTokenService) GenerateToken(email, role string) (string, error) {
+func (m *MockTokenService) GenerateToken(email, role, organizationID string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", email, role)
+	ret := m.ctrl.Call(m, "GenerateToken", email, role, organizationID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockTokenServiceMockRecorder) GenerateToken(email, role interface{}) *gomock.Call {
+func (mr *MockTokenServiceMockRecorder) GenerateToken(email, role, organizationID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockTokenService)(nil).GenerateToken), email, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockTokenService)(nil).GenerateToken), email, role, organizationID)
 }
 
 // ValidateToken mocks base method.
-func (m *MockTokenService) ValidateToken(token string) (string, string, error) {
+func (m *MockTokenService) ValidateToken(token string) (string, string, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ValidateToken", token)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret2, _ := ret[2].(string)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // ValidateToken indicates an expected call of ValidateToken.
@@ -76,4 +79,50 @@ func (m *MockTokenService) ExtractClaims(token string) (map[string]interface{},
 func (mr *MockTokenServiceMockRecorder) ExtractClaims(token interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtractClaims", reflect.TypeOf((*MockTokenService)(nil).ExtractClaims), token)
-} 
\ No newline at end of file
+}
+
+// GenerateActionToken mocks base method.
+func (m *MockTokenService) GenerateActionToken(email, purpose, fingerprint string, ttl time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateActionToken", email, purpose, fingerprint, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateActionToken indicates an expected call of GenerateActionToken.
+func (mr *MockTokenServiceMockRecorder) GenerateActionToken(email, purpose, fingerprint, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateActionToken", reflect.TypeOf((*MockTokenService)(nil).GenerateActionToken), email, purpose, fingerprint, ttl)
+}
+
+// ValidateActionToken mocks base method.
+func (m *MockTokenService) ValidateActionToken(token, purpose string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateActionToken", token, purpose)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ValidateActionToken indicates an expected call of ValidateActionToken.
+func (mr *MockTokenServiceMockRecorder) ValidateActionToken(token, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateActionToken", reflect.TypeOf((*MockTokenService)(nil).ValidateActionToken), token, purpose)
+}
+
+// JWKS mocks base method.
+func (m *MockTokenService) JWKS() ([]interfaces.JSONWebKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].([]interfaces.JSONWebKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockTokenServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockTokenService)(nil).JWKS))
+}
@@ -4,8 +4,10 @@
 package mocks
 
 import (
+	"context"
 	"reflect"
 	"task_manager/Domain/entities"
+	"time"
 
 	"github.com/golang/mock/gomock"
 )
@@ -34,75 +36,370 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 }
 
 // GetUserByEmail mocks base method.
-func (m *MockUserRepository) GetUserByEmail(email string) (entities.User, error) {
+func (m *MockUserRepository) GetUserByEmail(ctx context.Context, email string) (entities.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", email)
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
 	ret0, _ := ret[0].(entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(email interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id string) (entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserRepositoryMockRecorder) GetUserByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByID), ctx, id)
+}
+
+// GetUserByExternalID mocks base method.
+func (m *MockUserRepository) GetUserByExternalID(ctx context.Context, issuer, subject string) (entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByExternalID", ctx, issuer, subject)
+	ret0, _ := ret[0].(entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByExternalID indicates an expected call of GetUserByExternalID.
+func (mr *MockUserRepositoryMockRecorder) GetUserByExternalID(ctx, issuer, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByExternalID", reflect.TypeOf((*MockUserRepository)(nil).GetUserByExternalID), ctx, issuer, subject)
+}
+
+// ListUsers mocks base method.
+func (m *MockUserRepository) ListUsers(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, filter)
+	ret0, _ := ret[0].([]entities.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUserRepositoryMockRecorder) ListUsers(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepository)(nil).ListUsers), ctx, filter)
 }
 
 // CountDocuments mocks base method.
-func (m *MockUserRepository) CountDocuments(email string) (int64, error) {
+func (m *MockUserRepository) CountDocuments(ctx context.Context, email string) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountDocuments", email)
+	ret := m.ctrl.Call(m, "CountDocuments", ctx, email)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountDocuments indicates an expected call of CountDocuments.
-func (mr *MockUserRepositoryMockRecorder) CountDocuments(email interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) CountDocuments(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDocuments", reflect.TypeOf((*MockUserRepository)(nil).CountDocuments), ctx, email)
+}
+
+// CountUsers mocks base method.
+func (m *MockUserRepository) CountUsers(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsers", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsers indicates an expected call of CountUsers.
+func (mr *MockUserRepositoryMockRecorder) CountUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsers", reflect.TypeOf((*MockUserRepository)(nil).CountUsers), ctx)
+}
+
+// EmailInUse mocks base method.
+func (m *MockUserRepository) EmailInUse(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailInUse", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailInUse indicates an expected call of EmailInUse.
+func (mr *MockUserRepositoryMockRecorder) EmailInUse(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDocuments", reflect.TypeOf((*MockUserRepository)(nil).CountDocuments), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailInUse", reflect.TypeOf((*MockUserRepository)(nil).EmailInUse), ctx, email)
 }
 
 // InsertOne mocks base method.
-func (m *MockUserRepository) InsertOne(user entities.User) (entities.User, error) {
+func (m *MockUserRepository) InsertOne(ctx context.Context, user entities.User) (entities.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertOne", user)
+	ret := m.ctrl.Call(m, "InsertOne", ctx, user)
 	ret0, _ := ret[0].(entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertOne indicates an expected call of InsertOne.
-func (mr *MockUserRepositoryMockRecorder) InsertOne(user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) InsertOne(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOne", reflect.TypeOf((*MockUserRepository)(nil).InsertOne), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOne", reflect.TypeOf((*MockUserRepository)(nil).InsertOne), ctx, user)
 }
 
 // UpdateOne mocks base method.
-func (m *MockUserRepository) UpdateOne(email string, user entities.User) (entities.User, error) {
+func (m *MockUserRepository) UpdateOne(ctx context.Context, email string, user entities.User) (entities.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateOne", email, user)
+	ret := m.ctrl.Call(m, "UpdateOne", ctx, email, user)
 	ret0, _ := ret[0].(entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateOne indicates an expected call of UpdateOne.
-func (mr *MockUserRepositoryMockRecorder) UpdateOne(email, user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) UpdateOne(ctx, email, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockUserRepository)(nil).UpdateOne), email, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockUserRepository)(nil).UpdateOne), ctx, email, user)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, email, name, newEmail string) (entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, email, name, newEmail)
+	ret0, _ := ret[0].(entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepositoryMockRecorder) UpdateProfile(ctx, email, name, newEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepository)(nil).UpdateProfile), ctx, email, name, newEmail)
+}
+
+// SetPassword mocks base method.
+func (m *MockUserRepository) SetPassword(ctx context.Context, email, currentHash, newHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", ctx, email, currentHash, newHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockUserRepositoryMockRecorder) SetPassword(ctx, email, currentHash, newHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockUserRepository)(nil).SetPassword), ctx, email, currentHash, newHash)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailVerified(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailVerified), ctx, email)
+}
+
+// LinkIdentity mocks base method.
+func (m *MockUserRepository) LinkIdentity(ctx context.Context, email, issuer, subject string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIdentity", ctx, email, issuer, subject)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkIdentity indicates an expected call of LinkIdentity.
+func (mr *MockUserRepositoryMockRecorder) LinkIdentity(ctx, email, issuer, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIdentity", reflect.TypeOf((*MockUserRepository)(nil).LinkIdentity), ctx, email, issuer, subject)
 }
 
 // UpdateRole mocks base method.
-func (m *MockUserRepository) UpdateRole(email, role string) error {
+func (m *MockUserRepository) UpdateRole(ctx context.Context, email, role string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRole", email, role)
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, email, role)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateRole indicates an expected call of UpdateRole.
-func (mr *MockUserRepositoryMockRecorder) UpdateRole(email, role interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) UpdateRole(ctx, email, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), ctx, email, role)
+}
+
+// SetDisabled mocks base method.
+func (m *MockUserRepository) SetDisabled(ctx context.Context, email string, disabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", ctx, email, disabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockUserRepositoryMockRecorder) SetDisabled(ctx, email, disabled interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), email, role)
-} 
\ No newline at end of file
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockUserRepository)(nil).SetDisabled), ctx, email, disabled)
+}
+
+// CountActiveAdmins mocks base method.
+func (m *MockUserRepository) CountActiveAdmins(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveAdmins", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveAdmins indicates an expected call of CountActiveAdmins.
+func (mr *MockUserRepositoryMockRecorder) CountActiveAdmins(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveAdmins", reflect.TypeOf((*MockUserRepository)(nil).CountActiveAdmins), ctx)
+}
+
+// RecordFailedLogin mocks base method.
+func (m *MockUserRepository) RecordFailedLogin(ctx context.Context, email string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedLogin", ctx, email)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailedLogin indicates an expected call of RecordFailedLogin.
+func (mr *MockUserRepositoryMockRecorder) RecordFailedLogin(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedLogin", reflect.TypeOf((*MockUserRepository)(nil).RecordFailedLogin), ctx, email)
+}
+
+// LockUntil mocks base method.
+func (m *MockUserRepository) LockUntil(ctx context.Context, email string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUntil", ctx, email, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUntil indicates an expected call of LockUntil.
+func (mr *MockUserRepositoryMockRecorder) LockUntil(ctx, email, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUntil", reflect.TypeOf((*MockUserRepository)(nil).LockUntil), ctx, email, until)
+}
+
+// ResetFailedLogins mocks base method.
+func (m *MockUserRepository) ResetFailedLogins(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedLogins", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedLogins indicates an expected call of ResetFailedLogins.
+func (mr *MockUserRepositoryMockRecorder) ResetFailedLogins(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLogins", reflect.TypeOf((*MockUserRepository)(nil).ResetFailedLogins), ctx, email)
+}
+
+// MarkMFAStepUsed mocks base method.
+func (m *MockUserRepository) MarkMFAStepUsed(ctx context.Context, email string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMFAStepUsed", ctx, email, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkMFAStepUsed indicates an expected call of MarkMFAStepUsed.
+func (mr *MockUserRepositoryMockRecorder) MarkMFAStepUsed(ctx, email, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMFAStepUsed", reflect.TypeOf((*MockUserRepository)(nil).MarkMFAStepUsed), ctx, email, step)
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockUserRepository) ConsumeRecoveryCode(ctx context.Context, email, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", ctx, email, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockUserRepositoryMockRecorder) ConsumeRecoveryCode(ctx, email, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockUserRepository)(nil).ConsumeRecoveryCode), ctx, email, codeHash)
+}
+
+// SetMFASecret mocks base method.
+func (m *MockUserRepository) SetMFASecret(ctx context.Context, email, secret string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMFASecret", ctx, email, secret)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMFASecret indicates an expected call of SetMFASecret.
+func (mr *MockUserRepositoryMockRecorder) SetMFASecret(ctx, email, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMFASecret", reflect.TypeOf((*MockUserRepository)(nil).SetMFASecret), ctx, email, secret)
+}
+
+// EnableMFA mocks base method.
+func (m *MockUserRepository) EnableMFA(ctx context.Context, email, secret string, recoveryCodeHashes []string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMFA", ctx, email, secret, recoveryCodeHashes, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableMFA indicates an expected call of EnableMFA.
+func (mr *MockUserRepositoryMockRecorder) EnableMFA(ctx, email, secret, recoveryCodeHashes, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMFA", reflect.TypeOf((*MockUserRepository)(nil).EnableMFA), ctx, email, secret, recoveryCodeHashes, step)
+}
+
+// ClearMFA mocks base method.
+func (m *MockUserRepository) ClearMFA(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearMFA", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearMFA indicates an expected call of ClearMFA.
+func (mr *MockUserRepositoryMockRecorder) ClearMFA(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearMFA", reflect.TypeOf((*MockUserRepository)(nil).ClearMFA), ctx, email)
+}
+
+// DeleteOne mocks base method.
+func (m *MockUserRepository) DeleteOne(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOne", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOne indicates an expected call of DeleteOne.
+func (mr *MockUserRepositoryMockRecorder) DeleteOne(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOne", reflect.TypeOf((*MockUserRepository)(nil).DeleteOne), ctx, email)
+}
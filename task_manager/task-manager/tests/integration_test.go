@@ -4,16 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
-	"testing"
-	"time"
-	"task_manager/config"
 	"task_manager/Delivery/http/controllers"
+	"task_manager/Delivery/http/middleware"
 	"task_manager/Infrastructure/database/repositories"
 	"task_manager/Infrastructure/services"
 	usecases "task_manager/Usecases"
+	"task_manager/config"
+	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -31,20 +34,36 @@ func setupTestApp() *gin.Engine {
 		}
 	}
 
+	cfg, err := config.Load(nil, os.LookupEnv)
+	if err != nil {
+		panic("Error loading configuration: " + err.Error())
+	}
+
 	// Connect to test database
-	_ = config.ConnectToMongo()
+	dbConfig := &cfg.Database
+	_ = config.ConnectToMongo(dbConfig)
 	// Note: We don't disconnect here because the client needs to stay connected for the tests
 	// The client will be cleaned up when the test process ends
 
+	logger := slog.New(slog.DiscardHandler)
+
 	// Initialize repositories
-	userRepo := repositories.NewUserRepository(config.UserCollection)
-	taskRepo := repositories.NewTaskRepository(config.TaskCollection)
+	userRepo := repositories.NewUserRepository(config.UserCollection, dbConfig.OperationTimeout)
+	taskRepo := repositories.NewTaskRepository(config.TaskCollection, dbConfig.OperationTimeout, logger)
+	apiKeyRepo := repositories.NewAPIKeyRepository(config.APIKeyCollection, dbConfig.OperationTimeout)
+	signingKeyRepo := repositories.NewSigningKeyRepository(config.SigningKeyCollection, dbConfig.OperationTimeout)
 
 	// Initialize services
-	tokenService := services.NewJWTService()
+	tokenService, err := services.NewJWTService(signingKeyRepo, &cfg.JWT, cfg.App.JWTSecret, logger)
+	if err != nil {
+		panic("Error setting up token signing: " + err.Error())
+	}
+
+	mailer, _ := services.NewLogMailer("")
 
 	// Initialize use cases
-	userUsecase := usecases.NewUserUsecase(userRepo, tokenService)
+	loginThrottle := services.NewMemoryLoginThrottle(10, time.Second, time.Minute, 15*time.Minute)
+	userUsecase := usecases.NewUserUsecase(userRepo, taskRepo, apiKeyRepo, tokenService, mailer, loginThrottle, services.NewPrometheusMetrics(), usecases.DefaultAuthSettings(), logger)
 	taskUsecase := usecases.NewTaskUsecase(taskRepo)
 
 	// Initialize controllers
@@ -54,7 +73,8 @@ func setupTestApp() *gin.Engine {
 	// Setup Gin router
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	
+	r.Use(middleware.ErrorMiddleware())
+
 	// Setup routes
 	r.POST("/register", userController.Register)
 	r.POST("/login", userController.Login)
@@ -70,7 +90,7 @@ func TestRegisterIntegration(t *testing.T) {
 	// Test data with unique email using timestamp
 	timestamp := time.Now().Unix()
 	uniqueEmail := fmt.Sprintf("test%d@example.com", timestamp)
-	
+
 	userData := map[string]interface{}{
 		"name":     "Test User",
 		"email":    uniqueEmail,
@@ -104,7 +124,7 @@ func TestLoginIntegration(t *testing.T) {
 	// First register a user with unique email
 	timestamp := time.Now().Unix()
 	uniqueEmail := fmt.Sprintf("test%d@example.com", timestamp)
-	
+
 	userData := map[string]interface{}{
 		"name":     "Test User",
 		"email":    uniqueEmail,
@@ -153,4 +173,4 @@ func TestGetTasksIntegration(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.NotNil(t, response["tasks"])
-} 
\ No newline at end of file
+}
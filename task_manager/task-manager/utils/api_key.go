@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix marks API keys so they can be told apart from JWTs and spotted by secret scanners
+const APIKeyPrefix = "tm_"
+
+// apiKeyDisplayLength is how much of a key is kept in clear to identify it
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// GenerateAPIKey returns a new random API key and the prefix stored to identify it
+func GenerateAPIKey() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	key := APIKeyPrefix + hex.EncodeToString(secret)
+	return key, key[:apiKeyDisplayLength], nil
+}
+
+// HashAPIKey returns the value stored for an API key. Keys are random, so a
+// fast hash is enough and lets keys be looked up directly.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey checks if a credential looks like an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
@@ -0,0 +1,32 @@
+package utils
+
+import "context"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request's correlation ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the correlation ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+type organizationKey struct{}
+
+// ContextWithOrganization returns a copy of ctx scoped to an organization.
+// Repositories only read and change that organization's data through it.
+func ContextWithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization ctx is scoped to. It
+// returns false for contexts that are not, such as those of sign-in flows and
+// background work, which may reach every organization's data.
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	organizationID, ok := ctx.Value(organizationKey{}).(string)
+	return organizationID, ok
+}
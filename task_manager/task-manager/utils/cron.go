@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the shorthands accepted in place of five fields
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule is a parsed cron expression. Each field is a bit set of the
+// values it matches.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// As in cron, when both day fields are restricted a day matching either
+	// one is enough
+	domRestricted, dowRestricted bool
+}
+
+// ParseCron parses a standard five-field cron expression: minute, hour, day of
+// month, month and day of week (0 or 7 is Sunday). Fields take *, values,
+// ranges such as 1-5, steps such as */15 and comma-separated lists of these.
+// @hourly, @daily, @weekly, @monthly and @yearly are accepted too.
+func ParseCron(spec string) (CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+
+	var s CronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return CronSchedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return CronSchedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return CronSchedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return CronSchedule{}, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return CronSchedule{}, fmt.Errorf("day of week: %w", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 is another name for Sunday
+	}
+	s.domRestricted = !strings.HasPrefix(fields[2], "*")
+	s.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseCronField returns the values a field matches as a bit set
+func parseCronField(field string, low, high int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+		}
+
+		first, last := low, high
+		if valueRange != "*" {
+			from, to, isRange := strings.Cut(valueRange, "-")
+			var err error
+			if first, err = cronValue(from, low, high); err != nil {
+				return 0, err
+			}
+			last = first
+			if isRange {
+				if last, err = cronValue(to, low, high); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				last = high // 5/15 means 5, 20, 35, ...
+			}
+			if last < first {
+				return 0, fmt.Errorf("invalid range %q", valueRange)
+			}
+		}
+
+		for value := first; value <= last; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+func cronValue(text string, low, high int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil || value < low || value > high {
+		return 0, fmt.Errorf("value %q must be between %d and %d", text, low, high)
+	}
+	return value, nil
+}
+
+// Next returns the first time after t that the schedule matches, in t's
+// location. It returns the zero time if the schedule never matches, e.g. on
+// February 30.
+func (s CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		previous := t
+		switch {
+		case s.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+		// An hour repeated when clocks go back can map to an earlier instant
+		if !t.After(previous) {
+			t = previous.Add(time.Hour).Truncate(time.Hour)
+		}
+	}
+	return time.Time{}
+}
+
+func (s CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
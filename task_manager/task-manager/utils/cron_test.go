@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 5, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day field may match when both are restricted
+		{"0 0 31 * 5", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		// Never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, schedule.Next(from))
+		})
+	}
+}
+
+func TestCronSchedule_NextAcrossDaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	schedule, err := ParseCron("30 2 * * *")
+	require.NoError(t, err)
+
+	// 02:30 does not exist on the day clocks go forward
+	next := schedule.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, berlin))
+	assert.Equal(t, time.Date(2026, 3, 30, 2, 30, 0, 0, berlin), next)
+
+	// and exists twice on the day they go back
+	next = schedule.Next(time.Date(2026, 10, 24, 12, 0, 0, 0, berlin))
+	assert.Equal(t, 25, next.Day())
+	assert.Equal(t, 2, next.Hour())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
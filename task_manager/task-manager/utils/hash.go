@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"task_manager/Domain/errors"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,14 +28,9 @@ func ValidatePassword(password string) error {
 
 // Common errors
 var (
-	ErrPasswordTooShort = &ValidationError{Message: "password must be at least 6 characters"}
+	ErrPasswordTooShort = &ValidationError{Field: "password", Message: "password must be at least 6 characters"}
 )
 
-// ValidationError represents validation errors
-type ValidationError struct {
-	Message string
-}
-
-func (e *ValidationError) Error() string {
-	return e.Message
-} 
\ No newline at end of file
+// ValidationError represents validation errors. It is the domain type, so the
+// API reports it with the offending field.
+type ValidationError = errors.ValidationError
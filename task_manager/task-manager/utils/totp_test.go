@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Base32 of the RFC 6238 SHA1 test key "12345678901234567890"
+const rfcTestSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B vectors, truncated to 6 digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfcTestSecret, TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "Unexpected code at %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	code, _ := TOTPCode(secret, TOTPStep(now))
+
+	step, ok := ValidateTOTP(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now), step)
+
+	// Codes from the neighbouring period are accepted to allow for clock drift
+	_, ok = ValidateTOTP(secret, code, now.Add(TOTPPeriod))
+	assert.True(t, ok)
+
+	// But not from further away
+	_, ok = ValidateTOTP(secret, code, now.Add(3*TOTPPeriod))
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("Task Manager", "john@example.com", "ABCDEF")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Task%20Manager:john@example.com?"))
+	assert.Contains(t, uri, "secret=ABCDEF")
+	assert.Contains(t, uri, "issuer=Task+Manager")
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, codes[0], 11)
+	assert.NotEqual(t, codes[0], codes[1])
+
+	// The dash and case do not matter when the code is typed back in
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+}
@@ -44,6 +44,20 @@ func ValidateName(name string) error {
 	return nil
 }
 
+// ValidateOrganizationName validates the name of a new organization
+func ValidateOrganizationName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrOrganizationRequired
+	}
+
+	if len(name) > 100 {
+		return ErrOrganizationTooLong
+	}
+
+	return nil
+}
+
 // ValidateTaskTitle validates task title
 func ValidateTaskTitle(title string) error {
 	title = strings.TrimSpace(title)
@@ -61,24 +75,26 @@ func ValidateTaskTitle(title string) error {
 // ValidateTaskStatus validates task status
 func ValidateTaskStatus(status string) error {
 	validStatuses := []string{"Pending", "In Progress", "Completed"}
-	
+
 	for _, validStatus := range validStatuses {
 		if status == validStatus {
 			return nil
 		}
 	}
-	
+
 	return ErrInvalidStatus
 }
 
 // Common validation errors
 var (
-	ErrEmailRequired   = &ValidationError{Message: "email is required"}
-	ErrInvalidEmail    = &ValidationError{Message: "invalid email format"}
-	ErrNameRequired    = &ValidationError{Message: "name is required"}
-	ErrNameTooShort    = &ValidationError{Message: "name must be at least 2 characters"}
-	ErrNameTooLong     = &ValidationError{Message: "name must be less than 50 characters"}
-	ErrTitleRequired   = &ValidationError{Message: "title is required"}
-	ErrTitleTooLong    = &ValidationError{Message: "title must be less than 100 characters"}
-	ErrInvalidStatus   = &ValidationError{Message: "invalid status"}
-) 
\ No newline at end of file
+	ErrEmailRequired        = &ValidationError{Field: "email", Message: "email is required"}
+	ErrInvalidEmail         = &ValidationError{Field: "email", Message: "invalid email format"}
+	ErrNameRequired         = &ValidationError{Field: "name", Message: "name is required"}
+	ErrNameTooShort         = &ValidationError{Field: "name", Message: "name must be at least 2 characters"}
+	ErrNameTooLong          = &ValidationError{Field: "name", Message: "name must be less than 50 characters"}
+	ErrOrganizationRequired = &ValidationError{Field: "organization", Message: "organization name is required"}
+	ErrOrganizationTooLong  = &ValidationError{Field: "organization", Message: "organization name must be less than 100 characters"}
+	ErrTitleRequired        = &ValidationError{Field: "title", Message: "title is required"}
+	ErrTitleTooLong         = &ValidationError{Field: "title", Message: "title must be less than 100 characters"}
+	ErrInvalidStatus        = &ValidationError{Field: "status", Message: "invalid status"}
+)
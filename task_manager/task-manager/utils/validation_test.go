@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,6 +63,14 @@ func TestValidateName(t *testing.T) {
 	}
 }
 
+func TestValidateOrganizationName(t *testing.T) {
+	assert.NoError(t, ValidateOrganizationName("Acme"))
+	assert.NoError(t, ValidateOrganizationName("X"))
+
+	assert.Equal(t, ErrOrganizationRequired, ValidateOrganizationName("   "))
+	assert.Equal(t, ErrOrganizationTooLong, ValidateOrganizationName(strings.Repeat("a", 101)))
+}
+
 func TestValidateTaskTitle(t *testing.T) {
 	// Test valid titles
 	validTitles := []string{
@@ -113,4 +122,4 @@ func TestValidateTaskStatus(t *testing.T) {
 		err := ValidateTaskStatus(status)
 		assert.Error(t, err, "Status should be invalid: %s", status)
 	}
-} 
\ No newline at end of file
+}